@@ -20,23 +20,106 @@ import (
 	"reflect"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 )
 
+// PluginState is the run state of an injected plugin.
+// +kubebuilder:validation:Enum=Running;Stopped
+type PluginState string
+
+// Plugin run states.
+const (
+	PluginRunning PluginState = "Running"
+	PluginStopped PluginState = "Stopped"
+)
+
 // BoardPluginInjectionParameters are the configurable fields of a BoardPluginInjection.
+// +kubebuilder:validation:XValidation:rule="!(has(self.ttl) && has(self.expiresAt))",message="at most one of ttl and expiresAt may be set"
+// +kubebuilder:validation:XValidation:rule="has(self.state) || !(has(self.parameters) || has(self.templateValues) || has(self.parametersFrom))",message="parameters, templateValues and parametersFrom are only passed when the plugin is started for state, which must be set"
 type BoardPluginInjectionParameters struct {
+	// BoardUuid is the IoTronic UUID of the target board.
 	// +kubebuilder:validation:Immutable
+	// +crossplane:generate:reference:type=Device
+	// +crossplane:generate:reference:extractor=DeviceUUID()
+	// +crossplane:generate:reference:refFieldName=BoardRef
+	// +crossplane:generate:reference:selectorFieldName=BoardSelector
 	BoardUuid string `json:"boardUuid,omitempty"`
+
+	// BoardRef references a Device to retrieve its board UUID.
+	// +optional
+	BoardRef *xpv1.Reference `json:"boardRef,omitempty"`
+
+	// BoardSelector selects a reference to a Device to retrieve its board UUID.
+	// +optional
+	BoardSelector *xpv1.Selector `json:"boardSelector,omitempty"`
+
+	// PluginUuid is the IoTronic UUID of the plugin to inject.
 	// +kubebuilder:validation:Immutable
+	// +crossplane:generate:reference:type=Plugin
+	// +crossplane:generate:reference:extractor=PluginUUID()
+	// +crossplane:generate:reference:refFieldName=PluginRef
+	// +crossplane:generate:reference:selectorFieldName=PluginSelector
 	PluginUuid string `json:"pluginUuid,omitempty"`
+
+	// PluginRef references a Plugin to retrieve its UUID.
+	// +optional
+	PluginRef *xpv1.Reference `json:"pluginRef,omitempty"`
+
+	// PluginSelector selects a reference to a Plugin to retrieve its UUID.
+	// +optional
+	PluginSelector *xpv1.Selector `json:"pluginSelector,omitempty"`
+
+	// State is the run state the plugin is kept in once injected. The
+	// plugin is left as Lightning Rod runs it when unset.
+	// +optional
+	State *PluginState `json:"state,omitempty"`
+
+	// Parameters are passed to the plugin when it is started for State,
+	// which they require. Changing them restarts a running plugin. String values may be Go templates, rendered
+	// against the board's Device, its Site and TemplateValues.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	Parameters runtime.RawExtension `json:"parameters,omitempty"`
+
+	// TemplateValues are read from ConfigMaps for the Parameters templates.
+	// +optional
+	TemplateValues []TemplateValue `json:"templateValues,omitempty"`
+
+	// ParametersFrom sets parameters from Secrets, after the Parameters
+	// templates are rendered. A change to one of the Secrets restarts a
+	// running plugin.
+	// +optional
+	ParametersFrom []ParameterFrom `json:"parametersFrom,omitempty"`
+
+	// TTL is how long after its creation the injection expires. An expired
+	// injection removes the plugin from the board and deletes itself.
+	// +optional
+	TTL *metav1.Duration `json:"ttl,omitempty"`
+
+	// ExpiresAt is when the injection expires, as an alternative to TTL.
+	// +optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
 }
 
 // BoardPluginInjectionObservation are the observable fields of a BoardPluginInjection.
 type BoardPluginInjectionObservation struct {
 	BoardUuid  string `json:"boardUuid,omitempty"`
 	PluginUuid string `json:"pluginUuid,omitempty"`
+
+	// Status is the plugin status IoTronic reports for the injection:
+	// injected, running or stopped.
+	Status string `json:"status,omitempty"`
+
+	// ParametersHash is the SHA-256 of the parameters the plugin was last
+	// started with. Parameters set from Secrets are covered by the UID and
+	// resourceVersion of their Secret, never by their value.
+	ParametersHash string `json:"parametersHash,omitempty"`
+
+	// ExpiresAt is when the injection expires, from ttl or expiresAt.
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
 }
 
 // A BoardPluginInjectionSpec defines the desired state of a BoardPluginInjection.
@@ -57,6 +140,8 @@ type BoardPluginInjectionStatus struct {
 // +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
 // +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
 // +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="STATUS",type="string",JSONPath=".status.atProvider.status"
+// +kubebuilder:printcolumn:name="EXPIRES-AT",type="string",JSONPath=".status.atProvider.expiresAt"
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,s4t}
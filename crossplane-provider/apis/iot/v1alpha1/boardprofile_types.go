@@ -0,0 +1,184 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// LabelBoardProfile is set on every BoardPluginInjection,
+// BoardServiceInjection and Webservice owned by a BoardProfile, to the
+// owner's name.
+const LabelBoardProfile = "iot.s4t.crossplane.io/board-profile"
+
+// BoardProfileParameters are the configurable fields of a BoardProfile.
+type BoardProfileParameters struct {
+	// DeviceSelector selects the Devices the profile applies to.
+	DeviceSelector metav1.LabelSelector `json:"deviceSelector"`
+
+	// Plugins are injected into every selected board.
+	// +listType=map
+	// +listMapKey=pluginRef
+	// +optional
+	Plugins []BoardProfilePlugin `json:"plugins,omitempty"`
+
+	// Services are exposed on every selected board.
+	// +listType=map
+	// +listMapKey=serviceRef
+	// +optional
+	Services []BoardProfileService `json:"services,omitempty"`
+
+	// Webservices are enabled on every selected board.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Webservices []BoardProfileWebservice `json:"webservices,omitempty"`
+}
+
+// A BoardProfilePlugin is a plugin a BoardProfile injects.
+// +kubebuilder:validation:XValidation:rule="has(self.state) || !(has(self.parameters) || has(self.templateValues) || has(self.parametersFrom))",message="parameters, templateValues and parametersFrom are only passed when the plugin is started for state, which must be set"
+type BoardProfilePlugin struct {
+	// PluginRef is the name of the Plugin to inject.
+	PluginRef string `json:"pluginRef"`
+
+	// State is the run state the plugin is kept in.
+	// +optional
+	State *PluginState `json:"state,omitempty"`
+
+	// Parameters are passed to the plugin when it is started for State,
+	// which they require. String values may be Go templates, rendered for
+	// each board.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	Parameters runtime.RawExtension `json:"parameters,omitempty"`
+
+	// TemplateValues are read from ConfigMaps for the Parameters templates.
+	// +optional
+	TemplateValues []TemplateValue `json:"templateValues,omitempty"`
+
+	// ParametersFrom sets parameters from Secrets.
+	// +optional
+	ParametersFrom []ParameterFrom `json:"parametersFrom,omitempty"`
+}
+
+// A BoardProfileService is a service a BoardProfile exposes.
+type BoardProfileService struct {
+	// ServiceRef is the name of the Service to expose.
+	ServiceRef string `json:"serviceRef"`
+}
+
+// A BoardProfileWebservice is a webservice a BoardProfile enables.
+type BoardProfileWebservice struct {
+	// Name is the name of the webservice on the board. It is part of the
+	// name of the Webservices the profile owns.
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+
+	// Port is the port the webservice listens on.
+	Port int `json:"port"`
+
+	// Secure serves the webservice over HTTPS.
+	// +optional
+	Secure bool `json:"secure,omitempty"`
+}
+
+// BoardProfileBoard is the conformance of one board to a BoardProfile.
+type BoardProfileBoard struct {
+	// Device is the name of the Device.
+	Device string `json:"device"`
+
+	// Conformant is whether every item of the profile is ready on the board.
+	Conformant bool `json:"conformant"`
+
+	// NotReady lists the items that are not, as kind/name, followed by the
+	// reason when there is one.
+	// +optional
+	NotReady []string `json:"notReady,omitempty"`
+}
+
+// BoardProfileObservation are the observable fields of a BoardProfile.
+type BoardProfileObservation struct {
+	// Matched is the number of Devices the profile applies to.
+	Matched int `json:"matched"`
+
+	// Conformant is the number of those whose boards carry every item.
+	Conformant int `json:"conformant"`
+
+	// Boards is the conformance of each matched board, sorted by Device.
+	// +optional
+	Boards []BoardProfileBoard `json:"boards,omitempty"`
+}
+
+// A BoardProfileSpec defines the desired state of a BoardProfile.
+type BoardProfileSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       BoardProfileParameters `json:"forProvider"`
+}
+
+// A BoardProfileStatus represents the observed state of a BoardProfile.
+type BoardProfileStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          BoardProfileObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A BoardProfile keeps every Device matching a label selector at the same set
+// of plugins, services and webservices, by owning one BoardPluginInjection,
+// BoardServiceInjection or Webservice per board and item.
+// +kubebuilder:printcolumn:name="MATCHED",type="integer",JSONPath=".status.atProvider.matched"
+// +kubebuilder:printcolumn:name="CONFORMANT",type="integer",JSONPath=".status.atProvider.conformant"
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,s4t}
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type BoardProfile struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   BoardProfileSpec   `json:"spec"`
+	Status BoardProfileStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// BoardProfileList contains a list of BoardProfile
+type BoardProfileList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []BoardProfile `json:"items"`
+}
+
+// BoardProfile type metadata.
+var (
+	BoardProfileKind             = reflect.TypeOf(BoardProfile{}).Name()
+	BoardProfileGroupKind        = schema.GroupKind{Group: Group, Kind: BoardProfileKind}.String()
+	BoardProfileKindAPIVersion   = BoardProfileKind + "." + SchemeGroupVersion.String()
+	BoardProfileGroupVersionKind = SchemeGroupVersion.WithKind(BoardProfileKind)
+)
+
+func init() {
+	SchemeBuilder.Register(&BoardProfile{}, &BoardProfileList{})
+}
@@ -26,17 +26,137 @@ import (
 )
 
 // BoardServiceInjectionParameters are the configurable fields of a BoardServiceInjection.
+// +kubebuilder:validation:XValidation:rule="!(has(self.ttl) && has(self.expiresAt))",message="at most one of ttl and expiresAt may be set"
 type BoardServiceInjectionParameters struct {
+	// BoardUuid is the IoTronic UUID of the target board.
 	// +kubebuilder:validation:Immutable
+	// +crossplane:generate:reference:type=Device
+	// +crossplane:generate:reference:extractor=DeviceUUID()
+	// +crossplane:generate:reference:refFieldName=BoardRef
+	// +crossplane:generate:reference:selectorFieldName=BoardSelector
 	BoardUuid string `json:"boardUuid,omitempty"`
+
+	// BoardRef references a Device to retrieve its board UUID.
+	// +optional
+	BoardRef *xpv1.Reference `json:"boardRef,omitempty"`
+
+	// BoardSelector selects a reference to a Device to retrieve its board UUID.
+	// +optional
+	BoardSelector *xpv1.Selector `json:"boardSelector,omitempty"`
+
+	// ServiceUuid is the IoTronic UUID of the service to expose.
 	// +kubebuilder:validation:Immutable
+	// +crossplane:generate:reference:type=Service
+	// +crossplane:generate:reference:extractor=ServiceUUID()
+	// +crossplane:generate:reference:refFieldName=ServiceRef
+	// +crossplane:generate:reference:selectorFieldName=ServiceSelector
 	ServiceUuid string `json:"serviceUuid,omitempty"`
+
+	// ServiceRef references a Service to retrieve its UUID.
+	// +optional
+	ServiceRef *xpv1.Reference `json:"serviceRef,omitempty"`
+
+	// ServiceSelector selects a reference to a Service to retrieve its UUID.
+	// +optional
+	ServiceSelector *xpv1.Selector `json:"serviceSelector,omitempty"`
+
+	// Route exposes the service through the cluster's ingress once it is
+	// exposed on the board. Traffic is forwarded to the wstun Service on the
+	// public port IoTronic assigned, unless a backend is given. Hosts are
+	// required.
+	// +kubebuilder:validation:XValidation:rule="has(self.hosts) && size(self.hosts) > 0",message="route.hosts is required"
+	// +optional
+	Route *Route `json:"route,omitempty"`
+
+	// ClusterService fronts the exposed service with a selector-less
+	// Kubernetes Service, so that cluster workloads can reach it without
+	// looking up the public port.
+	// +optional
+	ClusterService *ClusterService `json:"clusterService,omitempty"`
+
+	// TTL is how long after its creation the injection expires. An expired
+	// injection disables the service on the board and deletes itself.
+	// +optional
+	TTL *metav1.Duration `json:"ttl,omitempty"`
+
+	// ExpiresAt is when the injection expires, as an alternative to TTL.
+	// +optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+}
+
+// A ClusterService is a selector-less Kubernetes Service, and the
+// EndpointSlice backing it, that forwards the port of a board service to the
+// wstun host and public port IoTronic exposed it on.
+type ClusterService struct {
+	// Namespace the Service is created in.
+	// +kubebuilder:default=default
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Name of the Service. Defaults to <device>-<service>, after the Device
+	// and Service the injection refers to.
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=`^[a-z]([-a-z0-9]*[a-z0-9])?$`
+	// +optional
+	Name string `json:"name,omitempty"`
+}
+
+// ClusterServiceObservation records the Service generated for a
+// ClusterService.
+type ClusterServiceObservation struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+
+	// Port is the Service port, that of the board service.
+	Port int32 `json:"port"`
+
+	// Endpoint is the wstun address and public port the Service forwards to.
+	Endpoint string `json:"endpoint,omitempty"`
 }
 
 // BoardServiceInjectionObservation are the observable fields of a BoardServiceInjection.
 type BoardServiceInjectionObservation struct {
 	ServiceUuid string `json:"serviceUuid,omitempty"`
 	BoardUuid   string `json:"boardUuid,omitempty"`
+
+	// PublicPort is the port IoTronic assigned to the exposed service on the
+	// board's wstun server, typically in the 50000-50100 range.
+	PublicPort int `json:"publicPort,omitempty"`
+
+	// WstunHost is the wstun server through which the service is reachable.
+	WstunHost string `json:"wstunHost,omitempty"`
+
+	// Address is the host:port at which the exposed service is reachable.
+	Address string `json:"address,omitempty"`
+
+	// Scheme is the URL scheme of the exposed service, the protocol of its
+	// IoTronic Service. It is looked up when the service is exposed.
+	Scheme string `json:"scheme,omitempty"`
+
+	// BoardSession is the Lightning Rod session in which the service was
+	// last exposed or restored.
+	BoardSession string `json:"boardSession,omitempty"`
+
+	// BoardStatus is the board status seen when the exposure was last
+	// observed.
+	BoardStatus string `json:"boardStatus,omitempty"`
+
+	// RestoreCount is the number of ServiceRestore actions issued after the
+	// board's Lightning Rod reconnected.
+	RestoreCount int `json:"restoreCount,omitempty"`
+
+	// LastRestoreTime is when ServiceRestore was last issued.
+	LastRestoreTime *metav1.Time `json:"lastRestoreTime,omitempty"`
+
+	// Route is the VirtualService or Ingress generated for spec.forProvider.route.
+	Route *RouteObservation `json:"route,omitempty"`
+
+	// ClusterService is the Service generated for
+	// spec.forProvider.clusterService.
+	ClusterService *ClusterServiceObservation `json:"clusterService,omitempty"`
+
+	// ExpiresAt is when the injection expires, from ttl or expiresAt.
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
 }
 
 // A BoardServiceInjectionSpec defines the desired state of a BoardServiceInjection.
@@ -56,7 +176,9 @@ type BoardServiceInjectionStatus struct {
 // A BoardServiceInjection is an example API type.
 // +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
 // +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="ADDRESS",type="string",JSONPath=".status.atProvider.address"
 // +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="EXPIRES-AT",type="string",JSONPath=".status.atProvider.expiresAt"
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,s4t}
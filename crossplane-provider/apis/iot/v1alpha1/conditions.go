@@ -0,0 +1,137 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// TypeDegraded resources exist but some of what they manage is unhealthy.
+const TypeDegraded xpv1.ConditionType = "Degraded"
+
+// TypeDeletionDeferred resources are being deleted but hold off removing
+// what they manage from their board. It is separate from Ready, which the
+// managed reconciler sets to Deleting.
+const TypeDeletionDeferred xpv1.ConditionType = "DeletionDeferred"
+
+// Reasons a resource is not yet ready.
+const (
+	ReasonWaitingForBoard             xpv1.ConditionReason = "WaitingForBoard"
+	ReasonWaitingForMaintenanceWindow xpv1.ConditionReason = "WaitingForMaintenanceWindow"
+	ReasonInvalidParameters           xpv1.ConditionReason = "InvalidParameters"
+)
+
+// Reasons a resource is or is not degraded.
+const (
+	ReasonBoardsOffline xpv1.ConditionReason = "BoardsOffline"
+	ReasonBoardsOnline  xpv1.ConditionReason = "BoardsOnline"
+)
+
+// WaitingForBoard returns a condition that indicates the resource is waiting
+// for its board to come online before IoTronic can act on it.
+func WaitingForBoard(boardStatus string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               xpv1.TypeReady,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonWaitingForBoard,
+		Message:            fmt.Sprintf("board is %q, waiting for it to come online", boardStatus),
+	}
+}
+
+// WaitingForMaintenanceWindow returns a condition that indicates the
+// resource is holding off a disruptive operation on its board until the
+// board's maintenance window opens.
+func WaitingForMaintenanceWindow(window string, opens time.Time) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               xpv1.TypeReady,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonWaitingForMaintenanceWindow,
+		Message:            fmt.Sprintf("board is outside maintenance window %q, which opens at %s", window, opens.UTC().Format(time.RFC3339)),
+	}
+}
+
+// DeletionWaitingForMaintenanceWindow returns a condition that indicates
+// the resource being deleted is holding off removing what it manages from
+// its board until the board's maintenance window opens.
+func DeletionWaitingForMaintenanceWindow(window string, opens time.Time) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeDeletionDeferred,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonWaitingForMaintenanceWindow,
+		Message:            fmt.Sprintf("board is outside maintenance window %q, which opens at %s", window, opens.UTC().Format(time.RFC3339)),
+	}
+}
+
+// InvalidParameters returns a condition that indicates the resource's plugin
+// parameters could not be rendered for its board.
+func InvalidParameters(err error) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               xpv1.TypeReady,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonInvalidParameters,
+		Message:            err.Error(),
+	}
+}
+
+// Degraded returns a condition that indicates too many of the resource's
+// boards are offline.
+func Degraded(offline, members int) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeDegraded,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonBoardsOffline,
+		Message:            fmt.Sprintf("%d of %d boards are offline", offline, members),
+	}
+}
+
+// NotDegraded returns a condition that indicates enough of the resource's
+// boards are online.
+func NotDegraded() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeDegraded,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonBoardsOnline,
+	}
+}
+
+// RequestCondition returns the Ready condition of a Request in phase p:
+// available once it succeeded, and otherwise not ready for the phase as
+// reason.
+func RequestCondition(p RequestPhase, message string) xpv1.Condition {
+	if p == RequestSucceeded {
+		return xpv1.Available()
+	}
+	return xpv1.Condition{
+		Type:               xpv1.TypeReady,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             xpv1.ConditionReason(p),
+		Message:            message,
+	}
+}
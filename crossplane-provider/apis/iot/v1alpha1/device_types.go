@@ -58,12 +58,32 @@ type DeviceParameters struct {
 	Location  []Location `json:"location"`
 	Services  []string   `json:"services,omitempty"`
 	Plugins   []string   `json:"plugins,omitempty"`
+
+	// MaintenanceWindowRef references the MaintenanceWindow outside which
+	// disruptive operations on the board are deferred. It takes precedence
+	// over those of the board's Fleets and Site.
+	// +optional
+	MaintenanceWindowRef *xpv1.Reference `json:"maintenanceWindowRef,omitempty"`
 }
 
 // DeviceObservation are the observable fields of a Device.
 type DeviceObservation struct {
 	Code string `json:"code,omitempty"`
 	Uuid string `json:"uuid,omitempty"`
+	// Session is the current Lightning Rod session of the board.
+	Session string `json:"session,omitempty"`
+
+	// Location is the latest location reported by the board.
+	Location *Location `json:"location,omitempty"`
+
+	// GeofenceSite is the Site whose geofence contains Location, also set as
+	// the Device's geofence-site label. It is empty when Location lies
+	// outside every geofence.
+	GeofenceSite string `json:"geofenceSite,omitempty"`
+
+	// OutsideGeofences is true when sites declare geofences but none of them
+	// contains Location.
+	OutsideGeofences bool `json:"outsideGeofences,omitempty"`
 }
 
 // A DeviceSpec defines the desired state of a Device.
@@ -86,6 +106,8 @@ type DeviceStatus struct {
 // +kubebuilder:printcolumn:name="Board Name",type=string,JSONPath=".spec.Name"
 // +kubebuilder:printcolumn:name="Board Status",type=string,JSONPath=".spec.Status"
 // +kubebuilder:printcolumn:name="Board Location",type=string,JSONPath=".spec.Location"
+// +kubebuilder:printcolumn:name="SITE",type=string,JSONPath=".metadata.labels.site"
+// +kubebuilder:printcolumn:name="GEOFENCE",type=string,JSONPath=".metadata.labels.geofence-site"
 // +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
 // +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
 // +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
@@ -33,12 +33,45 @@ type FleetParameters struct {
 	Description string               `json:"description,omitempty"`
 	Project     string               `json:"project,omitempty"`
 	Extra       runtime.RawExtension `json:"extra,omitempty"`
+
+	// DeviceRefs lists Devices whose boards belong to the fleet.
+	// +optional
+	DeviceRefs []xpv1.Reference `json:"deviceRefs,omitempty"`
+
+	// DeviceSelector adds the Devices matching these labels to the fleet.
+	// When neither DeviceRefs nor DeviceSelector is set, membership is left
+	// as it is in IoTronic.
+	// +optional
+	DeviceSelector *metav1.LabelSelector `json:"deviceSelector,omitempty"`
+
+	// DegradedOfflinePercent is the percentage of member boards that may be
+	// offline before the fleet is reported Degraded.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:default=25
+	// +optional
+	DegradedOfflinePercent *int `json:"degradedOfflinePercent,omitempty"`
+
+	// MaintenanceWindowRef references the MaintenanceWindow outside which
+	// disruptive operations on member boards are deferred. It takes
+	// precedence over their Site's.
+	// +optional
+	MaintenanceWindowRef *xpv1.Reference `json:"maintenanceWindowRef,omitempty"`
 }
 
 // FleetObservation are the observable fields of a Fleet.
 type FleetObservation struct {
 	Uuid string `json:"uuid,omitempty"`
 	Name string `json:"name,omitempty"`
+
+	// Members is the number of boards in the fleet.
+	Members int `json:"members"`
+
+	// Online is the number of member boards whose Lightning Rod is connected.
+	Online int `json:"online"`
+
+	// Offline is the number of member boards that are not online.
+	Offline int `json:"offline"`
 }
 
 // A FleetSpec defines the desired state of a Fleet.
@@ -56,6 +89,9 @@ type FleetStatus struct {
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="MEMBERS",type="integer",JSONPath=".status.atProvider.members"
+// +kubebuilder:printcolumn:name="ONLINE",type="integer",JSONPath=".status.atProvider.online"
+// +kubebuilder:printcolumn:name="DEGRADED",type="string",JSONPath=".status.conditions[?(@.type=='Degraded')].status"
 // +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
 // +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
 // +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
@@ -0,0 +1,123 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// LabelFleetInjection is set on every BoardPluginInjection and
+// BoardServiceInjection owned by a FleetInjection, to the owner's name.
+const LabelFleetInjection = "iot.s4t.crossplane.io/fleet-injection"
+
+// FleetInjectionParameters are the configurable fields of a FleetInjection.
+// +kubebuilder:validation:XValidation:rule="has(self.pluginRef) != has(self.serviceRef)",message="exactly one of pluginRef and serviceRef must be set"
+// +kubebuilder:validation:XValidation:rule="has(self.deviceSelector) != has(self.fleetRef)",message="exactly one of deviceSelector and fleetRef must be set"
+type FleetInjectionParameters struct {
+	// PluginRef references the Plugin to inject into every matching board.
+	// +optional
+	PluginRef *xpv1.Reference `json:"pluginRef,omitempty"`
+
+	// ServiceRef references the Service to expose on every matching board.
+	// +optional
+	ServiceRef *xpv1.Reference `json:"serviceRef,omitempty"`
+
+	// DeviceSelector selects the target Devices by label.
+	// +optional
+	DeviceSelector *metav1.LabelSelector `json:"deviceSelector,omitempty"`
+
+	// FleetRef targets the Devices whose boards belong to the referenced
+	// Fleet in IoTronic.
+	// +optional
+	FleetRef *xpv1.Reference `json:"fleetRef,omitempty"`
+}
+
+// FleetInjectionObservation are the observable fields of a FleetInjection.
+type FleetInjectionObservation struct {
+	// Matched is the number of Devices currently targeted.
+	Matched int `json:"matched"`
+
+	// Ready is the number of boards whose injection is Ready.
+	Ready int `json:"ready"`
+
+	// Waiting is the number of boards whose injection is waiting for the
+	// board to come online or for its maintenance window to open.
+	Waiting int `json:"waiting"`
+
+	// Failed is the number of boards whose injection failed to sync.
+	Failed int `json:"failed"`
+}
+
+// A FleetInjectionSpec defines the desired state of a FleetInjection.
+type FleetInjectionSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       FleetInjectionParameters `json:"forProvider"`
+}
+
+// A FleetInjectionStatus represents the observed state of a FleetInjection.
+type FleetInjectionStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          FleetInjectionObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A FleetInjection injects a Plugin or exposes a Service on every Device
+// matching a label selector or belonging to a Fleet, by owning one
+// BoardPluginInjection or BoardServiceInjection per board.
+// +kubebuilder:printcolumn:name="MATCHED",type="integer",JSONPath=".status.atProvider.matched"
+// +kubebuilder:printcolumn:name="BOARDS-READY",type="integer",JSONPath=".status.atProvider.ready"
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,s4t}
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type FleetInjection struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   FleetInjectionSpec   `json:"spec"`
+	Status FleetInjectionStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// FleetInjectionList contains a list of FleetInjection
+type FleetInjectionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []FleetInjection `json:"items"`
+}
+
+// FleetInjection type metadata.
+var (
+	FleetInjectionKind             = reflect.TypeOf(FleetInjection{}).Name()
+	FleetInjectionGroupKind        = schema.GroupKind{Group: Group, Kind: FleetInjectionKind}.String()
+	FleetInjectionKindAPIVersion   = FleetInjectionKind + "." + SchemeGroupVersion.String()
+	FleetInjectionGroupVersionKind = SchemeGroupVersion.WithKind(FleetInjectionKind)
+)
+
+func init() {
+	SchemeBuilder.Register(&FleetInjection{}, &FleetInjectionList{})
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// An IPPoolSpec defines the addresses Ports on a network may use.
+// +kubebuilder:validation:XValidation:rule="has(self.network) != has(self.networkRef)",message="exactly one of network and networkRef must be set"
+type IPPoolSpec struct {
+	// Network is the Port network this pool serves. At most one pool may
+	// serve a network.
+	// +optional
+	Network string `json:"network,omitempty"`
+
+	// NetworkRef references the Network this pool serves, matching Ports
+	// that reference the same Network.
+	// +optional
+	NetworkRef *xpv1.Reference `json:"networkRef,omitempty"`
+
+	// CIDR is the subnet addresses are allocated from, e.g. 10.0.0.0/24.
+	// +kubebuilder:validation:Required
+	CIDR string `json:"cidr"`
+
+	// Gateway is the address of the network's gateway. It is never
+	// allocated.
+	// +optional
+	Gateway string `json:"gateway,omitempty"`
+
+	// Exclusions are addresses or CIDRs within the pool that are never
+	// allocated.
+	// +optional
+	Exclusions []string `json:"exclusions,omitempty"`
+}
+
+// An IPAllocation records the address held by a Port.
+type IPAllocation struct {
+	// IP is the allocated address.
+	IP string `json:"ip"`
+
+	// Port is the name of the Port holding the address.
+	Port string `json:"port"`
+}
+
+// An IPPoolStatus records the addresses allocated from an IPPool.
+type IPPoolStatus struct {
+	// Allocations are the addresses currently held by Ports.
+	// +optional
+	Allocations []IPAllocation `json:"allocations,omitempty"`
+
+	// Allocated is the number of addresses held by Ports.
+	Allocated int `json:"allocated"`
+
+	// Available is the number of addresses that can still be allocated.
+	Available int64 `json:"available"`
+}
+
+// +kubebuilder:object:root=true
+
+// An IPPool is a range of addresses allocated to the Ports of a network.
+// Ports that leave ip empty are given the next free address; addresses are
+// released when the Port is deleted.
+// +kubebuilder:printcolumn:name="NETWORK",type="string",JSONPath=".spec.network"
+// +kubebuilder:printcolumn:name="NETWORK-REF",type="string",JSONPath=".spec.networkRef.name"
+// +kubebuilder:printcolumn:name="CIDR",type="string",JSONPath=".spec.cidr"
+// +kubebuilder:printcolumn:name="ALLOCATED",type="integer",JSONPath=".status.allocated"
+// +kubebuilder:printcolumn:name="AVAILABLE",type="integer",JSONPath=".status.available"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,s4t}
+type IPPool struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   IPPoolSpec   `json:"spec"`
+	Status IPPoolStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// IPPoolList contains a list of IPPool
+type IPPoolList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []IPPool `json:"items"`
+}
+
+// IPPool type metadata.
+var (
+	IPPoolKind             = reflect.TypeOf(IPPool{}).Name()
+	IPPoolGroupKind        = schema.GroupKind{Group: Group, Kind: IPPoolKind}.String()
+	IPPoolKindAPIVersion   = IPPoolKind + "." + SchemeGroupVersion.String()
+	IPPoolGroupVersionKind = SchemeGroupVersion.WithKind(IPPoolKind)
+)
+
+func init() {
+	SchemeBuilder.Register(&IPPool{}, &IPPoolList{})
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// AnnotationMaintenanceOverride, set to "true" on an injection, Request,
+// PluginSchedule or Plugin, lets it act on boards outside their maintenance
+// window, for emergency changes.
+const AnnotationMaintenanceOverride = "iot.s4t.crossplane.io/maintenance-override"
+
+// A MaintenanceWindowSchedule is a recurring period during which disruptive
+// operations are allowed.
+type MaintenanceWindowSchedule struct {
+	// Start is when the window opens, in cron format, e.g. "0 2 * * 6".
+	// +kubebuilder:validation:MinLength=1
+	Start string `json:"start"`
+
+	// Duration is how long the window stays open, e.g. "4h".
+	Duration metav1.Duration `json:"duration"`
+}
+
+// MaintenanceWindowParameters are the configurable fields of a
+// MaintenanceWindow.
+type MaintenanceWindowParameters struct {
+	// Schedules are the recurring periods the window is open. It is open
+	// while any of them is.
+	// +kubebuilder:validation:MinItems=1
+	Schedules []MaintenanceWindowSchedule `json:"schedules"`
+
+	// TimeZone is the IANA time zone the schedules are interpreted in.
+	// Defaults to UTC.
+	// +optional
+	TimeZone *string `json:"timeZone,omitempty"`
+}
+
+// MaintenanceWindowObservation are the observable fields of a
+// MaintenanceWindow.
+type MaintenanceWindowObservation struct {
+	// Open is true while disruptive operations are allowed.
+	Open bool `json:"open"`
+
+	// OpenUntil is when the window closes, while it is open.
+	OpenUntil *metav1.Time `json:"openUntil,omitempty"`
+
+	// NextOpen is when the window next opens.
+	NextOpen *metav1.Time `json:"nextOpen,omitempty"`
+
+	// References counts the Devices, Fleets and Sites referencing the
+	// window.
+	References int `json:"references,omitempty"`
+}
+
+// A MaintenanceWindowSpec defines the desired state of a MaintenanceWindow.
+type MaintenanceWindowSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       MaintenanceWindowParameters `json:"forProvider"`
+}
+
+// A MaintenanceWindowStatus represents the observed state of a
+// MaintenanceWindow.
+type MaintenanceWindowStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          MaintenanceWindowObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A MaintenanceWindow is a recurring schedule outside which plugin
+// injection and removal, service disabling and board actions are deferred
+// for the Devices, Fleets and Sites that reference it.
+// +kubebuilder:printcolumn:name="OPEN",type="boolean",JSONPath=".status.atProvider.open"
+// +kubebuilder:printcolumn:name="OPEN-UNTIL",type="date",JSONPath=".status.atProvider.openUntil"
+// +kubebuilder:printcolumn:name="NEXT-OPEN",type="date",JSONPath=".status.atProvider.nextOpen"
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,s4t}
+type MaintenanceWindow struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MaintenanceWindowSpec   `json:"spec"`
+	Status MaintenanceWindowStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// MaintenanceWindowList contains a list of MaintenanceWindow
+type MaintenanceWindowList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MaintenanceWindow `json:"items"`
+}
+
+// MaintenanceWindow type metadata.
+var (
+	MaintenanceWindowKind             = reflect.TypeOf(MaintenanceWindow{}).Name()
+	MaintenanceWindowGroupKind        = schema.GroupKind{Group: Group, Kind: MaintenanceWindowKind}.String()
+	MaintenanceWindowKindAPIVersion   = MaintenanceWindowKind + "." + SchemeGroupVersion.String()
+	MaintenanceWindowGroupVersionKind = SchemeGroupVersion.WithKind(MaintenanceWindowKind)
+)
+
+func init() {
+	SchemeBuilder.Register(&MaintenanceWindow{}, &MaintenanceWindowList{})
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Network overlay types.
+const (
+	NetworkTypeVLAN  = "vlan"
+	NetworkTypeVXLAN = "vxlan"
+)
+
+// NetworkParameters are the configurable fields of a Network.
+// +kubebuilder:validation:XValidation:rule="self.type != 'vlan' || !has(self.segmentationId) || self.segmentationId <= 4094",message="a VLAN ID must be between 1 and 4094"
+// +kubebuilder:validation:XValidation:rule="has(self.segmentationId) == has(oldSelf.segmentationId)",message="segmentationId cannot be added or removed"
+type NetworkParameters struct {
+	// +kubebuilder:validation:Immutable
+	Uuid string `json:"uuid,omitempty"`
+
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// CIDR is the subnet of the network, e.g. 10.0.0.0/24.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="cidr is immutable"
+	CIDR string `json:"cidr"`
+
+	// Type is the overlay carrying the network.
+	// +kubebuilder:validation:Enum=vlan;vxlan
+	// +kubebuilder:default=vxlan
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="type is immutable"
+	Type string `json:"type,omitempty"`
+
+	// SegmentationID is the VLAN or VXLAN ID of the network. IoTronic picks
+	// one when it is not set.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=16777215
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="segmentationId is immutable"
+	// +optional
+	SegmentationID int `json:"segmentationId,omitempty"`
+
+	// Project is the IoTronic project the network belongs to.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="project is immutable"
+	// +optional
+	Project string `json:"project,omitempty"`
+}
+
+// NetworkObservation are the observable fields of a Network.
+type NetworkObservation struct {
+	Uuid           string `json:"uuid,omitempty"`
+	SegmentationID int    `json:"segmentationId,omitempty"`
+
+	// Ports is the number of Ports attached to the network.
+	Ports int `json:"ports,omitempty"`
+}
+
+// A NetworkSpec defines the desired state of a Network.
+type NetworkSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       NetworkParameters `json:"forProvider"`
+}
+
+// A NetworkStatus represents the observed state of a Network.
+type NetworkStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          NetworkObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A Network is an IoTronic virtual network boards are attached to through
+// Ports. It is not deleted while Ports are still attached.
+// +kubebuilder:printcolumn:name="CIDR",type="string",JSONPath=".spec.forProvider.cidr"
+// +kubebuilder:printcolumn:name="TYPE",type="string",JSONPath=".spec.forProvider.type"
+// +kubebuilder:printcolumn:name="SEGMENT",type="integer",JSONPath=".status.atProvider.segmentationId"
+// +kubebuilder:printcolumn:name="PORTS",type="integer",JSONPath=".status.atProvider.ports"
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,s4t}
+type Network struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NetworkSpec   `json:"spec"`
+	Status NetworkStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// NetworkList contains a list of Network
+type NetworkList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Network `json:"items"`
+}
+
+// Network type metadata.
+var (
+	NetworkKind             = reflect.TypeOf(Network{}).Name()
+	NetworkGroupKind        = schema.GroupKind{Group: Group, Kind: NetworkKind}.String()
+	NetworkKindAPIVersion   = NetworkKind + "." + SchemeGroupVersion.String()
+	NetworkGroupVersionKind = SchemeGroupVersion.WithKind(NetworkKind)
+)
+
+func init() {
+	SchemeBuilder.Register(&Network{}, &NetworkList{})
+}
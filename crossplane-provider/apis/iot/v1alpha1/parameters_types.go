@@ -0,0 +1,64 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// LabelParameterSource, set to "true" on a Secret, lets plugin parameters be
+// set from it with parametersFrom. Other Secrets are not read, so that a
+// resource cannot send any Secret the provider can read to a board.
+const LabelParameterSource = "iot.s4t.crossplane.io/parameter-source"
+
+// A TemplateValue is a value that plugin parameter templates refer to as
+// .Values.<name>.
+type TemplateValue struct {
+	// Name of the value in templates.
+	// +kubebuilder:validation:Pattern=`^[A-Za-z_][A-Za-z0-9_]*$`
+	Name string `json:"name"`
+
+	// ConfigMapKeyRef reads the value from a ConfigMap.
+	ConfigMapKeyRef ConfigMapKeySelector `json:"configMapKeyRef"`
+}
+
+// A ConfigMapKeySelector selects a ConfigMap, or a key of it.
+type ConfigMapKeySelector struct {
+	// Name of the ConfigMap.
+	Name string `json:"name"`
+
+	// Namespace of the ConfigMap.
+	Namespace string `json:"namespace"`
+
+	// Key of the ConfigMap to read. Without it the value is the whole data
+	// of the ConfigMap, a map of keys to values.
+	// +optional
+	Key string `json:"key,omitempty"`
+}
+
+// A ParameterFrom sets a plugin parameter from a Secret, so that it need not
+// be written into the resource.
+type ParameterFrom struct {
+	// Name of the parameter. Dots separate the keys of nested objects, e.g.
+	// "cloud.apiKey".
+	// +kubebuilder:validation:Pattern=`^[^.]+(\.[^.]+)*$`
+	Name string `json:"name"`
+
+	// SecretKeyRef reads the value from a Secret key. The Secret must be
+	// labelled iot.s4t.crossplane.io/parameter-source: "true".
+	SecretKeyRef xpv1.SecretKeySelector `json:"secretKeyRef"`
+}
@@ -33,13 +33,141 @@ type PluginParameters struct {
 	Name       string               `json:"name"`
 	Parameters runtime.RawExtension `json:"parameters"`
 	Code       string               `json:"code"`
+
+	// ParametersSchema is a JSON Schema the parameters of the plugin must
+	// match: its default Parameters, and those of every injection, call and
+	// schedule of it. References ($ref) are not supported.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Type=object
+	// +optional
+	ParametersSchema *runtime.RawExtension `json:"parametersSchema,omitempty"`
+
+	// ParametersFrom sets parameters from Secrets, so that credentials the
+	// plugin needs are not stored in the Plugin. A change to one of the
+	// Secrets is pushed to IoTronic, and rolled out when Rollout is set.
+	// +optional
+	ParametersFrom []ParameterFrom `json:"parametersFrom,omitempty"`
+
 	// +kubebuilder:validation:Immutable
 	Version string `json:"version,omitempty"`
+
+	// Rollout, when set, redeploys code and parameter changes to the boards
+	// the plugin is injected into, batch by batch. Without it a change is only
+	// patched into IoTronic and boards keep running the code they have.
+	// +optional
+	Rollout *PluginRollout `json:"rollout,omitempty"`
+}
+
+// PluginRollout configures the progressive redeployment of a Plugin.
+type PluginRollout struct {
+	// BatchSize is the number of boards redeployed at once.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=1
+	// +optional
+	BatchSize int `json:"batchSize,omitempty"`
+
+	// MaxUnavailable is the number of boards that may fail the health gate
+	// before the rollout is halted.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxUnavailable int `json:"maxUnavailable,omitempty"`
+
+	// Pause is how long to wait after a batch passes the health gate before
+	// starting the next one.
+	// +optional
+	Pause *metav1.Duration `json:"pause,omitempty"`
+
+	// CanarySelector selects, by Device label, the boards redeployed first as
+	// a batch of their own.
+	// +optional
+	CanarySelector *metav1.LabelSelector `json:"canarySelector,omitempty"`
+
+	// HealthCheck gates each batch.
+	// +optional
+	HealthCheck PluginHealthCheck `json:"healthCheck,omitempty"`
+
+	// AutoRollback restores the previous code and parameters on every board
+	// already touched when the rollout halts.
+	// +kubebuilder:default=true
+	// +optional
+	AutoRollback *bool `json:"autoRollback,omitempty"`
+}
+
+// PluginHealthCheck decides whether a redeployed board is healthy. A board is
+// healthy once it reports the plugin as running or, when Call is set, once a
+// PluginCall returns the expected result.
+type PluginHealthCheck struct {
+	// Call is a PluginCall issued to each redeployed board.
+	// +optional
+	Call *PluginCallCheck `json:"call,omitempty"`
+
+	// Timeout is how long a board may take to become healthy before it
+	// counts as failed. Defaults to 5m.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// PluginCallCheck is a PluginCall whose response must contain ExpectedResult.
+type PluginCallCheck struct {
+	// Parameters are passed to the plugin call.
+	// +optional
+	Parameters runtime.RawExtension `json:"parameters,omitempty"`
+
+	// ExpectedResult must appear in the call's response.
+	ExpectedResult string `json:"expectedResult"`
+}
+
+// Rollout phases.
+const (
+	RolloutProgressing = "Progressing"
+	RolloutCompleted   = "Completed"
+	RolloutHalted      = "Halted"
+	RolloutRolledBack  = "RolledBack"
+)
+
+// PluginRolloutStatus is the observed progress of a Plugin rollout.
+type PluginRolloutStatus struct {
+	// Revision identifies the code and parameters being rolled out.
+	Revision string `json:"revision,omitempty"`
+
+	// StableRevision identifies the code and parameters last fully rolled
+	// out.
+	StableRevision string `json:"stableRevision,omitempty"`
+
+	// StableCode and StableParameters are what IoTronic held before the
+	// current rollout started, restored on rollback.
+	StableCode       string               `json:"stableCode,omitempty"`
+	StableParameters runtime.RawExtension `json:"stableParameters,omitempty"`
+
+	// Phase is one of Progressing, Completed, Halted or RolledBack.
+	Phase string `json:"phase,omitempty"`
+
+	// Batch lists the board UUIDs redeployed in the current batch, awaiting
+	// the health gate.
+	Batch []string `json:"batch,omitempty"`
+
+	// BatchStartTime is when the current batch was redeployed.
+	BatchStartTime *metav1.Time `json:"batchStartTime,omitempty"`
+
+	// NextBatchTime is when the next batch may start.
+	NextBatchTime *metav1.Time `json:"nextBatchTime,omitempty"`
+
+	// Updated lists the board UUIDs that passed the health gate.
+	Updated []string `json:"updated,omitempty"`
+
+	// Failed lists the board UUIDs that failed the health gate.
+	Failed []string `json:"failed,omitempty"`
+
+	// Message describes the last rollout step.
+	Message string `json:"message,omitempty"`
 }
 
 // PluginObservation are the observable fields of a Plugin.
 type PluginObservation struct {
 	Name string `json:"name"`
+
+	// Rollout is the progress of the current or last rollout.
+	Rollout *PluginRolloutStatus `json:"rollout,omitempty"`
 }
 
 // A PluginSpec defines the desired state of a Plugin.
@@ -57,6 +185,7 @@ type PluginStatus struct {
 // +kubebuilder:object:root=true
 // +kubebuilder:printcolumn:name="Name",type=string,JSONPath=".spec.Name"
 // A Plugin is an example API type.
+// +kubebuilder:printcolumn:name="ROLLOUT",type="string",JSONPath=".status.atProvider.rollout.phase"
 // +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
 // +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
 // +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
@@ -0,0 +1,293 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// PluginScheduleActionType is what a PluginSchedule does to the plugin.
+// +kubebuilder:validation:Enum=Start;Stop;Restart;Call
+type PluginScheduleActionType string
+
+// Plugin schedule actions.
+const (
+	PluginScheduleStart   PluginScheduleActionType = "Start"
+	PluginScheduleStop    PluginScheduleActionType = "Stop"
+	PluginScheduleRestart PluginScheduleActionType = "Restart"
+	PluginScheduleCall    PluginScheduleActionType = "Call"
+)
+
+// ConcurrencyPolicy is what a PluginSchedule does when a run is due while
+// an earlier one is still running.
+// +kubebuilder:validation:Enum=Allow;Forbid;Replace
+type ConcurrencyPolicy string
+
+// Concurrency policies.
+const (
+	// AllowConcurrent starts the run alongside the running ones.
+	AllowConcurrent ConcurrencyPolicy = "Allow"
+	// ForbidConcurrent skips the run.
+	ForbidConcurrent ConcurrencyPolicy = "Forbid"
+	// ReplaceConcurrent stops the plugin on the boards of the running ones,
+	// stops tracking them and starts the run.
+	ReplaceConcurrent ConcurrencyPolicy = "Replace"
+)
+
+// MissedRunPolicy is what a PluginSchedule does about runs that could not
+// start within their starting deadline, for example because the provider was
+// down.
+// +kubebuilder:validation:Enum=RunOnce;Skip
+type MissedRunPolicy string
+
+// Missed run policies.
+const (
+	// RunOnceMissed starts a single run for the most recent missed time.
+	RunOnceMissed MissedRunPolicy = "RunOnce"
+	// SkipMissed waits for the next scheduled time.
+	SkipMissed MissedRunPolicy = "Skip"
+)
+
+// PluginScheduleTarget selects the boards a PluginSchedule acts on. Exactly
+// one of its fields must be set.
+// +kubebuilder:validation:XValidation:rule="(has(self.deviceRef) ? 1 : 0) + (has(self.deviceSelector) ? 1 : 0) + (has(self.fleetRef) ? 1 : 0) == 1",message="exactly one of deviceRef, deviceSelector and fleetRef must be set"
+type PluginScheduleTarget struct {
+	// DeviceRef targets the board of a single Device.
+	// +optional
+	DeviceRef *xpv1.Reference `json:"deviceRef,omitempty"`
+
+	// DeviceSelector targets the boards of the Devices matching a label
+	// selector.
+	// +optional
+	DeviceSelector *metav1.LabelSelector `json:"deviceSelector,omitempty"`
+
+	// FleetRef targets the boards belonging to a Fleet in IoTronic.
+	// +optional
+	FleetRef *xpv1.Reference `json:"fleetRef,omitempty"`
+}
+
+// PluginScheduleAction is the plugin action a PluginSchedule performs.
+// +kubebuilder:validation:XValidation:rule="!(has(self.parameters) || has(self.parametersFrom)) || self.type == 'Call'",message="parameters are only valid for the Call action"
+type PluginScheduleAction struct {
+	// Type of the action: Start, Stop, Restart or Call.
+	Type PluginScheduleActionType `json:"type"`
+
+	// Parameters passed to the plugin by a Call. String values may be Go
+	// templates, rendered for each board against its Device, its Site and
+	// TemplateValues.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	Parameters *runtime.RawExtension `json:"parameters,omitempty"`
+
+	// TemplateValues are read from ConfigMaps for the Parameters templates.
+	// +optional
+	TemplateValues []TemplateValue `json:"templateValues,omitempty"`
+
+	// ParametersFrom sets parameters of a Call from Secrets, read when the
+	// call is made.
+	// +optional
+	ParametersFrom []ParameterFrom `json:"parametersFrom,omitempty"`
+}
+
+// PluginScheduleParameters are the configurable fields of a PluginSchedule.
+type PluginScheduleParameters struct {
+	// Schedule in cron format, e.g. "0 3 * * *" or "@hourly".
+	// +kubebuilder:validation:MinLength=1
+	Schedule string `json:"schedule"`
+
+	// TimeZone is the IANA time zone the schedule is interpreted in.
+	// Defaults to UTC.
+	// +optional
+	TimeZone *string `json:"timeZone,omitempty"`
+
+	// Suspend stops scheduling new runs. Runs in progress are still
+	// tracked.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+
+	// PluginRef references the Plugin to act on. It must be injected into
+	// the target boards.
+	PluginRef xpv1.Reference `json:"pluginRef"`
+
+	// Target selects the boards to act on.
+	Target PluginScheduleTarget `json:"target"`
+
+	// Action is the plugin action to perform.
+	Action PluginScheduleAction `json:"action"`
+
+	// ConcurrencyPolicy is what to do when a run is due while an earlier one
+	// is still running.
+	// +kubebuilder:default=Allow
+	// +optional
+	ConcurrencyPolicy ConcurrencyPolicy `json:"concurrencyPolicy,omitempty"`
+
+	// StartingDeadlineSeconds is how late a run may start before it counts
+	// as missed.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=60
+	// +optional
+	StartingDeadlineSeconds *int64 `json:"startingDeadlineSeconds,omitempty"`
+
+	// RunTimeoutSeconds is how long the boards of a run may take to report
+	// an outcome. Boards that have not reported one by then, for example
+	// because IoTronic lost their request, fail.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=3600
+	// +optional
+	RunTimeoutSeconds *int64 `json:"runTimeoutSeconds,omitempty"`
+
+	// MissedRunPolicy is what to do about missed runs.
+	// +kubebuilder:default=RunOnce
+	// +optional
+	MissedRunPolicy MissedRunPolicy `json:"missedRunPolicy,omitempty"`
+
+	// SuccessfulRunsHistoryLimit is how many succeeded runs are kept in
+	// status.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default=3
+	// +optional
+	SuccessfulRunsHistoryLimit *int32 `json:"successfulRunsHistoryLimit,omitempty"`
+
+	// FailedRunsHistoryLimit is how many failed or replaced runs are kept in
+	// status.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default=1
+	// +optional
+	FailedRunsHistoryLimit *int32 `json:"failedRunsHistoryLimit,omitempty"`
+}
+
+// PluginScheduleRunPhase is where a run of a PluginSchedule is.
+type PluginScheduleRunPhase string
+
+// Run phases.
+const (
+	RunRunning   PluginScheduleRunPhase = "Running"
+	RunSucceeded PluginScheduleRunPhase = "Succeeded"
+	RunFailed    PluginScheduleRunPhase = "Failed"
+	// RunReplaced runs were still running when the ReplaceConcurrent policy
+	// started a newer one. The plugin is stopped on their running boards and
+	// they are no longer tracked.
+	RunReplaced PluginScheduleRunPhase = "Replaced"
+)
+
+// A PluginScheduleRunBoard is the outcome of a run on one board.
+type PluginScheduleRunBoard struct {
+	BoardUuid string `json:"boardUuid"`
+
+	// RequestUuid is the IoTronic request tracking the action, if IoTronic
+	// handled it asynchronously.
+	RequestUuid string `json:"requestUuid,omitempty"`
+
+	// Result is SUCCESS, ERROR, WARNING or RUNNING, as for Results.
+	Result  string `json:"result,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// A PluginScheduleRun records one run of a PluginSchedule.
+type PluginScheduleRun struct {
+	// ScheduledTime is the time the run was scheduled for.
+	ScheduledTime metav1.Time `json:"scheduledTime"`
+
+	StartTime      *metav1.Time `json:"startTime,omitempty"`
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	Phase PluginScheduleRunPhase `json:"phase"`
+
+	// Boards are the per-board outcomes of the run.
+	Boards []PluginScheduleRunBoard `json:"boards,omitempty"`
+}
+
+// PluginScheduleObservation are the observable fields of a PluginSchedule.
+type PluginScheduleObservation struct {
+	// LastScheduleTime is the most recent time a run was due, whether it
+	// was started or skipped.
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+
+	// LastSuccessfulTime is when the most recent successful run completed.
+	LastSuccessfulTime *metav1.Time `json:"lastSuccessfulTime,omitempty"`
+
+	// NextScheduleTime is when the next run is due.
+	NextScheduleTime *metav1.Time `json:"nextScheduleTime,omitempty"`
+
+	// Active is the number of runs still running.
+	Active int `json:"active,omitempty"`
+
+	// Runs are the runs in progress and the most recent finished ones,
+	// oldest first, within the history limits.
+	Runs []PluginScheduleRun `json:"runs,omitempty"`
+}
+
+// A PluginScheduleSpec defines the desired state of a PluginSchedule.
+type PluginScheduleSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       PluginScheduleParameters `json:"forProvider"`
+}
+
+// A PluginScheduleStatus represents the observed state of a PluginSchedule.
+type PluginScheduleStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          PluginScheduleObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A PluginSchedule performs a plugin action on a set of boards on a cron
+// schedule, like a CronJob.
+// +kubebuilder:printcolumn:name="SCHEDULE",type="string",JSONPath=".spec.forProvider.schedule"
+// +kubebuilder:printcolumn:name="ACTION",type="string",JSONPath=".spec.forProvider.action.type"
+// +kubebuilder:printcolumn:name="SUSPEND",type="boolean",JSONPath=".spec.forProvider.suspend"
+// +kubebuilder:printcolumn:name="ACTIVE",type="integer",JSONPath=".status.atProvider.active"
+// +kubebuilder:printcolumn:name="LAST-SCHEDULE",type="date",JSONPath=".status.atProvider.lastScheduleTime"
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,s4t}
+type PluginSchedule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PluginScheduleSpec   `json:"spec"`
+	Status PluginScheduleStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// PluginScheduleList contains a list of PluginSchedule
+type PluginScheduleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PluginSchedule `json:"items"`
+}
+
+// PluginSchedule type metadata.
+var (
+	PluginScheduleKind             = reflect.TypeOf(PluginSchedule{}).Name()
+	PluginScheduleGroupKind        = schema.GroupKind{Group: Group, Kind: PluginScheduleKind}.String()
+	PluginScheduleKindAPIVersion   = PluginScheduleKind + "." + SchemeGroupVersion.String()
+	PluginScheduleGroupVersionKind = SchemeGroupVersion.WithKind(PluginScheduleKind)
+)
+
+func init() {
+	SchemeBuilder.Register(&PluginSchedule{}, &PluginScheduleList{})
+}
@@ -27,18 +27,53 @@ import (
 // PortParameters are the configurable fields of a Port.
 type PortParameters struct {
 	// +kubebuilder:validation:Immutable
-	Uuid      string `json:"uuid,omitempty"`
-	BoardUuid string `json:"boardUuid"`
-	MacAdd    string `json:"macAdd,omitempty"`
-	VifName   string `json:"vifName,omitempty"`
-	Network   string `json:"network,omitempty"`
-	Ip        string `json:"ip,omitempty"`
+	Uuid string `json:"uuid,omitempty"`
+
+	// BoardUuid is the IoTronic UUID of the board owning the port.
+	// +crossplane:generate:reference:type=Device
+	// +crossplane:generate:reference:extractor=DeviceUUID()
+	// +crossplane:generate:reference:refFieldName=BoardRef
+	// +crossplane:generate:reference:selectorFieldName=BoardSelector
+	BoardUuid string `json:"boardUuid,omitempty"`
+
+	// BoardRef references a Device to retrieve its board UUID.
+	// +optional
+	BoardRef *xpv1.Reference `json:"boardRef,omitempty"`
+
+	// BoardSelector selects a reference to a Device to retrieve its board UUID.
+	// +optional
+	BoardSelector *xpv1.Selector `json:"boardSelector,omitempty"`
+
+	MacAdd  string `json:"macAdd,omitempty"`
+	VifName string `json:"vifName,omitempty"`
+
+	// Network is the IoTronic UUID of the network the port attaches to.
+	// +crossplane:generate:reference:type=Network
+	// +crossplane:generate:reference:extractor=NetworkUUID()
+	// +crossplane:generate:reference:refFieldName=NetworkRef
+	// +crossplane:generate:reference:selectorFieldName=NetworkSelector
+	Network string `json:"network,omitempty"`
+
+	// NetworkRef references a Network to retrieve its UUID.
+	// +optional
+	NetworkRef *xpv1.Reference `json:"networkRef,omitempty"`
+
+	// NetworkSelector selects a reference to a Network to retrieve its UUID.
+	// +optional
+	NetworkSelector *xpv1.Selector `json:"networkSelector,omitempty"`
+
+	// Ip is the address of the port. When an IPPool serves the network and
+	// Ip is empty, the next free address of the pool is allocated.
+	Ip string `json:"ip,omitempty"`
 }
 
 // PortObservation are the observable fields of a Port.
 type PortObservation struct {
 	Uuid string `json:"uuid,omitempty"`
 	Ip   string `json:"ip,omitempty"`
+
+	// IPPool is the name of the IPPool the address is allocated from.
+	IPPool string `json:"ipPool,omitempty"`
 }
 
 // A PortSpec defines the desired state of a Port.
@@ -56,6 +91,7 @@ type PortStatus struct {
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="IP",type="string",JSONPath=".status.atProvider.ip"
 // +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
 // +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
 // +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
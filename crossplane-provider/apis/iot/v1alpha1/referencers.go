@@ -0,0 +1,72 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/reference"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// DeviceUUID extracts the IoTronic board UUID of a referenced Device.
+// Nothing is returned until the Device is Ready, so dependent resources keep
+// retrying resolution while the board is still being registered.
+func DeviceUUID() reference.ExtractValueFn {
+	return func(mg resource.Managed) string {
+		cr, ok := mg.(*Device)
+		if !ok || !resource.IsConditionTrue(cr.GetCondition(xpv1.TypeReady)) {
+			return ""
+		}
+		return cr.Spec.ForProvider.Uuid
+	}
+}
+
+// PluginUUID extracts the IoTronic UUID of a referenced Plugin once it is
+// Ready.
+func PluginUUID() reference.ExtractValueFn {
+	return func(mg resource.Managed) string {
+		cr, ok := mg.(*Plugin)
+		if !ok || !resource.IsConditionTrue(cr.GetCondition(xpv1.TypeReady)) {
+			return ""
+		}
+		return cr.Spec.ForProvider.Uuid
+	}
+}
+
+// ServiceUUID extracts the IoTronic UUID of a referenced Service once it is
+// Ready.
+func ServiceUUID() reference.ExtractValueFn {
+	return func(mg resource.Managed) string {
+		cr, ok := mg.(*Service)
+		if !ok || !resource.IsConditionTrue(cr.GetCondition(xpv1.TypeReady)) {
+			return ""
+		}
+		return cr.Spec.ForProvider.Uuid
+	}
+}
+
+// NetworkUUID extracts the IoTronic UUID of a referenced Network once it is
+// Ready.
+func NetworkUUID() reference.ExtractValueFn {
+	return func(mg resource.Managed) string {
+		cr, ok := mg.(*Network)
+		if !ok || !resource.IsConditionTrue(cr.GetCondition(xpv1.TypeReady)) {
+			return ""
+		}
+		return cr.Spec.ForProvider.Uuid
+	}
+}
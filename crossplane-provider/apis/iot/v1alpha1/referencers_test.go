@@ -0,0 +1,122 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/reference"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestUUIDExtractors(t *testing.T) {
+	device := func(c xpv1.Condition) resource.Managed {
+		d := &Device{}
+		d.Spec.ForProvider.Uuid = "board-1"
+		d.SetConditions(c)
+		return d
+	}
+	plugin := func(c xpv1.Condition) resource.Managed {
+		p := &Plugin{}
+		p.Spec.ForProvider.Uuid = "plugin-1"
+		p.SetConditions(c)
+		return p
+	}
+	service := func(c xpv1.Condition) resource.Managed {
+		s := &Service{}
+		s.Spec.ForProvider.Uuid = "service-1"
+		s.SetConditions(c)
+		return s
+	}
+	network := func(c xpv1.Condition) resource.Managed {
+		n := &Network{}
+		n.Spec.ForProvider.Uuid = "network-1"
+		n.SetConditions(c)
+		return n
+	}
+
+	cases := map[string]struct {
+		reason  string
+		extract reference.ExtractValueFn
+		mg      resource.Managed
+		want    string
+	}{
+		"DeviceReady": {
+			reason:  "The UUID of a Ready Device should be extracted.",
+			extract: DeviceUUID(),
+			mg:      device(xpv1.Available()),
+			want:    "board-1",
+		},
+		"DeviceCreating": {
+			reason:  "Nothing should be extracted from a Device that is not Ready yet, so that resolution is retried.",
+			extract: DeviceUUID(),
+			mg:      device(xpv1.Creating()),
+		},
+		"DeviceUnavailable": {
+			reason:  "Nothing should be extracted from an unavailable Device.",
+			extract: DeviceUUID(),
+			mg:      device(xpv1.Unavailable()),
+		},
+		"PluginReady": {
+			reason:  "The UUID of a Ready Plugin should be extracted.",
+			extract: PluginUUID(),
+			mg:      plugin(xpv1.Available()),
+			want:    "plugin-1",
+		},
+		"PluginCreating": {
+			reason:  "Nothing should be extracted from a Plugin that is not Ready yet.",
+			extract: PluginUUID(),
+			mg:      plugin(xpv1.Creating()),
+		},
+		"ServiceReady": {
+			reason:  "The UUID of a Ready Service should be extracted.",
+			extract: ServiceUUID(),
+			mg:      service(xpv1.Available()),
+			want:    "service-1",
+		},
+		"ServiceCreating": {
+			reason:  "Nothing should be extracted from a Service that is not Ready yet.",
+			extract: ServiceUUID(),
+			mg:      service(xpv1.Creating()),
+		},
+		"NetworkReady": {
+			reason:  "The UUID of a Ready Network should be extracted.",
+			extract: NetworkUUID(),
+			mg:      network(xpv1.Available()),
+			want:    "network-1",
+		},
+		"NetworkCreating": {
+			reason:  "Nothing should be extracted from a Network that is not Ready yet.",
+			extract: NetworkUUID(),
+			mg:      network(xpv1.Creating()),
+		},
+		"WrongKind": {
+			reason:  "Nothing should be extracted from a resource of another kind.",
+			extract: DeviceUUID(),
+			mg:      plugin(xpv1.Available()),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, tc.extract(tc.mg)); diff != "" {
+				t.Errorf("\n%s\nextract(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
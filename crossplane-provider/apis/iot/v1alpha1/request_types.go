@@ -29,12 +29,61 @@ type RequestParameters struct {
 	// +kubebuilder:validation:Immutable
 	Uuid            string `json:"uuid,omitempty"`
 	DestinationUuid string `json:"destinationUuid,omitempty"`
+
+	// MainRequestUuid makes this request a child of another. Children are
+	// rolled up into the status of a parent Request.
 	MainRequestUuid string `json:"mainRequestUuid,omitempty"`
-	PendingRequests int    `json:"pendingRequests,omitempty"`
-	Status          string `json:"status,omitempty"`
 	Project         string `json:"project,omitempty"`
 	Type            int    `json:"type,omitempty"`
 	Action          string `json:"action,omitempty"`
+
+	// ActiveDeadlineSeconds is how long the request may run, from when it
+	// was created, before it is marked TimedOut.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ActiveDeadlineSeconds *int64 `json:"activeDeadlineSeconds,omitempty"`
+}
+
+// RequestPhase is where a Request is in its lifecycle.
+// +kubebuilder:validation:Enum=Pending;Running;Succeeded;Failed;TimedOut
+type RequestPhase string
+
+// Request phases.
+const (
+	// RequestPending requests have no results yet.
+	RequestPending RequestPhase = "Pending"
+	// RequestRunning requests have some results, or children still running.
+	RequestRunning RequestPhase = "Running"
+	// RequestSucceeded requests completed without errors, as did their
+	// children.
+	RequestSucceeded RequestPhase = "Succeeded"
+	// RequestFailed requests completed with an error result, or have a
+	// child that failed or timed out.
+	RequestFailed RequestPhase = "Failed"
+	// RequestTimedOut requests did not finish within their deadline.
+	RequestTimedOut RequestPhase = "TimedOut"
+)
+
+// Finished reports whether p is a terminal phase.
+func (p RequestPhase) Finished() bool {
+	return p == RequestSucceeded || p == RequestFailed || p == RequestTimedOut
+}
+
+// RequestResult is the outcome of a request on one board.
+type RequestResult struct {
+	BoardUuid string `json:"boardUuid,omitempty"`
+	Result    string `json:"result,omitempty"`
+	Message   string `json:"message,omitempty"`
+}
+
+// RequestChildren counts the child requests of a Request by phase.
+type RequestChildren struct {
+	Total     int `json:"total"`
+	Pending   int `json:"pending,omitempty"`
+	Running   int `json:"running,omitempty"`
+	Succeeded int `json:"succeeded,omitempty"`
+	Failed    int `json:"failed,omitempty"`
+	TimedOut  int `json:"timedOut,omitempty"`
 }
 
 // RequestObservation are the observable fields of a Request.
@@ -42,6 +91,22 @@ type RequestObservation struct {
 	Uuid            string `json:"uuid,omitempty"`
 	Status          string `json:"status,omitempty"`
 	PendingRequests int    `json:"pendingRequests,omitempty"`
+
+	// Phase is where the request is in its lifecycle.
+	Phase RequestPhase `json:"phase,omitempty"`
+
+	// StartTime is when the request was created, or first observed.
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is when the request reached a terminal phase.
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Children counts the Requests whose mainRequestUuid is this request.
+	Children *RequestChildren `json:"children,omitempty"`
+
+	// Results are the per-board results of the request, recorded once it
+	// completes. At most 100 are kept.
+	Results []RequestResult `json:"results,omitempty"`
 }
 
 // A RequestSpec defines the desired state of a Request.
@@ -61,6 +126,8 @@ type RequestStatus struct {
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
 // +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="PHASE",type="string",JSONPath=".status.atProvider.phase"
+// +kubebuilder:printcolumn:name="ACTION",type="string",JSONPath=".spec.forProvider.action"
 // +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,s4t}
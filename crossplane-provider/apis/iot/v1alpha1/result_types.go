@@ -26,13 +26,56 @@ import (
 
 // ResultParameters are the configurable fields of a Result.
 // Note: Results are typically read-only resources created by Stack4Things
+// +kubebuilder:validation:XValidation:rule="!has(self.deleteRecords) || !self.deleteRecords || !has(self.boardUuid) || self.boardUuid == '' || (has(self.uuid) && self.uuid != '')",message="deleteRecords would delete the results of every board of the request; it cannot be combined with boardUuid"
 type ResultParameters struct {
 	// +kubebuilder:validation:Immutable
-	Uuid        string `json:"uuid,omitempty"`
+	Uuid string `json:"uuid,omitempty"`
+
+	// BoardUuid restricts the results of RequestUuid to one board.
 	BoardUuid   string `json:"boardUuid,omitempty"`
 	RequestUuid string `json:"requestUuid,omitempty"`
-	Result      string `json:"result,omitempty"`
-	Message     string `json:"message,omitempty"`
+
+	// MaxResults bounds the per-board results listed in status. Failed
+	// results are listed first. The summary counts every result.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1000
+	// +kubebuilder:default=100
+	// +optional
+	MaxResults *int `json:"maxResults,omitempty"`
+
+	// TTLSecondsAfterFinished deletes the Result this long after every
+	// board has answered.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	TTLSecondsAfterFinished *int64 `json:"ttlSecondsAfterFinished,omitempty"`
+
+	// DeleteRecords deletes the results from IoTronic when the Result is
+	// deleted, rather than only from the cluster. IoTronic deletes the
+	// results of a request for every board at once, so it cannot be set
+	// together with BoardUuid unless Uuid names a single result.
+	// +optional
+	DeleteRecords bool `json:"deleteRecords,omitempty"`
+}
+
+// A ResultEntry is the outcome of a request on one board.
+type ResultEntry struct {
+	BoardUuid string `json:"boardUuid,omitempty"`
+
+	// Result is SUCCESS, ERROR, WARNING or RUNNING.
+	Result  string `json:"result,omitempty"`
+	Message string `json:"message,omitempty"`
+
+	// Timestamp is when IoTronic last updated the result.
+	Timestamp *metav1.Time `json:"timestamp,omitempty"`
+}
+
+// ResultSummary counts the results of a request by outcome.
+type ResultSummary struct {
+	Total     int `json:"total"`
+	Succeeded int `json:"succeeded,omitempty"`
+	Failed    int `json:"failed,omitempty"`
+	Warning   int `json:"warning,omitempty"`
+	Running   int `json:"running,omitempty"`
 }
 
 // ResultObservation are the observable fields of a Result.
@@ -40,8 +83,18 @@ type ResultObservation struct {
 	Uuid        string `json:"uuid,omitempty"`
 	BoardUuid   string `json:"boardUuid,omitempty"`
 	RequestUuid string `json:"requestUuid,omitempty"`
-	Result      string `json:"result,omitempty"`
-	Message     string `json:"message,omitempty"`
+
+	// Summary counts every result.
+	Summary ResultSummary `json:"summary,omitempty"`
+
+	// Results lists at most maxResults per-board results.
+	Results []ResultEntry `json:"results,omitempty"`
+
+	// Omitted is the number of results not listed.
+	Omitted int `json:"omitted,omitempty"`
+
+	// FinishedTime is when no result was running any more.
+	FinishedTime *metav1.Time `json:"finishedTime,omitempty"`
 }
 
 // A ResultSpec defines the desired state of a Result.
@@ -61,6 +114,9 @@ type ResultStatus struct {
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
 // +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="TOTAL",type="integer",JSONPath=".status.atProvider.summary.total"
+// +kubebuilder:printcolumn:name="FAILED",type="integer",JSONPath=".status.atProvider.summary.failed"
+// +kubebuilder:printcolumn:name="FINISHED",type="date",JSONPath=".status.atProvider.finishedTime"
 // +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,s4t}
@@ -0,0 +1,85 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+// A Route exposes a board endpoint through the cluster's ingress. The
+// provider generates an Istio VirtualService, or a Kubernetes Ingress when
+// the Istio CRDs are not installed, once the resource is ready. The generated
+// object is owned by the resource and deleted with it.
+type Route struct {
+	// Hosts the route answers on. Webservices default to the host of their
+	// public URL.
+	// +optional
+	Hosts []string `json:"hosts,omitempty"`
+
+	// Namespace the VirtualService or Ingress is created in. Backend
+	// services are resolved in this namespace.
+	// +kubebuilder:default=default
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Path is the URI prefix routed to the backend.
+	// +kubebuilder:default="/"
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// Gateways the VirtualService binds to, as [namespace/]name.
+	// +optional
+	Gateways []string `json:"gateways,omitempty"`
+
+	// IngressClassName of the Ingress generated when Istio is not installed.
+	// +optional
+	IngressClassName *string `json:"ingressClassName,omitempty"`
+
+	// Annotations added to the generated VirtualService or Ingress.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// Backend overrides the Service traffic is routed to.
+	// +optional
+	Backend *RouteBackend `json:"backend,omitempty"`
+}
+
+// A RouteBackend is the Service a Route forwards traffic to.
+type RouteBackend struct {
+	// Service is the name of the Service.
+	Service string `json:"service"`
+
+	// Port is the Service port. Defaults to the port of the board endpoint.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Port int32 `json:"port,omitempty"`
+}
+
+// RouteObservation records the object generated for a Route.
+type RouteObservation struct {
+	// Kind is VirtualService or Ingress.
+	Kind string `json:"kind"`
+
+	// APIVersion of the generated object.
+	APIVersion string `json:"apiVersion"`
+
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+
+	// Hosts the route answers on.
+	Hosts []string `json:"hosts,omitempty"`
+
+	// Backend is the service:port traffic is forwarded to.
+	Backend string `json:"backend,omitempty"`
+}
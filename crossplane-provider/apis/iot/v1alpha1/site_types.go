@@ -20,6 +20,7 @@ import (
 	"reflect"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
@@ -37,16 +38,105 @@ type SiteParameters struct {
 	Location string `json:"location,omitempty"`
 	// Site configuration parameters
 	Config map[string]string `json:"config,omitempty"`
-	// Parent site UUID for hierarchical multisite structure
+	// ParentSite is the name of the parent Site for hierarchical multisite
+	// structure. It must exist and must not make the hierarchy cyclic.
 	ParentSite string `json:"parentSite,omitempty"`
+
+	// Geofence is the area covered by the site. Devices whose board reports
+	// a location inside it are given the site's geofence-site label.
+	// +optional
+	Geofence *Geofence `json:"geofence,omitempty"`
+
+	// MaintenanceWindowRef references the MaintenanceWindow outside which
+	// disruptive operations on the site's boards are deferred. Child sites
+	// inherit it unless they reference their own.
+	// +optional
+	MaintenanceWindowRef *xpv1.Reference `json:"maintenanceWindowRef,omitempty"`
+}
+
+// A Geofence is either a GeoJSON polygon or a circle around a center.
+// +kubebuilder:validation:XValidation:rule="has(self.polygon) != has(self.center)",message="exactly one of polygon and center must be set"
+// +kubebuilder:validation:XValidation:rule="!has(self.center) || has(self.radiusMeters)",message="radiusMeters is required with center"
+type Geofence struct {
+	// Polygon is a GeoJSON Polygon geometry: linear rings of
+	// [longitude, latitude] positions, the first being the boundary and any
+	// others holes.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	Polygon *runtime.RawExtension `json:"polygon,omitempty"`
+
+	// Center of a circular geofence.
+	// +optional
+	Center *GeoPoint `json:"center,omitempty"`
+
+	// RadiusMeters is the radius of a circular geofence.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	RadiusMeters int64 `json:"radiusMeters,omitempty"`
+}
+
+// A GeoPoint is a position in decimal degrees, written like a Device
+// Location.
+type GeoPoint struct {
+	Latitude  string `json:"latitude"`
+	Longitude string `json:"longitude"`
 }
 
+// LabelSite is the Device label naming the Site a device belongs to.
+const LabelSite = "site"
+
+// LabelGeofenceSite is the Device label naming the Site whose geofence
+// contains the board's latest location. Unlike LabelSite it does not select
+// the ProviderConfig serving the device.
+const LabelGeofenceSite = "geofence-site"
+
 // SiteObservation are the observable fields of a Site.
 type SiteObservation struct {
-	Uuid        string `json:"uuid,omitempty"`
-	Name        string `json:"name,omitempty"`
-	Status      string `json:"status,omitempty"`
-	DeviceCount int    `json:"deviceCount,omitempty"`
+	Uuid   string `json:"uuid,omitempty"`
+	Name   string `json:"name,omitempty"`
+	Status string `json:"status,omitempty"`
+
+	// DeviceCount is the number of Devices labelled with this site.
+	DeviceCount int `json:"deviceCount,omitempty"`
+
+	// OnlineCount is the number of those Devices whose board is online.
+	OnlineCount int `json:"onlineCount,omitempty"`
+
+	// GeofenceDeviceCount is the number of Devices whose board's latest
+	// location lies within this site's geofence.
+	GeofenceDeviceCount int `json:"geofenceDeviceCount,omitempty"`
+
+	// TotalDeviceCount and TotalOnlineCount include the Devices of all
+	// descendant sites.
+	TotalDeviceCount int `json:"totalDeviceCount,omitempty"`
+	TotalOnlineCount int `json:"totalOnlineCount,omitempty"`
+
+	// ChildSites are the names of the Sites whose parent is this site.
+	ChildSites []string `json:"childSites,omitempty"`
+
+	// Connectivity reports whether the Stack4Things deployment serving this
+	// site can be reached.
+	Connectivity *SiteConnectivity `json:"connectivity,omitempty"`
+}
+
+// SiteConnectivity is the result of probing a site's Keystone and IoTronic
+// endpoints.
+type SiteConnectivity struct {
+	// ProviderConfig is the name of the ProviderConfig serving this site:
+	// its own providerConfigRef, otherwise that of its nearest ancestor
+	// setting one, otherwise the provider-wide default.
+	ProviderConfig string `json:"providerConfig,omitempty"`
+
+	KeystoneEndpoint string `json:"keystoneEndpoint,omitempty"`
+	IotronicEndpoint string `json:"iotronicEndpoint,omitempty"`
+
+	// Reachable is true when both endpoints answered the last probe.
+	Reachable bool `json:"reachable"`
+
+	// Message describes why the last probe failed.
+	Message string `json:"message,omitempty"`
+
+	LastProbeTime *metav1.Time `json:"lastProbeTime,omitempty"`
 }
 
 // A SiteSpec defines the desired state of a Site.
@@ -69,6 +159,10 @@ type SiteStatus struct {
 // +kubebuilder:printcolumn:name="Site Name",type=string,JSONPath=".spec.forProvider.name"
 // +kubebuilder:printcolumn:name="Location",type=string,JSONPath=".spec.forProvider.location"
 // +kubebuilder:printcolumn:name="Status",type=string,JSONPath=".status.status"
+// +kubebuilder:printcolumn:name="DEVICES",type="integer",JSONPath=".status.atProvider.totalDeviceCount"
+// +kubebuilder:printcolumn:name="ONLINE",type="integer",JSONPath=".status.atProvider.totalOnlineCount"
+// +kubebuilder:printcolumn:name="BACKEND",type="string",JSONPath=".status.atProvider.connectivity.providerConfig"
+// +kubebuilder:printcolumn:name="REACHABLE",type="boolean",JSONPath=".status.atProvider.connectivity.reachable"
 // +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
 // +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
 // +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
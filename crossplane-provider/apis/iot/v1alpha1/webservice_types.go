@@ -26,20 +26,72 @@ import (
 )
 
 // WebserviceParameters are the configurable fields of a Webservice.
+// +kubebuilder:validation:XValidation:rule="!has(self.tls) || self.secure",message="tls requires secure to be true"
 type WebserviceParameters struct {
 	// +kubebuilder:validation:Immutable
-	Uuid      string                 `json:"uuid,omitempty"`
-	Name      string                 `json:"name"`
-	Port      int                    `json:"port"`
-	BoardUuid string               `json:"boardUuid,omitempty"`
-	Secure    bool                 `json:"secure,omitempty"`
-	Extra     runtime.RawExtension `json:"extra,omitempty"`
+	Uuid string `json:"uuid,omitempty"`
+	Name string `json:"name"`
+	Port int    `json:"port"`
+
+	// BoardUuid is the IoTronic UUID of the board serving the webservice.
+	// +crossplane:generate:reference:type=Device
+	// +crossplane:generate:reference:extractor=DeviceUUID()
+	// +crossplane:generate:reference:refFieldName=BoardRef
+	// +crossplane:generate:reference:selectorFieldName=BoardSelector
+	BoardUuid string `json:"boardUuid,omitempty"`
+
+	// BoardRef references a Device to retrieve its board UUID.
+	// +optional
+	BoardRef *xpv1.Reference `json:"boardRef,omitempty"`
+
+	// BoardSelector selects a reference to a Device to retrieve its board UUID.
+	// +optional
+	BoardSelector *xpv1.Selector `json:"boardSelector,omitempty"`
+
+	Secure bool                 `json:"secure,omitempty"`
+	Extra  runtime.RawExtension `json:"extra,omitempty"`
+
+	// TLS is the certificate the webservice serves. It is pushed to IoTronic
+	// when the webservice is enabled and again whenever it changes.
+	// +optional
+	TLS *WebserviceTLS `json:"tls,omitempty"`
+
+	// Route exposes the webservice through the cluster's ingress once it is
+	// ready. Traffic is forwarded to IoTronic's web proxy, which dispatches
+	// on the Host header, unless a backend is given.
+	// +optional
+	Route *Route `json:"route,omitempty"`
+}
+
+// WebserviceTLS configures the certificate of a Webservice.
+type WebserviceTLS struct {
+	// SecretRef references a kubernetes.io/tls Secret holding the
+	// certificate and its private key.
+	SecretRef xpv1.SecretReference `json:"secretRef"`
+
+	// SelfSigned makes the provider generate a self-signed certificate into
+	// SecretRef, and renew it before it expires, instead of reading one.
+	// +optional
+	SelfSigned bool `json:"selfSigned,omitempty"`
 }
 
 // WebserviceObservation are the observable fields of a Webservice.
 type WebserviceObservation struct {
 	Uuid string `json:"uuid,omitempty"`
 	Name string `json:"name,omitempty"`
+
+	// URL is the public URL of the webservice.
+	URL string `json:"url,omitempty"`
+
+	// CertificateExpiry is when the certificate pushed to IoTronic expires.
+	CertificateExpiry *metav1.Time `json:"certificateExpiry,omitempty"`
+
+	// CertificateFingerprint is the SHA-256 fingerprint of the certificate
+	// pushed to IoTronic.
+	CertificateFingerprint string `json:"certificateFingerprint,omitempty"`
+
+	// Route is the VirtualService or Ingress generated for spec.forProvider.route.
+	Route *RouteObservation `json:"route,omitempty"`
 }
 
 // A WebserviceSpec defines the desired state of a Webservice.
@@ -57,6 +109,8 @@ type WebserviceStatus struct {
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="URL",type="string",JSONPath=".status.atProvider.url"
+// +kubebuilder:printcolumn:name="CERT-EXPIRY",type="date",JSONPath=".status.atProvider.certificateExpiry"
 // +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
 // +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
 // +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
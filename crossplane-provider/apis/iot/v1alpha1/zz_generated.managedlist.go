@@ -28,6 +28,15 @@ func (l *BoardPluginInjectionList) GetItems() []resource.Managed {
 	return items
 }
 
+// GetItems of this BoardProfileList.
+func (l *BoardProfileList) GetItems() []resource.Managed {
+	items := make([]resource.Managed, len(l.Items))
+	for i := range l.Items {
+		items[i] = &l.Items[i]
+	}
+	return items
+}
+
 // GetItems of this BoardServiceInjectionList.
 func (l *BoardServiceInjectionList) GetItems() []resource.Managed {
 	items := make([]resource.Managed, len(l.Items))
@@ -46,6 +55,15 @@ func (l *DeviceList) GetItems() []resource.Managed {
 	return items
 }
 
+// GetItems of this FleetInjectionList.
+func (l *FleetInjectionList) GetItems() []resource.Managed {
+	items := make([]resource.Managed, len(l.Items))
+	for i := range l.Items {
+		items[i] = &l.Items[i]
+	}
+	return items
+}
+
 // GetItems of this FleetList.
 func (l *FleetList) GetItems() []resource.Managed {
 	items := make([]resource.Managed, len(l.Items))
@@ -55,6 +73,24 @@ func (l *FleetList) GetItems() []resource.Managed {
 	return items
 }
 
+// GetItems of this MaintenanceWindowList.
+func (l *MaintenanceWindowList) GetItems() []resource.Managed {
+	items := make([]resource.Managed, len(l.Items))
+	for i := range l.Items {
+		items[i] = &l.Items[i]
+	}
+	return items
+}
+
+// GetItems of this NetworkList.
+func (l *NetworkList) GetItems() []resource.Managed {
+	items := make([]resource.Managed, len(l.Items))
+	for i := range l.Items {
+		items[i] = &l.Items[i]
+	}
+	return items
+}
+
 // GetItems of this PluginList.
 func (l *PluginList) GetItems() []resource.Managed {
 	items := make([]resource.Managed, len(l.Items))
@@ -64,6 +100,15 @@ func (l *PluginList) GetItems() []resource.Managed {
 	return items
 }
 
+// GetItems of this PluginScheduleList.
+func (l *PluginScheduleList) GetItems() []resource.Managed {
+	items := make([]resource.Managed, len(l.Items))
+	for i := range l.Items {
+		items[i] = &l.Items[i]
+	}
+	return items
+}
+
 // GetItems of this PortList.
 func (l *PortList) GetItems() []resource.Managed {
 	items := make([]resource.Managed, len(l.Items))
@@ -0,0 +1,177 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by angryjet. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"context"
+	reference "github.com/crossplane/crossplane-runtime/pkg/reference"
+	errors "github.com/pkg/errors"
+	client "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ResolveReferences of this BoardPluginInjection.
+func (mg *BoardPluginInjection) ResolveReferences(ctx context.Context, c client.Reader) error {
+	r := reference.NewAPIResolver(c, mg)
+
+	var rsp reference.ResolutionResponse
+	var err error
+
+	rsp, err = r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: mg.Spec.ForProvider.BoardUuid,
+		Extract:      DeviceUUID(),
+		Reference:    mg.Spec.ForProvider.BoardRef,
+		Selector:     mg.Spec.ForProvider.BoardSelector,
+		To: reference.To{
+			List:    &DeviceList{},
+			Managed: &Device{},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "mg.Spec.ForProvider.BoardUuid")
+	}
+	mg.Spec.ForProvider.BoardUuid = rsp.ResolvedValue
+	mg.Spec.ForProvider.BoardRef = rsp.ResolvedReference
+
+	rsp, err = r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: mg.Spec.ForProvider.PluginUuid,
+		Extract:      PluginUUID(),
+		Reference:    mg.Spec.ForProvider.PluginRef,
+		Selector:     mg.Spec.ForProvider.PluginSelector,
+		To: reference.To{
+			List:    &PluginList{},
+			Managed: &Plugin{},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "mg.Spec.ForProvider.PluginUuid")
+	}
+	mg.Spec.ForProvider.PluginUuid = rsp.ResolvedValue
+	mg.Spec.ForProvider.PluginRef = rsp.ResolvedReference
+
+	return nil
+}
+
+// ResolveReferences of this BoardServiceInjection.
+func (mg *BoardServiceInjection) ResolveReferences(ctx context.Context, c client.Reader) error {
+	r := reference.NewAPIResolver(c, mg)
+
+	var rsp reference.ResolutionResponse
+	var err error
+
+	rsp, err = r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: mg.Spec.ForProvider.BoardUuid,
+		Extract:      DeviceUUID(),
+		Reference:    mg.Spec.ForProvider.BoardRef,
+		Selector:     mg.Spec.ForProvider.BoardSelector,
+		To: reference.To{
+			List:    &DeviceList{},
+			Managed: &Device{},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "mg.Spec.ForProvider.BoardUuid")
+	}
+	mg.Spec.ForProvider.BoardUuid = rsp.ResolvedValue
+	mg.Spec.ForProvider.BoardRef = rsp.ResolvedReference
+
+	rsp, err = r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: mg.Spec.ForProvider.ServiceUuid,
+		Extract:      ServiceUUID(),
+		Reference:    mg.Spec.ForProvider.ServiceRef,
+		Selector:     mg.Spec.ForProvider.ServiceSelector,
+		To: reference.To{
+			List:    &ServiceList{},
+			Managed: &Service{},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "mg.Spec.ForProvider.ServiceUuid")
+	}
+	mg.Spec.ForProvider.ServiceUuid = rsp.ResolvedValue
+	mg.Spec.ForProvider.ServiceRef = rsp.ResolvedReference
+
+	return nil
+}
+
+// ResolveReferences of this Port.
+func (mg *Port) ResolveReferences(ctx context.Context, c client.Reader) error {
+	r := reference.NewAPIResolver(c, mg)
+
+	var rsp reference.ResolutionResponse
+	var err error
+
+	rsp, err = r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: mg.Spec.ForProvider.BoardUuid,
+		Extract:      DeviceUUID(),
+		Reference:    mg.Spec.ForProvider.BoardRef,
+		Selector:     mg.Spec.ForProvider.BoardSelector,
+		To: reference.To{
+			List:    &DeviceList{},
+			Managed: &Device{},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "mg.Spec.ForProvider.BoardUuid")
+	}
+	mg.Spec.ForProvider.BoardUuid = rsp.ResolvedValue
+	mg.Spec.ForProvider.BoardRef = rsp.ResolvedReference
+
+	rsp, err = r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: mg.Spec.ForProvider.Network,
+		Extract:      NetworkUUID(),
+		Reference:    mg.Spec.ForProvider.NetworkRef,
+		Selector:     mg.Spec.ForProvider.NetworkSelector,
+		To: reference.To{
+			List:    &NetworkList{},
+			Managed: &Network{},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "mg.Spec.ForProvider.Network")
+	}
+	mg.Spec.ForProvider.Network = rsp.ResolvedValue
+	mg.Spec.ForProvider.NetworkRef = rsp.ResolvedReference
+
+	return nil
+}
+
+// ResolveReferences of this Webservice.
+func (mg *Webservice) ResolveReferences(ctx context.Context, c client.Reader) error {
+	r := reference.NewAPIResolver(c, mg)
+
+	var rsp reference.ResolutionResponse
+	var err error
+
+	rsp, err = r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: mg.Spec.ForProvider.BoardUuid,
+		Extract:      DeviceUUID(),
+		Reference:    mg.Spec.ForProvider.BoardRef,
+		Selector:     mg.Spec.ForProvider.BoardSelector,
+		To: reference.To{
+			List:    &DeviceList{},
+			Managed: &Device{},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "mg.Spec.ForProvider.BoardUuid")
+	}
+	mg.Spec.ForProvider.BoardUuid = rsp.ResolvedValue
+	mg.Spec.ForProvider.BoardRef = rsp.ResolvedReference
+
+	return nil
+}
@@ -34,6 +34,11 @@ type ProviderConfigSpec struct {
 	// If not specified, defaults to http://keystone.default.svc.cluster.local:5000/v3
 	// +optional
 	KeystoneEndpoint string `json:"keystoneEndpoint,omitempty"`
+
+	// IotronicEndpoint is the IoTronic API URL, including its port.
+	// If not specified, defaults to http://iotronic-conductor.default.svc.cluster.local:8812
+	// +optional
+	IotronicEndpoint string `json:"iotronicEndpoint,omitempty"`
 }
 
 // ProviderCredentials required to authenticate.
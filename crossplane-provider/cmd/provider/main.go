@@ -29,6 +29,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/controller"
@@ -41,8 +42,13 @@ import (
 	"github.com/crossplane/provider-s4t/apis/v1alpha1"
 	s4t "github.com/crossplane/provider-s4t/internal/controller"
 	"github.com/crossplane/provider-s4t/internal/features"
+	s4twebhook "github.com/crossplane/provider-s4t/internal/webhook"
 )
 
+// tlsServerCertsDir is where Crossplane mounts the TLS certificate of the
+// provider's webhook server.
+const tlsServerCertsDir = "/tls/server"
+
 func main() {
 	var (
 		app            = kingpin.New(filepath.Base(os.Args[0]), "S4T support for Crossplane.").DefaultEnvars()
@@ -56,6 +62,8 @@ func main() {
 		namespace                  = app.Flag("namespace", "Namespace used to set as default scope in default secret store config.").Default("crossplane-system").Envar("POD_NAMESPACE").String()
 		enableExternalSecretStores = app.Flag("enable-external-secret-stores", "Enable support for ExternalSecretStores.").Default("false").Envar("ENABLE_EXTERNAL_SECRET_STORES").Bool()
 		enableManagementPolicies   = app.Flag("enable-management-policies", "Enable support for Management Policies.").Default("false").Envar("ENABLE_MANAGEMENT_POLICIES").Bool()
+
+		certsDir = app.Flag("certs-dir", "The directory that contains the webhook server key and certificate.").Default(tlsServerCertsDir).Envar("CERTS_DIR").String()
 	)
 	kingpin.MustParse(app.Parse(os.Args[1:]))
 
@@ -90,6 +98,10 @@ func main() {
 		LeaderElectionResourceLock: resourcelock.LeasesResourceLock,
 		LeaseDuration:              func() *time.Duration { d := 60 * time.Second; return &d }(),
 		RenewDeadline:              func() *time.Duration { d := 50 * time.Second; return &d }(),
+
+		WebhookServer: webhook.NewServer(webhook.Options{
+			CertDir: *certsDir,
+		}),
 	})
 	kingpin.FatalIfError(err, "Cannot create controller manager")
 	kingpin.FatalIfError(apis.AddToScheme(mgr.GetScheme()), "Cannot add S4T APIs to scheme")
@@ -127,5 +139,13 @@ func main() {
 	}
 
 	kingpin.FatalIfError(s4t.Setup(mgr, o), "Cannot setup S4T controllers")
+
+	// The webhook server only starts once a webhook is registered, so skip
+	// them when no certificate was provided, e.g. when running locally.
+	if _, err := os.Stat(filepath.Join(*certsDir, "tls.crt")); err == nil {
+		kingpin.FatalIfError(s4twebhook.Setup(mgr), "Cannot setup S4T webhooks")
+	} else {
+		log.Info("Webhooks disabled, no TLS certificate found", "certs-dir", *certsDir)
+	}
 	kingpin.FatalIfError(mgr.Start(ctrl.SetupSignalHandler()), "Cannot start controller manager")
 }
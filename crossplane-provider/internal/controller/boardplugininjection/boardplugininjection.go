@@ -17,30 +17,47 @@ limitations under the License.
 package boardplugininjection
 
 import (
-	"strings"
-	"os"
+	"bytes"
 	"context"
+	"crypto/sha256"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
+	"net/http"
+	"time"
 
 	s4t "github.com/MIKE9708/s4t-sdk-go/pkg/api"
-	read_config "github.com/MIKE9708/s4t-sdk-go/pkg/read_conf"
+	boards "github.com/MIKE9708/s4t-sdk-go/pkg/api/data/board"
+	plugins "github.com/MIKE9708/s4t-sdk-go/pkg/api/data/plugin"
 
 	v1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/connection"
 	"github.com/crossplane/crossplane-runtime/pkg/controller"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/provider-s4t/apis/iot/v1alpha1"
 	apisv1alpha1 "github.com/crossplane/provider-s4t/apis/v1alpha1"
+	"github.com/crossplane/provider-s4t/internal/expiry"
 	"github.com/crossplane/provider-s4t/internal/features"
+	"github.com/crossplane/provider-s4t/internal/maintenance"
+	"github.com/crossplane/provider-s4t/internal/params"
+	"github.com/crossplane/provider-s4t/internal/providerconfig"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlevent "sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 const (
@@ -49,6 +66,17 @@ const (
 	errGetPC                   = "cannot get ProviderConfig"
 	errGetCreds                = "cannot get credentials"
 	errNewClient               = "cannot create new Service"
+
+	// boardStatusOnline is the IoTronic status of a board whose Lightning Rod
+	// is connected. Plugins can only be injected into online boards.
+	boardStatusOnline = "online"
+
+	// pluginStatusRunning is what IoTronic reports for an injected plugin
+	// that Lightning Rod is running.
+	pluginStatusRunning = "running"
+
+	reasonPluginStarted event.Reason = "PluginStarted"
+	reasonPluginStopped event.Reason = "PluginStopped"
 )
 
 type S4TService struct {
@@ -57,64 +85,11 @@ type S4TService struct {
 
 var (
 	newS4TService = func(creds []byte, keystoneEndpoint string) (*S4TService, error) {
-		var result map[string]string
-		err := json.Unmarshal(creds, &result)
+		c, err := providerconfig.Connect(creds, keystoneEndpoint)
 		if err != nil {
 			return nil, errors.Wrap(err, errNewClient)
 		}
-		auth_req := read_config.FormatAuthRequ(
-			result["username"],
-			result["password"],
-			result["domain"],
-		)
-		
-		// Set Keystone endpoint via environment variable (OS_AUTH_URL)
-		if keystoneEndpoint != "" {
-			os.Setenv("OS_AUTH_URL", keystoneEndpoint)
-		} else {
-			os.Setenv("OS_AUTH_URL", "http://keystone.default.svc.cluster.local:5000/v3")
-		}
-		
-		// Extract host from keystoneEndpoint BEFORE creating client
-		// SDK uses hardcoded 127.0.0.1, we need to use Kubernetes service
-		endpoint := keystoneEndpoint
-		if endpoint == "" {
-			endpoint = "http://keystone.default.svc.cluster.local:5000/v3"
-		}
-		if strings.HasSuffix(endpoint, "/v3") {
-			endpoint = strings.TrimSuffix(endpoint, "/v3")
-		}
-		scheme := "http://"
-		if strings.HasPrefix(endpoint, "https://") {
-			scheme = "https://"
-			endpoint = strings.TrimPrefix(endpoint, "https://")
-		} else if strings.HasPrefix(endpoint, "http://") {
-			endpoint = strings.TrimPrefix(endpoint, "http://")
-		}
-		if idx := strings.Index(endpoint, ":"); idx != -1 {
-			endpoint = endpoint[:idx]
-		}
-		keystoneHost := scheme + endpoint
-		
-		// Create client manually with correct host (without port) instead of using GetClientConnection
-		// SDK will derive final URL using AuthPort
-		s4t_client := s4t.NewClient(keystoneHost)
-		s4t_client.Port = "8812"
-		s4t_client.AuthPort = "5000"
-		
-		// Authenticate with correct endpoint
-		token, err := s4t_client.Authenticate(s4t_client, auth_req)
-		if err != nil {
-			return nil, errors.Wrap(err, errNewClient)
-		}
-		s4t_client.AuthToken = token
-		
-		iotronicHost := scheme + "iotronic-conductor.default.svc.cluster.local"
-		s4t_client.Endpoint = iotronicHost
-		
-		return &S4TService{
-			S4tClient: s4t_client,
-		}, err
+		return &S4TService{S4tClient: c}, nil
 	}
 )
 
@@ -126,33 +101,129 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), apisv1alpha1.StoreConfigGroupVersionKind))
 	}
 
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
+
 	r := managed.NewReconciler(mgr,
 		resource.ManagedKind(v1alpha1.BoardPluginInjectionGroupVersionKind),
 		managed.WithExternalConnecter(&connector{
 			kube:         mgr.GetClient(),
-			usage:        resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
-			newServiceFn: newS4TService}),
+			usage:        providerconfig.NewTracker(mgr.GetClient()),
+			newServiceFn: newS4TService,
+			recorder:     recorder}),
+		managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
 		managed.WithLogger(o.Logger.WithValues("controller", name)),
 		managed.WithPollInterval(o.PollInterval),
-		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		managed.WithPollIntervalHook(expiry.PollInterval(expiresAt)),
+		managed.WithRecorder(recorder),
 		managed.WithConnectionPublishers(cps...))
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
 		WithOptions(o.ForControllerRuntime()).
-		WithEventFilter(resource.DesiredStateChanged()).
-		For(&v1alpha1.BoardPluginInjection{}).
+		For(&v1alpha1.BoardPluginInjection{}, builder.WithPredicates(resource.DesiredStateChanged())).
+		Watches(&v1alpha1.Device{},
+			handler.EnqueueRequestsFromMapFunc(injectionsForDevice(mgr.GetClient())),
+			builder.WithPredicates(boardStateChanged)).
+		Watches(&v1alpha1.MaintenanceWindow{},
+			handler.EnqueueRequestsFromMapFunc(maintenance.Waiting(mgr.GetClient(), func() resource.ManagedList { return &v1alpha1.BoardPluginInjectionList{} })),
+			builder.WithPredicates(maintenance.Opened)).
+		Watches(&corev1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(params.Referencing(mgr.GetClient(), func() resource.ManagedList { return &v1alpha1.BoardPluginInjectionList{} }, parametersFrom))).
 		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
 }
 
+// expiresAt returns when a BoardPluginInjection expires.
+func expiresAt(mg resource.Managed) *metav1.Time {
+	cr, ok := mg.(*v1alpha1.BoardPluginInjection)
+	if !ok {
+		return nil
+	}
+	return cr.Status.AtProvider.ExpiresAt
+}
+
+// parametersFrom returns the Secrets a BoardPluginInjection sets parameters
+// from.
+func parametersFrom(mg resource.Managed) []v1alpha1.ParameterFrom {
+	cr, ok := mg.(*v1alpha1.BoardPluginInjection)
+	if !ok {
+		return nil
+	}
+	return cr.Spec.ForProvider.ParametersFrom
+}
+
+// injectionsForDevice maps a Device to the BoardPluginInjections that target
+// its board, so that they are reconciled as soon as the board changes state
+// rather than on the next poll.
+func injectionsForDevice(kube client.Client) handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		d, ok := obj.(*v1alpha1.Device)
+		if !ok {
+			return nil
+		}
+		l := &v1alpha1.BoardPluginInjectionList{}
+		if err := kube.List(ctx, l); err != nil {
+			log.Printf("Error listing BoardPluginInjections for Device %s: %v", d.GetName(), err)
+			return nil
+		}
+		var reqs []reconcile.Request
+		for _, i := range l.Items {
+			ref := i.Spec.ForProvider.BoardRef
+			if (d.Spec.ForProvider.Uuid != "" && i.Spec.ForProvider.BoardUuid == d.Spec.ForProvider.Uuid) ||
+				(ref != nil && ref.Name == d.GetName()) {
+				reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{Name: i.GetName()}})
+			}
+		}
+		return reqs
+	}
+}
+
+// boardStateChanged passes Device updates that change the observed board
+// status or the Device's readiness.
+var boardStateChanged = predicate.Funcs{
+	CreateFunc:  func(ctrlevent.CreateEvent) bool { return false },
+	DeleteFunc:  func(ctrlevent.DeleteEvent) bool { return false },
+	GenericFunc: func(ctrlevent.GenericEvent) bool { return false },
+	UpdateFunc: func(e ctrlevent.UpdateEvent) bool {
+		o, ok := e.ObjectOld.(*v1alpha1.Device)
+		if !ok {
+			return false
+		}
+		n, ok := e.ObjectNew.(*v1alpha1.Device)
+		if !ok {
+			return false
+		}
+		return o.Status.Status != n.Status.Status ||
+			o.GetCondition(v1.TypeReady).Status != n.GetCondition(v1.TypeReady).Status
+	},
+}
+
 type connector struct {
 	kube         client.Client
 	usage        resource.Tracker
 	newServiceFn func(creds []byte, keystoneEndpoint string) (*S4TService, error)
+	recorder     event.Recorder
 }
 
 type external struct {
-	service *S4TService
+	service  *S4TService
+	kube     client.Client
+	recorder event.Recorder
+
+	// boards reads boards from IoTronic. When nil, the S4T client does.
+	boards boardReader
+}
+
+// A boardReader reads the state of a board and the plugins injected into it.
+type boardReader interface {
+	GetBoardDetail(uuid string) (*boards.Board, error)
+	GetBoardPlugins(uuid string) ([]plugins.PluginBoard, error)
+}
+
+func (c *external) board() boardReader {
+	if c.boards != nil {
+		return c.boards
+	}
+	return c.service.S4tClient
 }
 
 func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
@@ -163,9 +234,8 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 	if err := c.usage.Track(ctx, mg); err != nil {
 		return nil, errors.Wrap(err, errTrackPCUsage)
 	}
-	// cr.GetProviderConfigReference().Name
-	pc_domain := &apisv1alpha1.ProviderConfig{}
-	if err := c.kube.Get(ctx, types.NamespacedName{Name: "s4t-provider-domain"}, pc_domain); err != nil {
+	pc_domain, err := providerconfig.Resolve(ctx, c.kube, mg)
+	if err != nil {
 		return nil, errors.Wrap(err, errGetPC)
 	}
 	cd_domain := pc_domain.Spec.Credentials
@@ -184,7 +254,10 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 	if err != nil {
 		return nil, errors.Wrap(err, errNewClient)
 	}
-	return &external{service: svc}, err
+	if err := providerconfig.Configure(svc.S4tClient, pc_domain); err != nil {
+		return nil, errors.Wrap(err, errNewClient)
+	}
+	return &external{service: svc, kube: c.kube, recorder: c.recorder}, err
 }
 
 func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
@@ -193,9 +266,15 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		return managed.ExternalObservation{}, errors.New(errNotBoardPluginInjection)
 	}
 	fmt.Printf("Observing BoardPluginInjection: %+v", cr)
-	
+
+	// An expired injection deletes itself; Delete then removes the plugin.
+	cr.Status.AtProvider.ExpiresAt = expiry.At(cr, cr.Spec.ForProvider.TTL, cr.Spec.ForProvider.ExpiresAt)
+	if expired, err := expiry.Check(ctx, c.kube, c.recorder, cr, cr.Status.AtProvider.ExpiresAt); err != nil || expired {
+		return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, err
+	}
+
 	// Verify that the plugin is actually injected by checking the board's plugins
-	plugins, err := c.service.S4tClient.GetBoardPlugins(cr.Spec.ForProvider.BoardUuid)
+	plugins, err := c.board().GetBoardPlugins(cr.Spec.ForProvider.BoardUuid)
 	if err != nil {
 		log.Printf("####ERROR-LOG#### Error s4t client GetBoardPlugins %q", err)
 		// If we can't verify, assume it exists but mark as not up-to-date
@@ -207,15 +286,48 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	}
 	
 	// Check if our plugin is in the list
-	found := false
+	found, status := false, ""
 	for _, p := range plugins {
 		if p.Plugin == cr.Spec.ForProvider.PluginUuid {
-			found = true
+			found, status = true, p.Status
 			break
 		}
 	}
 	
 	if !found {
+		// Injection fails unless Lightning Rod is connected. Rather than
+		// calling it on an offline or merely registered board, report the
+		// injection as pending; the Device watch requeues us once the board
+		// comes online.
+		if !meta.WasDeleted(cr) {
+			board, err := c.board().GetBoardDetail(cr.Spec.ForProvider.BoardUuid)
+			if err != nil {
+				log.Printf("####ERROR-LOG#### Error s4t client Board Get %q", err)
+				return managed.ExternalObservation{}, err
+			}
+			if board.Uuid != "" && board.Status != boardStatusOnline {
+				cr.Status.SetConditions(v1alpha1.WaitingForBoard(board.Status))
+				return managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+				}, nil
+			}
+
+			// Injecting can interrupt what the board is running, so it is
+			// held off until the board's maintenance window opens.
+			deferred, err := maintenance.Check(ctx, c.kube, cr, cr.Spec.ForProvider.BoardUuid)
+			if err != nil {
+				return managed.ExternalObservation{}, err
+			}
+			if deferred != nil {
+				cr.Status.SetConditions(deferred.Condition())
+				return managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+				}, nil
+			}
+		}
+
 		// Plugin is not injected, resource doesn't exist yet
 		return managed.ExternalObservation{
 			ResourceExists:   false,
@@ -224,7 +336,44 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		}, nil
 	}
 	
+	cr.Status.AtProvider.Status = status
 	cr.Status.SetConditions(v1.Available())
+
+	if !meta.WasDeleted(cr) && cr.Spec.ForProvider.State != nil {
+		_, hash, err := c.parameters(ctx, cr)
+		if err != nil {
+			cr.Status.SetConditions(v1alpha1.InvalidParameters(err))
+			return managed.ExternalObservation{
+				ResourceExists:   true,
+				ResourceUpToDate: true,
+			}, nil
+		}
+		if action(cr, hash) == "" {
+			return managed.ExternalObservation{
+				ResourceExists:   true,
+				ResourceUpToDate: true,
+			}, nil
+		}
+
+		// Starting and stopping the plugin are held off, like injecting
+		// it, until the board's maintenance window opens.
+		deferred, err := maintenance.Check(ctx, c.kube, cr, cr.Spec.ForProvider.BoardUuid)
+		if err != nil {
+			return managed.ExternalObservation{}, err
+		}
+		if deferred != nil {
+			cr.Status.SetConditions(deferred.Condition())
+			return managed.ExternalObservation{
+				ResourceExists:   true,
+				ResourceUpToDate: true,
+			}, nil
+		}
+		return managed.ExternalObservation{
+			ResourceExists:   true,
+			ResourceUpToDate: false,
+		}, nil
+	}
+
 	return managed.ExternalObservation{
 		ResourceExists:   true,
 		ResourceUpToDate: true,
@@ -232,6 +381,82 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	}, nil
 }
 
+// parameters returns the parameters of cr rendered for its board, with the
+// values of its Secrets merged in, once they match the schema of its Plugin,
+// if any, and their hash. They must not be logged or recorded; the hash may.
+func (c *external) parameters(ctx context.Context, cr *v1alpha1.BoardPluginInjection) ([]byte, string, error) {
+	raw := cr.Spec.ForProvider.Parameters.Raw
+	from := cr.Spec.ForProvider.ParametersFrom
+	if len(raw) == 0 && len(from) == 0 {
+		return nil, parametersHash(nil, nil), nil
+	}
+	if params.Templated(raw) {
+		d, err := params.ForBoard(ctx, c.kube, cr.Spec.ForProvider.BoardUuid, cr.Spec.ForProvider.TemplateValues)
+		if err != nil {
+			return nil, "", err
+		}
+		if raw, err = params.Render(raw, d); err != nil {
+			return nil, "", err
+		}
+	}
+	versions, err := params.Versions(ctx, c.kube, from)
+	if err != nil {
+		return nil, "", err
+	}
+	hash := parametersHash(raw, versions)
+	merged, err := params.Merge(ctx, c.kube, raw, from)
+	if err != nil {
+		return nil, "", err
+	}
+	name := ""
+	if ref := cr.Spec.ForProvider.PluginRef; ref != nil {
+		name = ref.Name
+	}
+	schema, err := params.SchemaFor(ctx, c.kube, name, cr.Spec.ForProvider.PluginUuid)
+	if err != nil || schema == nil {
+		return merged, hash, err
+	}
+	return merged, hash, schema.Validate(field.NewPath("spec", "forProvider", "parameters"), merged, from).ToAggregate()
+}
+
+// parametersHash returns the SHA-256 of rendered parameters and the versions
+// of the Secrets their values are set from. It never covers the secret values
+// themselves, which could be recovered from the hash of short ones.
+func parametersHash(rendered, versions []byte) string {
+	h := sha256.New()
+	h.Write(rendered)
+	if len(versions) > 0 {
+		h.Write([]byte{0})
+		h.Write(versions)
+	}
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
+
+// action returns the action that brings the injected plugin to the state of
+// cr: PluginStart, PluginStop, or PluginRestart to restart a running plugin
+// whose parameters changed, as told by their hash. It returns "" for a
+// plugin in the desired state.
+func action(cr *v1alpha1.BoardPluginInjection, hash string) string {
+	if cr.Spec.ForProvider.State == nil {
+		return ""
+	}
+	running := cr.Status.AtProvider.Status == pluginStatusRunning
+	switch *cr.Spec.ForProvider.State {
+	case v1alpha1.PluginRunning:
+		if !running {
+			return "PluginStart"
+		}
+		if cr.Status.AtProvider.ParametersHash != hash {
+			return "PluginRestart"
+		}
+	case v1alpha1.PluginStopped:
+		if running {
+			return "PluginStop"
+		}
+	}
+	return ""
+}
+
 func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
 	cr, ok := mg.(*v1alpha1.BoardPluginInjection)
 	if !ok {
@@ -253,14 +478,107 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 	}, nil
 }
 
-// Update is a no-op for BoardPluginInjection.
-// Plugin injections cannot be updated; they must be deleted and recreated.
+// Update starts or stops the injected plugin according to spec.forProvider.state.
+// The board and plugin cannot be changed; the injection must be deleted and
+// recreated.
 func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*v1alpha1.BoardPluginInjection)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotBoardPluginInjection)
+	}
+	fmt.Printf("Updating BoardPluginInjection: %+v", cr)
+
+	board, plugin := cr.Spec.ForProvider.BoardUuid, cr.Spec.ForProvider.PluginUuid
+	rendered, hash, err := c.parameters(ctx, cr)
+	if err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+	var parameters interface{}
+	if len(rendered) > 0 {
+		if err := json.Unmarshal(rendered, &parameters); err != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, "cannot decode plugin parameters")
+		}
+	}
+
+	switch action(cr, hash) {
+	case "PluginStop":
+		if err := c.pluginAction(board, plugin, "PluginStop", nil); err != nil {
+			return managed.ExternalUpdate{}, err
+		}
+		c.recorder.Event(cr, event.Normal(reasonPluginStopped, "Stopped plugin"))
+	case "PluginRestart":
+		if err := c.pluginAction(board, plugin, "PluginStop", nil); err != nil {
+			return managed.ExternalUpdate{}, err
+		}
+		fallthrough
+	case "PluginStart":
+		if err := c.pluginAction(board, plugin, "PluginStart", parameters); err != nil {
+			return managed.ExternalUpdate{}, err
+		}
+		cr.Status.AtProvider.ParametersHash = hash
+		c.recorder.Event(cr, event.Normal(reasonPluginStarted, "Started plugin"))
+	}
+
 	return managed.ExternalUpdate{
 		ConnectionDetails: managed.ConnectionDetails{},
 	}, nil
 }
 
+// pluginAction performs an action on the plugin injected into a board.
+// API: POST /v1/boards/{board_uuid}/plugins/{plugin_uuid}
+// Request Body: {"action": "PluginStart|PluginStop", "parameters": {...}}
+func (c *external) pluginAction(board, plugin, action string, params interface{}) error {
+	data := map[string]interface{}{"action": action}
+	if params != nil {
+		data["parameters"] = params
+	}
+	resp, err := c.makeRESTCall("POST", fmt.Sprintf("/boards/%s/plugins/%s", board, plugin), data)
+	if err != nil {
+		log.Printf("####ERROR-LOG#### Error plugin action %s %q", action, err)
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("unexpected status code: %d, body: %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+// makeRESTCall makes a REST API call to the IoTronic service
+func (c *external) makeRESTCall(method, path string, data interface{}) (*http.Response, error) {
+	baseURL := fmt.Sprintf("%s:%s", c.service.S4tClient.Endpoint, c.service.S4tClient.Port)
+	url := fmt.Sprintf("%s/v1%s", baseURL, path)
+
+	var reqBody io.Reader
+	if data != nil {
+		jsonData, err := json.Marshal(data)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal request data")
+		}
+		reqBody = bytes.NewBuffer(jsonData)
+	}
+
+	req, err := http.NewRequest(method, url, reqBody)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create request")
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if c.service.S4tClient.AuthToken != "" {
+		req.Header.Set("X-Auth-Token", c.service.S4tClient.AuthToken)
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to execute request")
+	}
+
+	return resp, nil
+}
+
 // Delete removes an injected plugin from a board via IoTronic API.
 // API: DELETE /v1/boards/{board_uuid}/plugins/{plugin_uuid}
 // Response: 204 No Content on success
@@ -270,6 +588,19 @@ func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
 		return errors.New(errNotBoardPluginInjection)
 	}
 	fmt.Printf("Deleting: %+v", cr)
+
+	// Removal is requested again, without an error, until the board's
+	// maintenance window opens, unless the injection expired.
+	if !expiry.Expired(cr) {
+		deferred, err := maintenance.Check(ctx, c.kube, cr, cr.Spec.ForProvider.BoardUuid)
+		if err != nil {
+			return err
+		}
+		if deferred != nil {
+			cr.Status.SetConditions(deferred.DeletionCondition())
+			return nil
+		}
+	}
 	err := c.service.S4tClient.RemoveInjectedPlugin(cr.Spec.ForProvider.PluginUuid, cr.Spec.ForProvider.BoardUuid)
 	if err != nil {
 		log.Printf("####ERROR-LOG#### Error s4t client BoardPlugin Delete %q", err)
@@ -18,13 +18,29 @@ package boardplugininjection
 
 import (
 	"context"
+	"crypto/sha256"
+	"fmt"
 	"testing"
 
+	boards "github.com/MIKE9708/s4t-sdk-go/pkg/api/data/board"
+	plugins "github.com/MIKE9708/s4t-sdk-go/pkg/api/data/plugin"
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	ctrlevent "sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/provider-s4t/apis/iot/v1alpha1"
 )
 
 // Unlike many Kubernetes projects Crossplane does not use third party testing
@@ -68,3 +84,220 @@ func TestObserve(t *testing.T) {
 		})
 	}
 }
+
+func TestParametersHash(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "iot", Name: "cloud", UID: "cloud-uid", Labels: map[string]string{v1alpha1.LabelParameterSource: "true"}},
+		Data:       map[string][]byte{"apiKey": []byte("1234")},
+	}
+	kube := fake.NewClientBuilder().WithObjects(secret).Build()
+	cr := &v1alpha1.BoardPluginInjection{}
+	cr.Spec.ForProvider.Parameters = runtime.RawExtension{Raw: []byte(`{"interval":5}`)}
+	cr.Spec.ForProvider.ParametersFrom = []v1alpha1.ParameterFrom{{
+		Name: "apiKey",
+		SecretKeyRef: xpv1.SecretKeySelector{
+			SecretReference: xpv1.SecretReference{Namespace: "iot", Name: "cloud"},
+			Key:             "apiKey",
+		},
+	}}
+	e := &external{kube: kube}
+
+	merged, hash, err := e.parameters(context.Background(), cr)
+	if err != nil {
+		t.Fatalf("parameters: %v", err)
+	}
+	if diff := cmp.Diff(`{"apiKey":"1234","interval":5}`, string(merged)); diff != "" {
+		t.Errorf("parameters: -want, +got:\n%s", diff)
+	}
+	// The hash is recorded in the status, so it must not be a hash of the
+	// secret value that could be brute-forced.
+	if hash == fmt.Sprintf("%x", sha256.Sum256(merged)) {
+		t.Error("parameters: hash covers the merged secret value")
+	}
+
+	secret.Data["apiKey"] = []byte("5678")
+	if err := kube.Update(context.Background(), secret); err != nil {
+		t.Fatal(err)
+	}
+	_, changed, err := e.parameters(context.Background(), cr)
+	if err != nil {
+		t.Fatalf("parameters: %v", err)
+	}
+	if changed == hash {
+		t.Error("parameters: want a change to the Secret to change the hash")
+	}
+}
+
+func newKube(t *testing.T, objs ...client.Object) client.Client {
+	t.Helper()
+	s := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := v1alpha1.SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	return fake.NewClientBuilder().WithScheme(s).WithObjects(objs...).Build()
+}
+
+func injection(name, board string, ref *xpv1.Reference) *v1alpha1.BoardPluginInjection {
+	i := &v1alpha1.BoardPluginInjection{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	i.Spec.ForProvider.BoardUuid = board
+	i.Spec.ForProvider.BoardRef = ref
+	i.Spec.ForProvider.PluginUuid = "plugin-1"
+	return i
+}
+
+func TestInjectionsForDevice(t *testing.T) {
+	kube := newKube(t,
+		injection("by-uuid", "board-1", nil),
+		injection("by-ref", "", &xpv1.Reference{Name: "rpi"}),
+		injection("other", "board-2", &xpv1.Reference{Name: "other"}),
+	)
+	device := func(name, uuid string) *v1alpha1.Device {
+		d := &v1alpha1.Device{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		d.Spec.ForProvider.Uuid = uuid
+		return d
+	}
+	request := func(name string) reconcile.Request {
+		return reconcile.Request{NamespacedName: types.NamespacedName{Name: name}}
+	}
+
+	cases := map[string]struct {
+		reason string
+		obj    client.Object
+		want   []reconcile.Request
+	}{
+		"ByUUIDAndRef": {
+			reason: "Injections should be mapped by the board UUID and by reference to the Device.",
+			obj:    device("rpi", "board-1"),
+			want:   []reconcile.Request{request("by-ref"), request("by-uuid")},
+		},
+		"ByRef": {
+			reason: "A Device without a UUID yet should map the injections referring to it.",
+			obj:    device("rpi", ""),
+			want:   []reconcile.Request{request("by-ref")},
+		},
+		"NoUUIDMatch": {
+			reason: "An empty UUID should not match injections without a board UUID.",
+			obj:    device("unknown", ""),
+		},
+		"NotADevice": {
+			reason: "Objects other than Devices should map to nothing.",
+			obj:    &v1alpha1.Plugin{},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := injectionsForDevice(kube)(context.Background(), tc.obj)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\ninjectionsForDevice(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestBoardStateChanged(t *testing.T) {
+	device := func(status string, ready xpv1.Condition) *v1alpha1.Device {
+		d := &v1alpha1.Device{}
+		d.Status.Status = status
+		d.SetConditions(ready)
+		return d
+	}
+	labelled := device("online", xpv1.Available())
+	labelled.SetLabels(map[string]string{"site": "lab"})
+
+	cases := map[string]struct {
+		reason string
+		old    client.Object
+		new    client.Object
+		want   bool
+	}{
+		"StatusChanged": {
+			reason: "A board coming online should pass.",
+			old:    device("offline", xpv1.Available()),
+			new:    device("online", xpv1.Available()),
+			want:   true,
+		},
+		"ReadyChanged": {
+			reason: "A Device becoming Ready should pass.",
+			old:    device("online", xpv1.Creating()),
+			new:    device("online", xpv1.Available()),
+			want:   true,
+		},
+		"Unchanged": {
+			reason: "An update to anything else should not pass.",
+			old:    device("online", xpv1.Available()),
+			new:    labelled,
+		},
+		"NotADevice": {
+			reason: "Updates to other objects should not pass.",
+			old:    &v1alpha1.Plugin{},
+			new:    &v1alpha1.Plugin{},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := boardStateChanged.Update(ctrlevent.UpdateEvent{ObjectOld: tc.old, ObjectNew: tc.new})
+			if got != tc.want {
+				t.Errorf("\n%s\nboardStateChanged.Update(...): want %t, got %t", tc.reason, tc.want, got)
+			}
+		})
+	}
+	if boardStateChanged.Create(ctrlevent.CreateEvent{Object: device("online", xpv1.Available())}) {
+		t.Error("boardStateChanged.Create(...): want false")
+	}
+}
+
+// fakeBoards is a board that has no plugin injected.
+type fakeBoards struct {
+	board boards.Board
+}
+
+func (f *fakeBoards) GetBoardDetail(string) (*boards.Board, error) { return &f.board, nil }
+
+func (f *fakeBoards) GetBoardPlugins(string) ([]plugins.PluginBoard, error) { return nil, nil }
+
+func TestObserveBoardState(t *testing.T) {
+	cases := map[string]struct {
+		reason  string
+		board   boards.Board
+		want    managed.ExternalObservation
+		waiting bool
+	}{
+		"Offline": {
+			reason:  "An injection into an offline board should wait for it rather than be created.",
+			board:   boards.Board{Uuid: "board-1", Status: "offline"},
+			want:    managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			waiting: true,
+		},
+		"Registered": {
+			reason:  "An injection into a board that never connected should wait for it.",
+			board:   boards.Board{Uuid: "board-1", Status: "registered"},
+			want:    managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			waiting: true,
+		},
+		"Online": {
+			reason: "An injection into an online board should be created.",
+			board:  boards.Board{Uuid: "board-1", Status: "online"},
+			want:   managed.ExternalObservation{ConnectionDetails: managed.ConnectionDetails{}},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := injection("collector", "board-1", nil)
+			e := &external{kube: newKube(t), boards: &fakeBoards{board: tc.board}}
+			got, err := e.Observe(context.Background(), cr)
+			if err != nil {
+				t.Fatalf("\n%s\ne.Observe(...): %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			c := cr.GetCondition(xpv1.TypeReady)
+			if waiting := c.Reason == v1alpha1.ReasonWaitingForBoard; waiting != tc.waiting {
+				t.Errorf("\n%s\ne.Observe(...): want waiting for board %t, got condition %+v", tc.reason, tc.waiting, c)
+			}
+		})
+	}
+}
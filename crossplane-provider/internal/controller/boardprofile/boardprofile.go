@@ -0,0 +1,471 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package boardprofile
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/connection"
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlevent "sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/provider-s4t/apis/iot/v1alpha1"
+	apisv1alpha1 "github.com/crossplane/provider-s4t/apis/v1alpha1"
+	"github.com/crossplane/provider-s4t/internal/features"
+	"github.com/crossplane/provider-s4t/internal/providerconfig"
+)
+
+const (
+	errNotBoardProfile = "managed resource is not a BoardProfile custom resource"
+	errTrackPCUsage    = "cannot track ProviderConfig usage"
+	errListDevices     = "cannot list Devices"
+	errSelector        = "cannot parse device selector"
+	errListChildren    = "cannot list owned resources"
+	errApplyChild      = "cannot create owned resource"
+	errGetChild        = "cannot get owned resource"
+	errGetPlugin       = "cannot get Plugin"
+	errNotControlled   = "resource %q exists and is not controlled by this BoardProfile"
+	errUpdateChild     = "cannot update owned resource"
+	errDeleteChild     = "cannot delete owned resource"
+
+	reasonItemAdded       event.Reason = "ItemAdded"
+	reasonItemRemoved     event.Reason = "ItemRemoved"
+	reasonItemOverlapping event.Reason = "ItemOverlapping"
+)
+
+// Setup adds a controller that reconciles BoardProfile managed resources.
+// BoardProfiles have no IoTronic counterpart; the resources they own act on
+// the boards.
+func Setup(mgr ctrl.Manager, o controller.Options) error {
+	name := managed.ControllerName(v1alpha1.BoardProfileGroupKind)
+
+	cps := []managed.ConnectionPublisher{managed.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme())}
+	if o.Features.Enabled(features.EnableAlphaExternalSecretStores) {
+		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), apisv1alpha1.StoreConfigGroupVersionKind))
+	}
+
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
+
+	r := managed.NewReconciler(mgr,
+		resource.ManagedKind(v1alpha1.BoardProfileGroupVersionKind),
+		managed.WithExternalConnecter(&connector{
+			kube:     mgr.GetClient(),
+			usage:    providerconfig.NewTracker(mgr.GetClient()),
+			recorder: recorder}),
+		managed.WithLogger(o.Logger.WithValues("controller", name)),
+		managed.WithPollInterval(o.PollInterval),
+		managed.WithRecorder(recorder),
+		managed.WithConnectionPublishers(cps...))
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		For(&v1alpha1.BoardProfile{}, builder.WithPredicates(resource.DesiredStateChanged())).
+		Owns(&v1alpha1.BoardPluginInjection{}).
+		Owns(&v1alpha1.BoardServiceInjection{}).
+		Owns(&v1alpha1.Webservice{}).
+		Watches(&v1alpha1.Device{},
+			handler.EnqueueRequestsFromMapFunc(allBoardProfiles(mgr.GetClient())),
+			builder.WithPredicates(membershipChanged)).
+		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+}
+
+// allBoardProfiles maps a Device to every BoardProfile, so that boards are
+// converged or released as soon as they start or stop matching rather than
+// on the next poll.
+func allBoardProfiles(kube client.Client) handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		l := &v1alpha1.BoardProfileList{}
+		if err := kube.List(ctx, l); err != nil {
+			log.Printf("Error listing BoardProfiles for Device %s: %v", obj.GetName(), err)
+			return nil
+		}
+		reqs := make([]reconcile.Request, 0, len(l.Items))
+		for _, p := range l.Items {
+			reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{Name: p.GetName()}})
+		}
+		return reqs
+	}
+}
+
+// membershipChanged passes Device events that can change which BoardProfiles
+// select it: creation, deletion, and changes to its labels.
+var membershipChanged = predicate.Funcs{
+	GenericFunc: func(ctrlevent.GenericEvent) bool { return false },
+	UpdateFunc: func(e ctrlevent.UpdateEvent) bool {
+		return !labels.Equals(e.ObjectOld.GetLabels(), e.ObjectNew.GetLabels()) ||
+			meta.WasDeleted(e.ObjectOld) != meta.WasDeleted(e.ObjectNew)
+	},
+}
+
+// A connector is expected to produce an ExternalClient when its Connect method
+// is called.
+type connector struct {
+	kube     client.Client
+	usage    resource.Tracker
+	recorder event.Recorder
+}
+
+// Connect only tracks ProviderConfig usage; a BoardProfile is reconciled
+// against the Kubernetes API. The ProviderConfig is passed on to the
+// resources it owns.
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	_, ok := mg.(*v1alpha1.BoardProfile)
+	if !ok {
+		return nil, errors.New(errNotBoardProfile)
+	}
+
+	if err := c.usage.Track(ctx, mg); err != nil {
+		return nil, errors.Wrap(err, errTrackPCUsage)
+	}
+
+	return &external{kube: c.kube, recorder: c.recorder}, nil
+}
+
+// An ExternalClient observes, then either creates, updates, or deletes an
+// external resource to ensure it reflects the managed resource's desired state.
+// For a BoardProfile the "external" resources are the BoardPluginInjections,
+// BoardServiceInjections and Webservices it owns.
+type external struct {
+	kube     client.Client
+	recorder event.Recorder
+}
+
+// matchingDevices returns the Devices cr applies to, keyed by name.
+func (c *external) matchingDevices(ctx context.Context, cr *v1alpha1.BoardProfile) (map[string]*v1alpha1.Device, error) {
+	sel, err := metav1.LabelSelectorAsSelector(&cr.Spec.ForProvider.DeviceSelector)
+	if err != nil {
+		return nil, errors.Wrap(err, errSelector)
+	}
+	l := &v1alpha1.DeviceList{}
+	if err := c.kube.List(ctx, l, client.MatchingLabelsSelector{Selector: sel}); err != nil {
+		return nil, errors.Wrap(err, errListDevices)
+	}
+	devices := map[string]*v1alpha1.Device{}
+	for i := range l.Items {
+		if d := &l.Items[i]; !meta.WasDeleted(d) {
+			devices[d.GetName()] = d
+		}
+	}
+	return devices, nil
+}
+
+func refName(ref *xpv1.Reference) string {
+	if ref == nil {
+		return ""
+	}
+	return ref.Name
+}
+
+// ownedResources returns the resources controlled by cr, keyed by key.
+func (c *external) ownedResources(ctx context.Context, cr *v1alpha1.BoardProfile) (map[string]owned, error) {
+	sel := client.MatchingLabels{v1alpha1.LabelBoardProfile: cr.GetName()}
+	out := map[string]owned{}
+	add := func(mg resource.Managed, device, item string) {
+		if metav1.IsControlledBy(mg, cr) {
+			out[key(device, item)] = owned{Managed: mg, device: device, item: item}
+		}
+	}
+
+	pl := &v1alpha1.BoardPluginInjectionList{}
+	if err := c.kube.List(ctx, pl, sel); err != nil {
+		return nil, errors.Wrap(err, errListChildren)
+	}
+	for i := range pl.Items {
+		p := &pl.Items[i]
+		add(p, refName(p.Spec.ForProvider.BoardRef), kindPlugin+"/"+refName(p.Spec.ForProvider.PluginRef))
+	}
+
+	sl := &v1alpha1.BoardServiceInjectionList{}
+	if err := c.kube.List(ctx, sl, sel); err != nil {
+		return nil, errors.Wrap(err, errListChildren)
+	}
+	for i := range sl.Items {
+		s := &sl.Items[i]
+		add(s, refName(s.Spec.ForProvider.BoardRef), kindService+"/"+refName(s.Spec.ForProvider.ServiceRef))
+	}
+
+	wl := &v1alpha1.WebserviceList{}
+	if err := c.kube.List(ctx, wl, sel); err != nil {
+		return nil, errors.Wrap(err, errListChildren)
+	}
+	for i := range wl.Items {
+		w := &wl.Items[i]
+		add(w, refName(w.Spec.ForProvider.BoardRef), kindWebservice+"/"+w.Spec.ForProvider.Name)
+	}
+	return out, nil
+}
+
+// overlapping returns the plugin items of cr that a BoardPluginInjection cr
+// does not control already injects into the board of one of devices, keyed
+// by key, with the kind and name of what owns that injection. Two injections
+// of the same plugin into the same board would fight over its state and
+// parameters, and removing the profile's would remove a plugin it never
+// injected, so the injection that got there first keeps it. Injections are
+// matched by their resolved board and plugin UUIDs as well as by reference.
+func (c *external) overlapping(ctx context.Context, cr *v1alpha1.BoardProfile, devices map[string]*v1alpha1.Device) (map[string]string, error) {
+	boards := make(map[string]string, len(devices))
+	for name, d := range devices {
+		if d.Spec.ForProvider.Uuid != "" {
+			boards[d.Spec.ForProvider.Uuid] = name
+		}
+	}
+	plugins := map[string]string{}
+	for _, pl := range cr.Spec.ForProvider.Plugins {
+		p := &v1alpha1.Plugin{}
+		if err := c.kube.Get(ctx, types.NamespacedName{Name: pl.PluginRef}, p); resource.IgnoreNotFound(err) != nil {
+			return nil, errors.Wrap(err, errGetPlugin)
+		}
+		if p.Spec.ForProvider.Uuid != "" {
+			plugins[p.Spec.ForProvider.Uuid] = pl.PluginRef
+		}
+	}
+
+	l := &v1alpha1.BoardPluginInjectionList{}
+	if err := c.kube.List(ctx, l); err != nil {
+		return nil, errors.Wrap(err, errListChildren)
+	}
+	out := map[string]string{}
+	for i := range l.Items {
+		p := &l.Items[i]
+		if metav1.IsControlledBy(p, cr) || meta.WasDeleted(p) {
+			continue
+		}
+		device := refName(p.Spec.ForProvider.BoardRef)
+		if _, ok := devices[device]; !ok {
+			device = boards[p.Spec.ForProvider.BoardUuid]
+		}
+		plugin := refName(p.Spec.ForProvider.PluginRef)
+		if plugin == "" {
+			plugin = plugins[p.Spec.ForProvider.PluginUuid]
+		}
+		if device == "" || plugin == "" {
+			continue
+		}
+		owner := v1alpha1.BoardPluginInjectionKind + " " + p.GetName()
+		if profile := p.GetLabels()[v1alpha1.LabelBoardProfile]; profile != "" {
+			owner = v1alpha1.BoardProfileKind + " " + profile
+		}
+		out[key(device, kindPlugin+"/"+plugin)] = owner
+	}
+	return out, nil
+}
+
+// wanted returns the resources cr should own for devices, less the ones
+// another injection already implements and cr does not own.
+func wanted(cr *v1alpha1.BoardProfile, devices map[string]*v1alpha1.Device, owned map[string]owned, overlaps map[string]string) map[string]resource.Managed {
+	want := desired(cr, devices)
+	for k := range overlaps {
+		if _, ok := owned[k]; !ok {
+			delete(want, k)
+		}
+	}
+	return want
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha1.BoardProfile)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotBoardProfile)
+	}
+
+	fmt.Printf("Observing BoardProfile: %+v", cr)
+
+	owned, err := c.ownedResources(ctx, cr)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+	if meta.WasDeleted(cr) {
+		return managed.ExternalObservation{ResourceExists: len(owned) > 0}, nil
+	}
+
+	devices, err := c.matchingDevices(ctx, cr)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+	overlaps, err := c.overlapping(ctx, cr, devices)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+	want := wanted(cr, devices, owned, overlaps)
+
+	upToDate := len(owned) == len(want)
+	for k, o := range owned {
+		w, ok := want[k]
+		if !ok || converge(o.Managed.DeepCopyObject().(resource.Managed), w) {
+			upToDate = false
+		}
+	}
+
+	obs := conformance(cr, devices, owned, overlaps)
+	cr.Status.AtProvider = obs
+	if obs.Conformant == obs.Matched {
+		cr.Status.SetConditions(xpv1.Available())
+	} else {
+		cr.Status.SetConditions(xpv1.Unavailable().WithMessage(
+			fmt.Sprintf("%d of %d boards conformant", obs.Conformant, obs.Matched)))
+	}
+
+	return managed.ExternalObservation{
+		ResourceExists:    len(owned) > 0 || len(want) == 0,
+		ResourceUpToDate:  upToDate,
+		ConnectionDetails: managed.ConnectionDetails{},
+	}, nil
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1alpha1.BoardProfile)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotBoardProfile)
+	}
+
+	fmt.Printf("Creating BoardProfile: %+v", cr)
+
+	return managed.ExternalCreation{
+		ConnectionDetails: managed.ConnectionDetails{},
+	}, c.sync(ctx, cr)
+}
+
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*v1alpha1.BoardProfile)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotBoardProfile)
+	}
+
+	fmt.Printf("Updating BoardProfile: %+v", cr)
+
+	return managed.ExternalUpdate{
+		ConnectionDetails: managed.ConnectionDetails{},
+	}, c.sync(ctx, cr)
+}
+
+// sync converges every matching board to the items of cr: it deletes the
+// owned resources for items no longer listed or Devices no longer matching,
+// updates those whose state, parameters or port changed, and creates the
+// missing ones, unless another injection already put the plugin on the
+// board.
+func (c *external) sync(ctx context.Context, cr *v1alpha1.BoardProfile) error {
+	devices, err := c.matchingDevices(ctx, cr)
+	if err != nil {
+		return err
+	}
+	owned, err := c.ownedResources(ctx, cr)
+	if err != nil {
+		return err
+	}
+	overlaps, err := c.overlapping(ctx, cr, devices)
+	if err != nil {
+		return err
+	}
+	want := wanted(cr, devices, owned, overlaps)
+
+	for k, o := range owned {
+		w, ok := want[k]
+		if !ok {
+			if err := c.kube.Delete(ctx, o.Managed); resource.IgnoreNotFound(err) != nil {
+				return errors.Wrap(err, errDeleteChild)
+			}
+			c.recorder.Event(cr, event.Normal(reasonItemRemoved, fmt.Sprintf("Removing %s from the board of Device %s", o.item, o.device)))
+			continue
+		}
+		if converge(o.Managed, w) {
+			if err := c.kube.Update(ctx, o.Managed); err != nil {
+				return errors.Wrap(err, errUpdateChild)
+			}
+		}
+	}
+
+	for name := range devices {
+		for _, item := range items(cr.Spec.ForProvider) {
+			k := key(name, item)
+			if _, ok := owned[k]; ok {
+				continue
+			}
+			w, ok := want[k]
+			if !ok {
+				c.recorder.Event(cr, event.Warning(reasonItemOverlapping, errors.Errorf("not adding %s to the board of Device %s: %s already injects it", item, name, overlaps[k])))
+				continue
+			}
+			if err := c.create(ctx, cr, w); err != nil {
+				return err
+			}
+			c.recorder.Event(cr, event.Normal(reasonItemAdded, fmt.Sprintf("Adding %s to the board of Device %s", item, name)))
+		}
+	}
+	return nil
+}
+
+// create creates mg, unless it already exists and is controlled by cr, for
+// example because it was created since cr last listed what it owns.
+func (c *external) create(ctx context.Context, cr *v1alpha1.BoardProfile, mg resource.Managed) error {
+	err := c.kube.Create(ctx, mg)
+	if !kerrors.IsAlreadyExists(err) {
+		return errors.Wrap(err, errApplyChild)
+	}
+	existing, ok := mg.DeepCopyObject().(resource.Managed)
+	if !ok {
+		return errors.Wrap(err, errApplyChild)
+	}
+	if err := c.kube.Get(ctx, types.NamespacedName{Name: mg.GetName()}, existing); err != nil {
+		return errors.Wrap(err, errGetChild)
+	}
+	if !metav1.IsControlledBy(existing, cr) {
+		return errors.Errorf(errNotControlled, mg.GetName())
+	}
+	return nil
+}
+
+// Delete removes every owned resource. Each of them in turn removes its
+// plugin, service or webservice from the board before it goes away.
+func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(*v1alpha1.BoardProfile)
+	if !ok {
+		return errors.New(errNotBoardProfile)
+	}
+
+	fmt.Printf("Deleting BoardProfile: %+v", cr)
+
+	owned, err := c.ownedResources(ctx, cr)
+	if err != nil {
+		return err
+	}
+	for _, o := range owned {
+		if err := c.kube.Delete(ctx, o.Managed); resource.IgnoreNotFound(err) != nil {
+			return errors.Wrap(err, errDeleteChild)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package boardprofile
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/provider-s4t/apis/iot/v1alpha1"
+	"github.com/crossplane/provider-s4t/internal/names"
+	"github.com/crossplane/provider-s4t/internal/providerconfig"
+)
+
+// Item kinds, as they prefix item keys and appear in status.
+const (
+	kindPlugin     = "plugin"
+	kindService    = "service"
+	kindWebservice = "webservice"
+)
+
+// An owned resource is a BoardPluginInjection, BoardServiceInjection or
+// Webservice a BoardProfile owns for one item on one board.
+type owned struct {
+	resource.Managed
+
+	// device is the name of the Device the resource targets.
+	device string
+
+	// item is the profile item the resource implements, as kind/name.
+	item string
+}
+
+// key identifies the resource for an item on a Device.
+func key(device, item string) string {
+	return device + "/" + item
+}
+
+// items returns the items of p, as kind/name, in the order listed.
+func items(p v1alpha1.BoardProfileParameters) []string {
+	out := make([]string, 0, len(p.Plugins)+len(p.Services)+len(p.Webservices))
+	for _, pl := range p.Plugins {
+		out = append(out, kindPlugin+"/"+pl.PluginRef)
+	}
+	for _, s := range p.Services {
+		out = append(out, kindService+"/"+s.ServiceRef)
+	}
+	for _, w := range p.Webservices {
+		out = append(out, kindWebservice+"/"+w.Name)
+	}
+	return out
+}
+
+// desired returns the resources cr owns for the Devices it applies to, keyed
+// by key.
+func desired(cr *v1alpha1.BoardProfile, devices map[string]*v1alpha1.Device) map[string]resource.Managed {
+	out := map[string]resource.Managed{}
+	for name, d := range devices {
+		add := func(k string, mg resource.Managed) {
+			providerconfig.Inherit(mg, cr, d)
+			out[k] = mg
+		}
+		om := func(kind, item string) metav1.ObjectMeta {
+			return metav1.ObjectMeta{
+				Name:            names.Child(cr.GetName(), name, kind, item),
+				Labels:          map[string]string{v1alpha1.LabelBoardProfile: cr.GetName()},
+				OwnerReferences: []metav1.OwnerReference{meta.AsController(meta.TypedReferenceTo(cr, v1alpha1.BoardProfileGroupVersionKind))},
+			}
+		}
+		rs := xpv1.ResourceSpec{
+			ProviderConfigReference: cr.GetProviderConfigReference().DeepCopy(),
+			DeletionPolicy:          cr.GetDeletionPolicy(),
+		}
+		board := func() *xpv1.Reference { return &xpv1.Reference{Name: name} }
+
+		for _, p := range cr.Spec.ForProvider.Plugins {
+			add(key(name, kindPlugin+"/"+p.PluginRef), &v1alpha1.BoardPluginInjection{
+				ObjectMeta: om(kindPlugin, p.PluginRef),
+				Spec: v1alpha1.BoardPluginInjectionSpec{
+					ResourceSpec: rs,
+					ForProvider: v1alpha1.BoardPluginInjectionParameters{
+						BoardRef:       board(),
+						PluginRef:      &xpv1.Reference{Name: p.PluginRef},
+						State:          p.State,
+						Parameters:     *p.Parameters.DeepCopy(),
+						TemplateValues: append([]v1alpha1.TemplateValue(nil), p.TemplateValues...),
+						ParametersFrom: append([]v1alpha1.ParameterFrom(nil), p.ParametersFrom...),
+					},
+				},
+			})
+		}
+		for _, s := range cr.Spec.ForProvider.Services {
+			add(key(name, kindService+"/"+s.ServiceRef), &v1alpha1.BoardServiceInjection{
+				ObjectMeta: om(kindService, s.ServiceRef),
+				Spec: v1alpha1.BoardServiceInjectionSpec{
+					ResourceSpec: rs,
+					ForProvider: v1alpha1.BoardServiceInjectionParameters{
+						BoardRef:   board(),
+						ServiceRef: &xpv1.Reference{Name: s.ServiceRef},
+					},
+				},
+			})
+		}
+		for _, w := range cr.Spec.ForProvider.Webservices {
+			add(key(name, kindWebservice+"/"+w.Name), &v1alpha1.Webservice{
+				ObjectMeta: om(kindWebservice, w.Name),
+				Spec: v1alpha1.WebserviceSpec{
+					ResourceSpec: rs,
+					ForProvider: v1alpha1.WebserviceParameters{
+						Name:     w.Name,
+						Port:     w.Port,
+						Secure:   w.Secure,
+						BoardRef: board(),
+					},
+				},
+			})
+		}
+	}
+	return out
+}
+
+// converge copies the fields of want that a profile may change into got,
+// and reports whether any differed. The board and the referenced plugin,
+// service or webservice name identify the item and never differ.
+func converge(got, want resource.Managed) bool {
+	switch g := got.(type) {
+	case *v1alpha1.BoardPluginInjection:
+		w := want.(*v1alpha1.BoardPluginInjection)
+		if reflect.DeepEqual(g.Spec.ForProvider.State, w.Spec.ForProvider.State) &&
+			bytes.Equal(g.Spec.ForProvider.Parameters.Raw, w.Spec.ForProvider.Parameters.Raw) &&
+			reflect.DeepEqual(g.Spec.ForProvider.TemplateValues, w.Spec.ForProvider.TemplateValues) &&
+			reflect.DeepEqual(g.Spec.ForProvider.ParametersFrom, w.Spec.ForProvider.ParametersFrom) {
+			return false
+		}
+		g.Spec.ForProvider.State = w.Spec.ForProvider.State
+		g.Spec.ForProvider.Parameters = w.Spec.ForProvider.Parameters
+		g.Spec.ForProvider.TemplateValues = w.Spec.ForProvider.TemplateValues
+		g.Spec.ForProvider.ParametersFrom = w.Spec.ForProvider.ParametersFrom
+		return true
+	case *v1alpha1.Webservice:
+		w := want.(*v1alpha1.Webservice)
+		if g.Spec.ForProvider.Port == w.Spec.ForProvider.Port && g.Spec.ForProvider.Secure == w.Spec.ForProvider.Secure {
+			return false
+		}
+		g.Spec.ForProvider.Port = w.Spec.ForProvider.Port
+		g.Spec.ForProvider.Secure = w.Spec.ForProvider.Secure
+		return true
+	}
+	return false
+}
+
+// conformance reports, for every Device cr applies to, whether each of its
+// items is implemented by a ready resource, given the resources cr owns
+// keyed by key, and the items another injection implements on the board,
+// keyed by key, with what owns that injection.
+func conformance(cr *v1alpha1.BoardProfile, devices map[string]*v1alpha1.Device, owned map[string]owned, overlaps map[string]string) v1alpha1.BoardProfileObservation {
+	names := make([]string, 0, len(devices))
+	for name := range devices {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	obs := v1alpha1.BoardProfileObservation{Matched: len(names)}
+	for _, name := range names {
+		b := v1alpha1.BoardProfileBoard{Device: name}
+		for _, item := range items(cr.Spec.ForProvider) {
+			o, ok := owned[key(name, item)]
+			if other := overlaps[key(name, item)]; !ok && other != "" {
+				b.NotReady = append(b.NotReady, fmt.Sprintf("%s (Overlaps %s)", item, other))
+				continue
+			}
+			if !ok {
+				b.NotReady = append(b.NotReady, item+" (Missing)")
+				continue
+			}
+			ready := o.GetCondition(xpv1.TypeReady)
+			switch {
+			case ready.Status == corev1.ConditionTrue:
+			case ready.Reason != "":
+				b.NotReady = append(b.NotReady, fmt.Sprintf("%s (%s)", item, ready.Reason))
+			default:
+				b.NotReady = append(b.NotReady, item)
+			}
+		}
+		b.Conformant = len(b.NotReady) == 0
+		if b.Conformant {
+			obs.Conformant++
+		}
+		obs.Boards = append(obs.Boards, b)
+	}
+	return obs
+}
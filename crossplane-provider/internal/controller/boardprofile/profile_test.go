@@ -0,0 +1,204 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package boardprofile
+
+import (
+	"context"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/crossplane/provider-s4t/apis/iot/v1alpha1"
+	"github.com/crossplane/provider-s4t/internal/names"
+)
+
+func profile() *v1alpha1.BoardProfile {
+	cr := &v1alpha1.BoardProfile{ObjectMeta: metav1.ObjectMeta{Name: "gateway"}}
+	cr.Spec.ForProvider.Plugins = []v1alpha1.BoardProfilePlugin{{PluginRef: "collector", State: ptr.To(v1alpha1.PluginRunning)}}
+	cr.Spec.ForProvider.Services = []v1alpha1.BoardProfileService{{ServiceRef: "ssh"}}
+	cr.Spec.ForProvider.Webservices = []v1alpha1.BoardProfileWebservice{{Name: "dashboard", Port: 8080}}
+	return cr
+}
+
+func TestDesired(t *testing.T) {
+	cr := profile()
+	devices := map[string]*v1alpha1.Device{"a": {}, "b": {}}
+
+	got := map[string]string{}
+	for k, mg := range desired(cr, devices) {
+		got[k] = mg.GetName()
+	}
+	want := map[string]string{
+		"a/plugin/collector":     names.Child("gateway", "a", "plugin", "collector"),
+		"a/service/ssh":          names.Child("gateway", "a", "service", "ssh"),
+		"a/webservice/dashboard": names.Child("gateway", "a", "webservice", "dashboard"),
+		"b/plugin/collector":     names.Child("gateway", "b", "plugin", "collector"),
+		"b/service/ssh":          names.Child("gateway", "b", "service", "ssh"),
+		"b/webservice/dashboard": names.Child("gateway", "b", "webservice", "dashboard"),
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("desired: -want, +got:\n%s", diff)
+	}
+}
+
+func TestConverge(t *testing.T) {
+	cr := profile()
+	want := desired(cr, map[string]*v1alpha1.Device{"a": {}})
+
+	p := want["a/plugin/collector"].DeepCopyObject().(*v1alpha1.BoardPluginInjection)
+	if converge(p, want["a/plugin/collector"]) {
+		t.Errorf("converge: want no change for an identical injection")
+	}
+	p.Spec.ForProvider.State = ptr.To(v1alpha1.PluginStopped)
+	p.Spec.ForProvider.Parameters = runtime.RawExtension{Raw: []byte(`{"interval":5}`)}
+	if !converge(p, want["a/plugin/collector"]) {
+		t.Errorf("converge: want a change for a stopped injection")
+	}
+	if *p.Spec.ForProvider.State != v1alpha1.PluginRunning || len(p.Spec.ForProvider.Parameters.Raw) != 0 {
+		t.Errorf("converge: want state and parameters of the profile, got %+v", p.Spec.ForProvider)
+	}
+
+	w := want["a/webservice/dashboard"].DeepCopyObject().(*v1alpha1.Webservice)
+	w.Spec.ForProvider.Port = 9090
+	w.Spec.ForProvider.Uuid = "assigned"
+	if !converge(w, want["a/webservice/dashboard"]) || w.Spec.ForProvider.Port != 8080 || w.Spec.ForProvider.Uuid != "assigned" {
+		t.Errorf("converge: want port reset and UUID kept, got %+v", w.Spec.ForProvider)
+	}
+}
+
+func TestConformance(t *testing.T) {
+	cr := profile()
+	devices := map[string]*v1alpha1.Device{"a": {}, "b": {}}
+	want := desired(cr, devices)
+
+	resources := map[string]owned{}
+	for k, mg := range want {
+		mg.SetConditions(xpv1.Available())
+		resources[k] = owned{Managed: mg}
+	}
+	waiting := want["b/plugin/collector"]
+	waiting.SetConditions(v1alpha1.WaitingForBoard("offline"))
+	delete(resources, "b/service/ssh")
+
+	devices["c"] = &v1alpha1.Device{}
+	overlaps := map[string]string{"c/plugin/collector": "BoardProfile sensors"}
+
+	got := conformance(cr, devices, resources, overlaps)
+	exp := v1alpha1.BoardProfileObservation{
+		Matched:    3,
+		Conformant: 1,
+		Boards: []v1alpha1.BoardProfileBoard{
+			{Device: "a", Conformant: true},
+			{Device: "b", NotReady: []string{"plugin/collector (" + string(v1alpha1.ReasonWaitingForBoard) + ")", "service/ssh (Missing)"}},
+			{Device: "c", NotReady: []string{"plugin/collector (Overlaps BoardProfile sensors)", "service/ssh (Missing)", "webservice/dashboard (Missing)"}},
+		},
+	}
+	if diff := cmp.Diff(exp, got); diff != "" {
+		t.Errorf("conformance: -want, +got:\n%s", diff)
+	}
+}
+
+func newKube(t *testing.T, objs ...client.Object) client.Client {
+	t.Helper()
+	s := runtime.NewScheme()
+	if err := v1alpha1.SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	return fake.NewClientBuilder().WithScheme(s).WithObjects(objs...).Build()
+}
+
+func TestOverlapping(t *testing.T) {
+	cr := profile()
+	cr.SetUID("gateway")
+	a := &v1alpha1.Device{}
+	a.Spec.ForProvider.Uuid = "board-a"
+	devices := map[string]*v1alpha1.Device{"a": a, "b": {}}
+
+	collector := &v1alpha1.Plugin{ObjectMeta: metav1.ObjectMeta{Name: "collector"}}
+	collector.Spec.ForProvider.Uuid = "plugin-collector"
+
+	// Another profile already injects the collector into board a.
+	other := profile()
+	other.SetName("sensors")
+	theirs := desired(other, map[string]*v1alpha1.Device{"a": {}})["a/plugin/collector"]
+
+	// A standalone injection of the collector into board a, referring to
+	// both by UUID.
+	standalone := &v1alpha1.BoardPluginInjection{ObjectMeta: metav1.ObjectMeta{Name: "debug"}}
+	standalone.Spec.ForProvider.BoardUuid = "board-a"
+	standalone.Spec.ForProvider.PluginUuid = "plugin-collector"
+
+	// The profile's own injection is no overlap.
+	ours := desired(cr, map[string]*v1alpha1.Device{"a": a})["a/plugin/collector"]
+
+	cases := map[string]struct {
+		reason string
+		objs   []client.Object
+		want   map[string]string
+	}{
+		"OtherProfile": {
+			reason: "An injection another profile owns should overlap.",
+			objs:   []client.Object{theirs},
+			want:   map[string]string{"a/plugin/collector": "BoardProfile sensors"},
+		},
+		"Standalone": {
+			reason: "A standalone injection matched by board and plugin UUID should overlap.",
+			objs:   []client.Object{collector, standalone},
+			want:   map[string]string{"a/plugin/collector": "BoardPluginInjection debug"},
+		},
+		"Owned": {
+			reason: "An injection the profile controls should not overlap.",
+			objs:   []client.Object{collector, ours},
+			want:   map[string]string{},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := &external{kube: newKube(t, tc.objs...)}
+			overlaps, err := c.overlapping(context.Background(), cr, devices)
+			if err != nil {
+				t.Fatalf("overlapping: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, overlaps); diff != "" {
+				t.Errorf("\n%s\noverlapping: -want, +got:\n%s", tc.reason, diff)
+			}
+			want := wanted(cr, devices, nil, overlaps)
+			if _, ok := want["a/plugin/collector"]; ok == (len(tc.want) > 0) {
+				t.Errorf("\n%s\nwanted: collector on board a wanted = %t", tc.reason, ok)
+			}
+		})
+	}
+}
+
+func TestCreateNotControlled(t *testing.T) {
+	cr := profile()
+	cr.SetUID("gateway")
+	mg := desired(cr, map[string]*v1alpha1.Device{"a": {}})["a/service/ssh"]
+	foreign := mg.DeepCopyObject().(*v1alpha1.BoardServiceInjection)
+	foreign.SetOwnerReferences(nil)
+	c := &external{kube: newKube(t, foreign)}
+
+	if err := c.create(context.Background(), cr, mg); err == nil {
+		t.Error("create: want error for a resource the profile does not control")
+	}
+}
@@ -23,28 +23,40 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"net"
 	"net/http"
-	"os"
+	"strconv"
 	"strings"
 	"time"
 
 	s4t "github.com/MIKE9708/s4t-sdk-go/pkg/api"
-	read_config "github.com/MIKE9708/s4t-sdk-go/pkg/read_conf"
+	boards "github.com/MIKE9708/s4t-sdk-go/pkg/api/data/board"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/connection"
 	"github.com/crossplane/crossplane-runtime/pkg/controller"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/provider-s4t/apis/iot/v1alpha1"
 	apisv1alpha1 "github.com/crossplane/provider-s4t/apis/v1alpha1"
+	"github.com/crossplane/provider-s4t/internal/expiry"
 	"github.com/crossplane/provider-s4t/internal/features"
+	"github.com/crossplane/provider-s4t/internal/maintenance"
+	"github.com/crossplane/provider-s4t/internal/providerconfig"
+	"github.com/crossplane/provider-s4t/internal/route"
 	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlevent "sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 const (
@@ -53,6 +65,22 @@ const (
 	errGetPC                    = "cannot get ProviderConfig"
 	errGetCreds                 = "cannot get credentials"
 	errNewClient                = "cannot create new Service"
+	errRoute                    = "cannot route exposed service"
+
+	// boardStatusOnline is the IoTronic status of a board whose Lightning Rod
+	// is connected. Services can only be exposed on online boards.
+	boardStatusOnline = "online"
+
+	// Connection details published for an exposed service.
+	keyHost = "host"
+	keyPort = "port"
+	keyURL  = "url"
+
+	// wstunService is the Service fronting the wstun server, on whose
+	// public ports IoTronic exposes board services.
+	wstunService = "iotronic-wstun"
+
+	reasonServiceRestored event.Reason = "ServiceRestored"
 )
 
 type S4TService struct {
@@ -63,68 +91,11 @@ type S4TService struct {
 
 var (
 	newS4TService = func(creds []byte, keystoneEndpoint string) (*S4TService, error) {
-		var result map[string]string
-		err := json.Unmarshal(creds, &result)
-		if err != nil {
-			return nil, errors.Wrap(err, errNewClient)
-		}
-		auth_req := read_config.FormatAuthRequ(
-			result["username"],
-			result["password"],
-			result["domain"],
-		)
-		
-		// Set Keystone endpoint via environment variable (OS_AUTH_URL)
-		if keystoneEndpoint != "" {
-			os.Setenv("OS_AUTH_URL", keystoneEndpoint)
-		} else {
-			os.Setenv("OS_AUTH_URL", "http://keystone.default.svc.cluster.local:5000/v3")
-		}
-		
-		// Extract host from keystoneEndpoint BEFORE creating client
-		// SDK uses hardcoded 127.0.0.1, we need to use Kubernetes service
-		endpoint := keystoneEndpoint
-		if endpoint == "" {
-			endpoint = "http://keystone.default.svc.cluster.local:5000/v3"
-		}
-		if strings.HasSuffix(endpoint, "/v3") {
-			endpoint = strings.TrimSuffix(endpoint, "/v3")
-		}
-		scheme := "http://"
-		if strings.HasPrefix(endpoint, "https://") {
-			scheme = "https://"
-			endpoint = strings.TrimPrefix(endpoint, "https://")
-		} else if strings.HasPrefix(endpoint, "http://") {
-			endpoint = strings.TrimPrefix(endpoint, "http://")
-		}
-		if idx := strings.Index(endpoint, ":"); idx != -1 {
-			endpoint = endpoint[:idx]
-		}
-		keystoneHost := scheme + endpoint
-		
-		// Create client manually with correct host (without port) instead of using GetClientConnection
-		// SDK will derive final URL using AuthPort
-		s4t_client := s4t.NewClient(keystoneHost)
-		s4t_client.Port = "8812"
-		s4t_client.AuthPort = "5000"
-		
-		// Authenticate with correct endpoint
-		token, err := s4t_client.Authenticate(s4t_client, auth_req)
-		if err != nil {
-			return nil, errors.Wrap(err, errNewClient)
-		}
-		s4t_client.AuthToken = token
-		
-		iotronicHost := scheme + "iotronic-conductor.default.svc.cluster.local"
-		s4t_client.Endpoint = iotronicHost
-		
+		c, err := providerconfig.Connect(creds, keystoneEndpoint)
 		if err != nil {
 			return nil, errors.Wrap(err, errNewClient)
 		}
-		return &S4TService{
-			S4tClient: s4t_client,
-			BaseURL:   "http://iotronic-conductor:8812",
-		}, nil
+		return &S4TService{S4tClient: c}, nil
 	}
 )
 
@@ -137,30 +108,94 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), apisv1alpha1.StoreConfigGroupVersionKind))
 	}
 
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
+
 	r := managed.NewReconciler(mgr,
 		resource.ManagedKind(v1alpha1.BoardServiceInjectionGroupVersionKind),
 		managed.WithExternalConnecter(&connector{
 			kube:         mgr.GetClient(),
-			usage:        resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
+			usage:        providerconfig.NewTracker(mgr.GetClient()),
+			recorder:     recorder,
 			newServiceFn: newS4TService}),
+		managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
 		managed.WithLogger(o.Logger.WithValues("controller", name)),
 		managed.WithPollInterval(o.PollInterval),
-		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		managed.WithPollIntervalHook(expiry.PollInterval(expiresAt)),
+		managed.WithRecorder(recorder),
 		managed.WithConnectionPublishers(cps...))
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
 		WithOptions(o.ForControllerRuntime()).
-		WithEventFilter(resource.DesiredStateChanged()).
-		For(&v1alpha1.BoardServiceInjection{}).
+		For(&v1alpha1.BoardServiceInjection{}, builder.WithPredicates(resource.DesiredStateChanged())).
+		Watches(&v1alpha1.Device{},
+			handler.EnqueueRequestsFromMapFunc(injectionsForDevice(mgr.GetClient())),
+			builder.WithPredicates(boardStateChanged)).
 		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
 }
 
+// expiresAt returns when a BoardServiceInjection expires.
+func expiresAt(mg resource.Managed) *metav1.Time {
+	cr, ok := mg.(*v1alpha1.BoardServiceInjection)
+	if !ok {
+		return nil
+	}
+	return cr.Status.AtProvider.ExpiresAt
+}
+
+// injectionsForDevice maps a Device to the BoardServiceInjections that target
+// its board, so that they are reconciled as soon as the board changes state
+// rather than on the next poll.
+func injectionsForDevice(kube client.Client) handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		d, ok := obj.(*v1alpha1.Device)
+		if !ok {
+			return nil
+		}
+		l := &v1alpha1.BoardServiceInjectionList{}
+		if err := kube.List(ctx, l); err != nil {
+			log.Printf("Error listing BoardServiceInjections for Device %s: %v", d.GetName(), err)
+			return nil
+		}
+		var reqs []reconcile.Request
+		for _, i := range l.Items {
+			ref := i.Spec.ForProvider.BoardRef
+			if (d.Spec.ForProvider.Uuid != "" && i.Spec.ForProvider.BoardUuid == d.Spec.ForProvider.Uuid) ||
+				(ref != nil && ref.Name == d.GetName()) {
+				reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{Name: i.GetName()}})
+			}
+		}
+		return reqs
+	}
+}
+
+// boardStateChanged passes Device updates that change the observed board
+// status or session, or the Device's readiness.
+var boardStateChanged = predicate.Funcs{
+	CreateFunc:  func(ctrlevent.CreateEvent) bool { return false },
+	DeleteFunc:  func(ctrlevent.DeleteEvent) bool { return false },
+	GenericFunc: func(ctrlevent.GenericEvent) bool { return false },
+	UpdateFunc: func(e ctrlevent.UpdateEvent) bool {
+		o, ok := e.ObjectOld.(*v1alpha1.Device)
+		if !ok {
+			return false
+		}
+		n, ok := e.ObjectNew.(*v1alpha1.Device)
+		if !ok {
+			return false
+		}
+		return o.Status.Status != n.Status.Status ||
+			o.Status.AtProvider.Session != n.Status.AtProvider.Session ||
+			o.GetCondition(xpv1.TypeReady).Status != n.GetCondition(xpv1.TypeReady).Status
+	},
+}
+
 // A connector is expected to produce an ExternalClient when its Connect method
 // is called.
 type connector struct {
 	kube         client.Client
 	usage        resource.Tracker
+	recorder     event.Recorder
 	newServiceFn func(creds []byte, keystoneEndpoint string) (*S4TService, error)
 }
 
@@ -178,9 +213,8 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 	if err := c.usage.Track(ctx, mg); err != nil {
 		return nil, errors.Wrap(err, errTrackPCUsage)
 	}
-	// cr.GetProviderConfigReference().Name
-	pc_domain := &apisv1alpha1.ProviderConfig{}
-	if err := c.kube.Get(ctx, types.NamespacedName{Name: "s4t-provider-domain"}, pc_domain); err != nil {
+	pc_domain, err := providerconfig.Resolve(ctx, c.kube, mg)
+	if err != nil {
 		return nil, errors.Wrap(err, errGetPC)
 	}
 	cd_domain := pc_domain.Spec.Credentials
@@ -202,7 +236,10 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 	if err != nil {
 		return nil, errors.Wrap(err, errNewClient)
 	}
-	return &external{service: svc}, err
+	if err := providerconfig.Configure(svc.S4tClient, pc_domain); err != nil {
+		return nil, errors.Wrap(err, errNewClient)
+	}
+	return &external{service: svc, kube: c.kube, recorder: c.recorder}, err
 }
 
 // An ExternalClient observes, then either creates, updates, or deletes an
@@ -210,13 +247,30 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 type external struct {
 	// A 'client' used to connect to the external resource API. In practice this
 	// would be something like an AWS SDK client.
-	service *S4TService
+	service  *S4TService
+	kube     client.Client
+	recorder event.Recorder
+
+	// boards reads boards from IoTronic. When nil, the S4T client does.
+	boards boardReader
+}
+
+// A boardReader reads the state of a board.
+type boardReader interface {
+	GetBoardDetail(uuid string) (*boards.Board, error)
+}
+
+func (c *external) board() boardReader {
+	if c.boards != nil {
+		return c.boards
+	}
+	return c.service.S4tClient
 }
 
 // makeRESTCall makes a REST API call to the IoTronic service
 func (c *external) makeRESTCall(method, path string, data interface{}) (*http.Response, error) {
 	// Build URL using the service client's endpoint
-	baseURL := fmt.Sprintf("http://iotronic-conductor.default.svc.cluster.local:%s", c.service.S4tClient.Port)
+	baseURL := fmt.Sprintf("%s:%s", c.service.S4tClient.Endpoint, c.service.S4tClient.Port)
 	url := fmt.Sprintf("%s/v1%s", baseURL, path)
 	
 	var reqBody io.Reader
@@ -259,8 +313,14 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 
 	fmt.Printf("Observing BoardServiceInjection: %+v", cr)
 
+	// An expired injection deletes itself; Delete then disables the service.
+	cr.Status.AtProvider.ExpiresAt = expiry.At(cr, cr.Spec.ForProvider.TTL, cr.Spec.ForProvider.ExpiresAt)
+	if expired, err := expiry.Check(ctx, c.kube, c.recorder, cr, cr.Status.AtProvider.ExpiresAt); err != nil || expired {
+		return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, err
+	}
+
 	// Get board detail to check if it exists
-	board, err := c.service.S4tClient.GetBoardDetail(cr.Spec.ForProvider.BoardUuid)
+	board, err := c.board().GetBoardDetail(cr.Spec.ForProvider.BoardUuid)
 	if err != nil {
 		log.Printf("####ERROR-LOG#### Error s4t client Board Get %q", err)
 		return managed.ExternalObservation{}, err
@@ -278,52 +338,151 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return managed.ExternalObservation{ResourceExists: false}, nil
-	}
+	var found map[string]interface{}
+	if resp.StatusCode == http.StatusOK {
+		var exposedCollection map[string]interface{}
+		if err := json.NewDecoder(resp.Body).Decode(&exposedCollection); err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, "failed to decode response")
+		}
 
-	var exposedCollection map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&exposedCollection); err != nil {
-		return managed.ExternalObservation{}, errors.Wrap(err, "failed to decode response")
+		// Check if our service is in the exposed list
+		exposed, _ := exposedCollection["exposed"].([]interface{})
+		for _, exp := range exposed {
+			expMap, ok := exp.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if service, ok := expMap["service"].(string); ok && service == cr.Spec.ForProvider.ServiceUuid {
+				found = expMap
+				break
+			}
+		}
 	}
 
-	// Check if our service is in the exposed list
-	exposed, ok := exposedCollection["exposed"].([]interface{})
-	if !ok {
+	if found == nil {
+		// ServiceEnable fails unless Lightning Rod is connected. Rather than
+		// calling it on an offline or merely registered board, report the
+		// exposure as pending; the Device watch requeues us once the board
+		// comes online.
+		if !meta.WasDeleted(cr) && board.Status != boardStatusOnline {
+			cr.Status.SetConditions(v1alpha1.WaitingForBoard(board.Status))
+			return managed.ExternalObservation{
+				ResourceExists:   true,
+				ResourceUpToDate: true,
+			}, nil
+		}
 		return managed.ExternalObservation{ResourceExists: false}, nil
 	}
 
-	found := false
-	for _, exp := range exposed {
-		expMap, ok := exp.(map[string]interface{})
-		if !ok {
-			continue
-		}
-		if service, ok := expMap["service"].(string); ok && service == cr.Spec.ForProvider.ServiceUuid {
-			found = true
-			break
-		}
+	// IoTronic forwards the service through the board's wstun server on the
+	// public port it assigned at ServiceEnable time.
+	if cr.Status.AtProvider.Scheme == "" || cr.Status.AtProvider.ServiceUuid != cr.Spec.ForProvider.ServiceUuid {
+		cr.Status.AtProvider.Scheme = c.scheme(cr.Spec.ForProvider.ServiceUuid)
+	}
+	cr.Status.AtProvider.BoardUuid = cr.Spec.ForProvider.BoardUuid
+	cr.Status.AtProvider.ServiceUuid = cr.Spec.ForProvider.ServiceUuid
+	cr.Status.AtProvider.WstunHost = board.Wstunip
+	cr.Status.AtProvider.PublicPort = 0
+	if port, ok := found["public_port"].(float64); ok {
+		cr.Status.AtProvider.PublicPort = int(port)
+	}
+	cr.Status.AtProvider.Address = ""
+	if board.Wstunip != "" && cr.Status.AtProvider.PublicPort != 0 {
+		cr.Status.AtProvider.Address = net.JoinHostPort(board.Wstunip, strconv.Itoa(cr.Status.AtProvider.PublicPort))
 	}
 
-	if !found {
-		return managed.ExternalObservation{ResourceExists: false}, nil
+	// The exposed list lives in the IoTronic database, so it survives a
+	// Lightning Rod reconnect even though the board's wstun tunnels usually
+	// do not. Leave the recorded session alone until Update restores them.
+	restore := needsRestore(cr.Status.AtProvider, board.Status, board.Session)
+	if !restore {
+		cr.Status.AtProvider.BoardSession = board.Session
+		cr.Status.AtProvider.BoardStatus = board.Status
 	}
 
 	cr.Status.SetConditions(xpv1.Available())
 
+	if err := c.route(ctx, cr); err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errRoute)
+	}
+	if err := c.clusterService(ctx, cr); err != nil {
+		return managed.ExternalObservation{}, err
+	}
+
 	return managed.ExternalObservation{
-		ResourceExists:   true,
-		ResourceUpToDate: true,
-		ConnectionDetails: managed.ConnectionDetails{},
+		ResourceExists:    true,
+		ResourceUpToDate:  !restore,
+		ConnectionDetails: connectionDetails(cr.Status.AtProvider),
 	}, nil
 }
 
+// route generates the VirtualService or Ingress for spec.forProvider.route
+// once IoTronic has assigned the service a public port, or removes the one
+// generated before the route was dropped.
+func (c *external) route(ctx context.Context, cr *v1alpha1.BoardServiceInjection) error {
+	r := cr.Spec.ForProvider.Route
+	if r == nil {
+		if err := route.Remove(ctx, c.kube, cr.Status.AtProvider.Route); err != nil {
+			return err
+		}
+		cr.Status.AtProvider.Route = nil
+		return nil
+	}
+	if cr.Status.AtProvider.PublicPort == 0 {
+		return nil
+	}
+	t := route.Target{Service: wstunService, Port: int32(cr.Status.AtProvider.PublicPort)}
+	obs, err := route.Apply(ctx, c.kube, cr, v1alpha1.BoardServiceInjectionGroupVersionKind, r, t, cr.Status.AtProvider.Route)
+	if err != nil {
+		return err
+	}
+	cr.Status.AtProvider.Route = obs
+	return nil
+}
+
+// needsRestore reports whether the board's Lightning Rod has reconnected since
+// the service was last exposed or restored, either in a new session or after
+// having been seen offline.
+func needsRestore(obs v1alpha1.BoardServiceInjectionObservation, boardStatus, boardSession string) bool {
+	if boardStatus != boardStatusOnline || obs.BoardStatus == "" {
+		return false
+	}
+	return obs.BoardStatus != boardStatusOnline || obs.BoardSession != boardSession
+}
+
+// scheme returns the URL scheme of an exposed service, the protocol of the
+// IoTronic Service, or "tcp" when that is unknown.
+func (c *external) scheme(serviceUuid string) string {
+	if svc, err := c.service.S4tClient.GetService(serviceUuid); err == nil && svc.Protocol != "" {
+		return strings.ToLower(svc.Protocol)
+	}
+	return "tcp"
+}
+
+// connectionDetails returns the endpoint of an exposed service, so that
+// consumers can mount it instead of querying IoTronic.
+func connectionDetails(obs v1alpha1.BoardServiceInjectionObservation) managed.ConnectionDetails {
+	if obs.Address == "" {
+		return managed.ConnectionDetails{}
+	}
+	scheme := obs.Scheme
+	if scheme == "" {
+		scheme = "tcp"
+	}
+	return managed.ConnectionDetails{
+		keyHost: []byte(obs.WstunHost),
+		keyPort: []byte(strconv.Itoa(obs.PublicPort)),
+		keyURL:  []byte(fmt.Sprintf("%s://%s", scheme, obs.Address)),
+	}
+}
+
 // Create exposes a service on a board via IoTronic API.
 // API: POST /v1/boards/{board_uuid}/services/{service_uuid}/action
 // Request Body: {"action": "ServiceEnable"}
 // Response: 200 OK on success
 // Prerequisites:
-//   - Board must be online (status='online', Lightning Rod connected)
+//   - Board must be online (status='online', Lightning Rod connected);
+//     Observe holds off with a WaitingForBoard condition until it is
 //   - Service must exist in database
 //   - Board must have an active wagent assigned
 // The service will be exposed on a random public port (typically in range 50000-50100)
@@ -355,17 +514,65 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 	}
 
 	log.Printf("Service %s exposed on board %s", cr.Spec.ForProvider.ServiceUuid, cr.Spec.ForProvider.BoardUuid)
+	cr.Status.AtProvider.ServiceUuid = cr.Spec.ForProvider.ServiceUuid
+	cr.Status.AtProvider.Scheme = c.scheme(cr.Spec.ForProvider.ServiceUuid)
 
 	return managed.ExternalCreation{
 		ConnectionDetails: managed.ConnectionDetails{},
 	}, nil
 }
 
-// Update is a no-op for BoardServiceInjection.
-// Service exposures cannot be updated; they must be deleted and recreated.
+// Update re-establishes the wstun tunnel of an exposed service after the
+// board's Lightning Rod reconnected. Exposures themselves cannot be updated;
+// they must be deleted and recreated.
+// API: POST /v1/boards/{board_uuid}/services/{service_uuid}/action
+// Request Body: {"action": "ServiceRestore"}
+// Response: 200 OK on success
 func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*v1alpha1.BoardServiceInjection)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotBoardServiceInjection)
+	}
+
+	fmt.Printf("Restoring BoardServiceInjection: %+v", cr)
+
+	board, err := c.board().GetBoardDetail(cr.Spec.ForProvider.BoardUuid)
+	if err != nil {
+		log.Printf("####ERROR-LOG#### Error s4t client Board Get %q", err)
+		return managed.ExternalUpdate{}, err
+	}
+
+	serviceData := map[string]interface{}{
+		"action": "ServiceRestore",
+	}
+
+	resp, err := c.makeRESTCall("POST", fmt.Sprintf("/boards/%s/services/%s/action", cr.Spec.ForProvider.BoardUuid, cr.Spec.ForProvider.ServiceUuid), serviceData)
+	if err != nil {
+		log.Printf("Error restoring service on board: %v", err)
+		return managed.ExternalUpdate{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes := make([]byte, 1024)
+		resp.Body.Read(bodyBytes)
+		return managed.ExternalUpdate{}, fmt.Errorf("unexpected status code: %d, body: %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	now := metav1.Now()
+	cr.Status.AtProvider.BoardSession = board.Session
+	cr.Status.AtProvider.BoardStatus = board.Status
+	cr.Status.AtProvider.RestoreCount++
+	cr.Status.AtProvider.LastRestoreTime = &now
+
+	log.Printf("Service %s restored on board %s", cr.Spec.ForProvider.ServiceUuid, cr.Spec.ForProvider.BoardUuid)
+	if c.recorder != nil {
+		c.recorder.Event(cr, event.Normal(reasonServiceRestored,
+			fmt.Sprintf("Issued ServiceRestore after Lightning Rod reconnected in session %q", board.Session)))
+	}
+
 	return managed.ExternalUpdate{
-		ConnectionDetails: managed.ConnectionDetails{},
+		ConnectionDetails: connectionDetails(cr.Status.AtProvider),
 	}, nil
 }
 
@@ -381,6 +588,20 @@ func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
 
 	fmt.Printf("Deleting BoardServiceInjection: %+v", cr)
 
+	// Disabling cuts off whoever is using the service, so it is requested
+	// again, without an error, until the board's maintenance window opens,
+	// unless the injection expired.
+	if !expiry.Expired(cr) {
+		deferred, err := maintenance.Check(ctx, c.kube, cr, cr.Spec.ForProvider.BoardUuid)
+		if err != nil {
+			return err
+		}
+		if deferred != nil {
+			cr.Status.SetConditions(deferred.DeletionCondition())
+			return nil
+		}
+	}
+
 	// Remove service from board via REST API
 	// POST /v1/boards/{uuid}/services/{service_uuid}/action with action "ServiceDisable"
 	serviceData := map[string]interface{}{
@@ -18,13 +18,32 @@ package boardserviceinjection
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"testing"
+	"time"
 
+	s4t "github.com/MIKE9708/s4t-sdk-go/pkg/api"
+	boards "github.com/MIKE9708/s4t-sdk-go/pkg/api/data/board"
 	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/provider-s4t/apis/iot/v1alpha1"
+	"github.com/crossplane/provider-s4t/internal/expiry"
 )
 
 // Unlike many Kubernetes projects Crossplane does not use third party testing
@@ -68,3 +87,324 @@ func TestObserve(t *testing.T) {
 		})
 	}
 }
+
+func TestDNSLabel(t *testing.T) {
+	cases := map[string]struct {
+		in   string
+		want string
+	}{
+		"Valid":      {in: "rpi-01-modbus", want: "rpi-01-modbus"},
+		"Sanitized":  {in: "RPi_01.Modbus TCP", want: "rpi-01-modbus-tcp"},
+		"LeadDigits": {in: "01-board-ssh", want: "board-ssh"},
+		"Empty":      {in: "-_-", want: ""},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, dnsLabel(tc.in)); diff != "" {
+				t.Errorf("dnsLabel(%q): -want, +got:\n%s", tc.in, diff)
+			}
+		})
+	}
+}
+
+func TestClusterServiceName(t *testing.T) {
+	long := "greenhouse-sensor-gateway-building-a-floor-2-room-17-rack-3"
+	names := map[string]bool{}
+	for _, board := range []string{long + "-a", long + "-b"} {
+		cr := &v1alpha1.BoardServiceInjection{}
+		cr.Spec.ForProvider.BoardRef = &xpv1.Reference{Name: board}
+		cr.Spec.ForProvider.ServiceRef = &xpv1.Reference{Name: "modbus"}
+		c := &external{kube: newKube(t)}
+		name, err := c.clusterServiceName(context.Background(), cr)
+		if err != nil {
+			t.Fatalf("clusterServiceName(%s): %v", board, err)
+		}
+		if len(name) > 63 || dnsLabel(name) != name {
+			t.Errorf("clusterServiceName(%s): want a DNS label, got %q", board, name)
+		}
+		names[name] = true
+	}
+	if len(names) != 2 {
+		t.Errorf("clusterServiceName(...): long names sharing a prefix collide: %v", names)
+	}
+}
+
+func TestConnectionDetails(t *testing.T) {
+	cases := map[string]struct {
+		obs  v1alpha1.BoardServiceInjectionObservation
+		want managed.ConnectionDetails
+	}{
+		"NotExposed": {
+			obs:  v1alpha1.BoardServiceInjectionObservation{Scheme: "http"},
+			want: managed.ConnectionDetails{},
+		},
+		"Exposed": {
+			obs: v1alpha1.BoardServiceInjectionObservation{WstunHost: "10.0.0.5", PublicPort: 50024, Address: "10.0.0.5:50024", Scheme: "http"},
+			want: managed.ConnectionDetails{
+				keyHost: []byte("10.0.0.5"),
+				keyPort: []byte("50024"),
+				keyURL:  []byte("http://10.0.0.5:50024"),
+			},
+		},
+		"UnknownScheme": {
+			obs: v1alpha1.BoardServiceInjectionObservation{WstunHost: "10.0.0.5", PublicPort: 50024, Address: "10.0.0.5:50024"},
+			want: managed.ConnectionDetails{
+				keyHost: []byte("10.0.0.5"),
+				keyPort: []byte("50024"),
+				keyURL:  []byte("tcp://10.0.0.5:50024"),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, connectionDetails(tc.obs)); diff != "" {
+				t.Errorf("connectionDetails(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNeedsRestore(t *testing.T) {
+	cases := map[string]struct {
+		obs     v1alpha1.BoardServiceInjectionObservation
+		status  string
+		session string
+		want    bool
+	}{
+		"NeverObserved": {
+			obs:     v1alpha1.BoardServiceInjectionObservation{},
+			status:  boardStatusOnline,
+			session: "s1",
+			want:    false,
+		},
+		"SameSession": {
+			obs:     v1alpha1.BoardServiceInjectionObservation{BoardStatus: boardStatusOnline, BoardSession: "s1"},
+			status:  boardStatusOnline,
+			session: "s1",
+			want:    false,
+		},
+		"NewSession": {
+			obs:     v1alpha1.BoardServiceInjectionObservation{BoardStatus: boardStatusOnline, BoardSession: "s1"},
+			status:  boardStatusOnline,
+			session: "s2",
+			want:    true,
+		},
+		"BackOnline": {
+			obs:     v1alpha1.BoardServiceInjectionObservation{BoardStatus: "offline", BoardSession: "s1"},
+			status:  boardStatusOnline,
+			session: "s1",
+			want:    true,
+		},
+		"StillOffline": {
+			obs:     v1alpha1.BoardServiceInjectionObservation{BoardStatus: boardStatusOnline, BoardSession: "s1"},
+			status:  "offline",
+			session: "s2",
+			want:    false,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := needsRestore(tc.obs, tc.status, tc.session); got != tc.want {
+				t.Errorf("needsRestore(...): want %t, got %t", tc.want, got)
+			}
+		})
+	}
+}
+
+func newKube(t *testing.T, objs ...client.Object) client.Client {
+	t.Helper()
+	s := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := v1alpha1.SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	return fake.NewClientBuilder().WithScheme(s).WithObjects(objs...).Build()
+}
+
+func exposed() *v1alpha1.BoardServiceInjection {
+	cr := &v1alpha1.BoardServiceInjection{ObjectMeta: metav1.ObjectMeta{Name: "ssh", UID: "ssh"}}
+	cr.Spec.ForProvider.ServiceUuid = "svc-1"
+	cr.Spec.ForProvider.ClusterService = &v1alpha1.ClusterService{Name: "board-ssh"}
+	cr.Status.AtProvider.WstunHost = "10.0.0.5"
+	cr.Status.AtProvider.PublicPort = 50024
+	return cr
+}
+
+func TestApplyClusterService(t *testing.T) {
+	e := endpoint{protocol: corev1.ProtocolTCP, port: 22, public: 50024, addrs: []string{"10.0.0.5"}, addrType: discoveryv1.AddressTypeIPv4}
+	foreign := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "taken", Namespace: "default"}}
+	kube := newKube(t, foreign)
+	c := &external{kube: kube}
+	cr := exposed()
+	ctx := context.Background()
+
+	if err := c.applyClusterService(ctx, cr, "board-ssh", "default", e); err != nil {
+		t.Fatalf("applyClusterService(...): %v", err)
+	}
+	// Applying again updates the objects the injection controls.
+	e.public = 50025
+	if err := c.applyClusterService(ctx, cr, "board-ssh", "default", e); err != nil {
+		t.Fatalf("applyClusterService(...) again: %v", err)
+	}
+	s := &corev1.Service{}
+	if err := kube.Get(ctx, types.NamespacedName{Namespace: "default", Name: "board-ssh"}, s); err != nil {
+		t.Fatal(err)
+	}
+	if !metav1.IsControlledBy(s, cr) || s.Spec.Ports[0].TargetPort.IntVal != 50025 {
+		t.Errorf("applyClusterService(...): want a Service controlled by the injection targeting 50025, got %+v", s)
+	}
+
+	// Objects deleted out of band are recreated.
+	if err := kube.Delete(ctx, s); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.applyClusterService(ctx, cr, "board-ssh", "default", e); err != nil {
+		t.Fatalf("applyClusterService(...) after delete: %v", err)
+	}
+	if err := kube.Get(ctx, types.NamespacedName{Namespace: "default", Name: "board-ssh"}, s); err != nil {
+		t.Errorf("applyClusterService(...): want the deleted Service recreated: %v", err)
+	}
+
+	if err := c.applyClusterService(ctx, cr, "taken", "default", e); err == nil {
+		t.Error("applyClusterService(...): want error for a Service the injection does not control")
+	}
+	if err := kube.Get(ctx, types.NamespacedName{Namespace: "default", Name: "taken"}, s); err != nil {
+		t.Fatal(err)
+	}
+	if len(s.GetOwnerReferences()) != 0 {
+		t.Errorf("applyClusterService(...): a Service the injection does not control must not be taken over, got %+v", s.GetOwnerReferences())
+	}
+}
+
+func TestDeleteDeferred(t *testing.T) {
+	d := &v1alpha1.Device{ObjectMeta: metav1.ObjectMeta{Name: "board"}}
+	d.Spec.ForProvider.Uuid = "board-1"
+	d.Spec.ForProvider.MaintenanceWindowRef = &xpv1.Reference{Name: "never"}
+	// February 30th never comes.
+	w := &v1alpha1.MaintenanceWindow{ObjectMeta: metav1.ObjectMeta{Name: "never"}}
+	w.Spec.ForProvider.Schedules = []v1alpha1.MaintenanceWindowSchedule{{Start: "0 0 30 2 *", Duration: metav1.Duration{Duration: time.Hour}}}
+
+	cases := map[string]struct {
+		annotations map[string]string
+		deferred    bool
+	}{
+		// Removal outside the window is requested again later rather
+		// than failing, and the wait is reported apart from Ready.
+		"OutsideWindow": {deferred: true},
+		// An expired injection is due now.
+		"Expired": {annotations: map[string]string{expiry.AnnotationExpired: "2026-01-01T00:00:00Z"}},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			disabled := false
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				disabled = r.URL.Path == "/v1/boards/board-1/services/svc-1/action"
+			}))
+			defer srv.Close()
+			u, err := url.Parse(srv.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			cr := exposed()
+			cr.Spec.ForProvider.BoardUuid = "board-1"
+			cr.SetAnnotations(tc.annotations)
+			c := &external{
+				service: &S4TService{S4tClient: &s4t.Client{Endpoint: u.Scheme + "://" + u.Hostname(), Port: u.Port()}},
+				kube:    newKube(t, d, w),
+			}
+			if err := c.Delete(context.Background(), cr); err != nil {
+				t.Fatalf("Delete(...): %v", err)
+			}
+			if disabled == tc.deferred {
+				t.Errorf("Delete(...): want service disabled %t, got %t", !tc.deferred, disabled)
+			}
+			got := cr.Status.GetCondition(v1alpha1.TypeDeletionDeferred)
+			if deferred := got.Reason == v1alpha1.ReasonWaitingForMaintenanceWindow; deferred != tc.deferred {
+				t.Errorf("Delete(...): want %s condition %t, got %+v", v1alpha1.TypeDeletionDeferred, tc.deferred, got)
+			}
+		})
+	}
+}
+
+func TestInjectionsForDevice(t *testing.T) {
+	byUUID := &v1alpha1.BoardServiceInjection{ObjectMeta: metav1.ObjectMeta{Name: "by-uuid"}}
+	byUUID.Spec.ForProvider.BoardUuid = "board-1"
+	byRef := &v1alpha1.BoardServiceInjection{ObjectMeta: metav1.ObjectMeta{Name: "by-ref"}}
+	byRef.Spec.ForProvider.BoardRef = &xpv1.Reference{Name: "rpi"}
+	other := &v1alpha1.BoardServiceInjection{ObjectMeta: metav1.ObjectMeta{Name: "other"}}
+	other.Spec.ForProvider.BoardUuid = "board-2"
+	kube := newKube(t, byUUID, byRef, other)
+
+	d := &v1alpha1.Device{ObjectMeta: metav1.ObjectMeta{Name: "rpi"}}
+	d.Spec.ForProvider.Uuid = "board-1"
+	got := injectionsForDevice(kube)(context.Background(), d)
+	want := []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Name: "by-ref"}},
+		{NamespacedName: types.NamespacedName{Name: "by-uuid"}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("injectionsForDevice(...): -want, +got:\n%s", diff)
+	}
+}
+
+// fakeBoards returns the same board for any UUID.
+type fakeBoards struct {
+	board boards.Board
+}
+
+func (f *fakeBoards) GetBoardDetail(string) (*boards.Board, error) { return &f.board, nil }
+
+func TestObserveBoardState(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"exposed":[]}`))
+	}))
+	defer srv.Close()
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]struct {
+		reason  string
+		board   boards.Board
+		want    managed.ExternalObservation
+		waiting bool
+	}{
+		"Offline": {
+			reason:  "A service on an offline board should wait for it rather than be enabled.",
+			board:   boards.Board{Uuid: "board-1", Status: "offline"},
+			want:    managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			waiting: true,
+		},
+		"Online": {
+			reason: "A service on an online board should be enabled.",
+			board:  boards.Board{Uuid: "board-1", Status: "online"},
+			want:   managed.ExternalObservation{},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := exposed()
+			cr.Spec.ForProvider.BoardUuid = "board-1"
+			c := &external{
+				service: &S4TService{S4tClient: &s4t.Client{Endpoint: u.Scheme + "://" + u.Hostname(), Port: u.Port()}},
+				kube:    newKube(t),
+				boards:  &fakeBoards{board: tc.board},
+			}
+			got, err := c.Observe(context.Background(), cr)
+			if err != nil {
+				t.Fatalf("\n%s\nObserve(...): %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nObserve(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			cond := cr.GetCondition(xpv1.TypeReady)
+			if waiting := cond.Reason == v1alpha1.ReasonWaitingForBoard; waiting != tc.waiting {
+				t.Errorf("\n%s\nObserve(...): want waiting for board %t, got condition %+v", tc.reason, tc.waiting, cond)
+			}
+		})
+	}
+}
@@ -0,0 +1,300 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package boardserviceinjection
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"strconv"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/crossplane/provider-s4t/apis/iot/v1alpha1"
+	"github.com/crossplane/provider-s4t/internal/names"
+)
+
+const (
+	errGetService       = "cannot get IoTronic service"
+	errResolveWstun     = "cannot resolve wstun host %q"
+	errListDevices      = "cannot list Devices"
+	errListServices     = "cannot list Services"
+	errApplyService     = "cannot apply Service %s/%s"
+	errApplySlice       = "cannot apply EndpointSlice %s/%s"
+	errDeleteService    = "cannot delete Service %s/%s"
+	errNoClusterService = "cannot name the cluster Service: the board and service are not known to the cluster; set clusterService.name"
+	errNotControlled    = "%s %s/%s exists and is not controlled by this BoardServiceInjection"
+
+	// defaultClusterServiceNamespace is where cluster Services are created
+	// unless they name a namespace.
+	defaultClusterServiceNamespace = "default"
+
+	// endpointSliceManager identifies the provider as the manager of the
+	// EndpointSlices it writes, so that the EndpointSlice controllers leave
+	// them alone.
+	endpointSliceManager = "provider-s4t"
+)
+
+// clusterService generates the Service and EndpointSlice for
+// spec.forProvider.clusterService once IoTronic has assigned the service a
+// public port, or removes the ones generated before the Service was dropped
+// or renamed. Both are applied on every poll, so that they are recreated if
+// deleted, restored if edited, and follow the IoTronic service, the wstun
+// host and the public port as they change.
+func (c *external) clusterService(ctx context.Context, cr *v1alpha1.BoardServiceInjection) error {
+	cs := cr.Spec.ForProvider.ClusterService
+	prev := cr.Status.AtProvider.ClusterService
+	if cs == nil {
+		if err := c.removeClusterService(ctx, prev); err != nil {
+			return err
+		}
+		cr.Status.AtProvider.ClusterService = nil
+		return nil
+	}
+	obs := cr.Status.AtProvider
+	if obs.PublicPort == 0 || obs.WstunHost == "" {
+		return nil
+	}
+
+	name := cs.Name
+	if name == "" {
+		var err error
+		if name, err = c.clusterServiceName(ctx, cr); err != nil {
+			return err
+		}
+	}
+	ns := cs.Namespace
+	if ns == "" {
+		ns = defaultClusterServiceNamespace
+	}
+	svc, err := c.service.S4tClient.GetService(cr.Spec.ForProvider.ServiceUuid)
+	if err != nil {
+		return errors.Wrap(err, errGetService)
+	}
+	if svc == nil {
+		return errors.New(errGetService)
+	}
+	addrs, addrType, err := resolve(ctx, obs.WstunHost)
+	if err != nil {
+		return errors.Wrapf(err, errResolveWstun, obs.WstunHost)
+	}
+
+	if prev != nil && (prev.Name != name || prev.Namespace != ns) {
+		if err := c.removeClusterService(ctx, prev); err != nil {
+			return err
+		}
+	}
+
+	e := endpoint{protocol: protocol(svc.Protocol), port: int32(svc.Port), public: int32(obs.PublicPort), addrs: addrs, addrType: addrType}
+	if err := c.applyClusterService(ctx, cr, name, ns, e); err != nil {
+		return err
+	}
+
+	cr.Status.AtProvider.ClusterService = &v1alpha1.ClusterServiceObservation{
+		Name:      name,
+		Namespace: ns,
+		Port:      e.port,
+		Endpoint:  net.JoinHostPort(addrs[0], strconv.Itoa(obs.PublicPort)),
+	}
+	return nil
+}
+
+// An endpoint is where a cluster Service forwards to.
+type endpoint struct {
+	protocol corev1.Protocol
+	port     int32
+	public   int32
+	addrs    []string
+	addrType discoveryv1.AddressType
+}
+
+// applyClusterService creates or updates the Service name in ns and its
+// EndpointSlice, forwarding to e. Existing objects the injection does not
+// control are left alone and reported.
+func (c *external) applyClusterService(ctx context.Context, cr *v1alpha1.BoardServiceInjection, name, ns string, e endpoint) error {
+	owner := meta.AsController(meta.TypedReferenceTo(cr, v1alpha1.BoardServiceInjectionGroupVersionKind))
+	portName := strings.ToLower(string(e.protocol))
+
+	s := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns}}
+	if _, err := controllerutil.CreateOrUpdate(ctx, c.kube, s, func() error {
+		if s.GetResourceVersion() != "" && !metav1.IsControlledBy(s, cr) {
+			return errors.Errorf(errNotControlled, "Service", ns, name)
+		}
+		meta.AddOwnerReference(s, owner)
+		s.Spec.Ports = []corev1.ServicePort{{
+			Name:       portName,
+			Protocol:   e.protocol,
+			Port:       e.port,
+			TargetPort: intstr.FromInt32(e.public),
+		}}
+		return nil
+	}); err != nil {
+		return errors.Wrapf(err, errApplyService, ns, name)
+	}
+
+	es := &discoveryv1.EndpointSlice{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns}}
+	if _, err := controllerutil.CreateOrUpdate(ctx, c.kube, es, func() error {
+		if es.GetResourceVersion() != "" && !metav1.IsControlledBy(es, cr) {
+			return errors.Errorf(errNotControlled, "EndpointSlice", ns, name)
+		}
+		meta.AddOwnerReference(es, owner)
+		meta.AddLabels(es, map[string]string{
+			discoveryv1.LabelServiceName: name,
+			discoveryv1.LabelManagedBy:   endpointSliceManager,
+		})
+		es.AddressType = e.addrType
+		es.Endpoints = []discoveryv1.Endpoint{{
+			Addresses:  e.addrs,
+			Conditions: discoveryv1.EndpointConditions{Ready: ptr.To(true)},
+		}}
+		es.Ports = []discoveryv1.EndpointPort{{
+			Name:     ptr.To(portName),
+			Protocol: ptr.To(e.protocol),
+			Port:     ptr.To(e.public),
+		}}
+		return nil
+	}); err != nil {
+		return errors.Wrapf(err, errApplySlice, ns, name)
+	}
+	return nil
+}
+
+// removeClusterService deletes the Service recorded in obs and its
+// EndpointSlice. It is not an error for them to be gone already.
+func (c *external) removeClusterService(ctx context.Context, obs *v1alpha1.ClusterServiceObservation) error {
+	if obs == nil {
+		return nil
+	}
+	for _, o := range []client.Object{
+		&discoveryv1.EndpointSlice{ObjectMeta: metav1.ObjectMeta{Name: obs.Name, Namespace: obs.Namespace}},
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: obs.Name, Namespace: obs.Namespace}},
+	} {
+		if err := c.kube.Delete(ctx, o); err != nil && !kerrors.IsNotFound(err) {
+			return errors.Wrapf(err, errDeleteService, obs.Namespace, obs.Name)
+		}
+	}
+	return nil
+}
+
+// clusterServiceName returns <device>-<service>-<hash> after the Device and
+// Service managed resources the injection refers to, by reference or by UUID,
+// shortened to a DNS label. The hash keeps long names that share a prefix
+// apart.
+func (c *external) clusterServiceName(ctx context.Context, cr *v1alpha1.BoardServiceInjection) (string, error) {
+	p := cr.Spec.ForProvider
+	board := ""
+	if p.BoardRef != nil {
+		board = p.BoardRef.Name
+	} else {
+		l := &v1alpha1.DeviceList{}
+		if err := c.kube.List(ctx, l); err != nil {
+			return "", errors.Wrap(err, errListDevices)
+		}
+		for _, d := range l.Items {
+			if d.Spec.ForProvider.Uuid == p.BoardUuid {
+				board = d.GetName()
+				break
+			}
+		}
+	}
+	service := ""
+	if p.ServiceRef != nil {
+		service = p.ServiceRef.Name
+	} else {
+		l := &v1alpha1.ServiceList{}
+		if err := c.kube.List(ctx, l); err != nil {
+			return "", errors.Wrap(err, errListServices)
+		}
+		for _, s := range l.Items {
+			if s.Spec.ForProvider.Uuid == p.ServiceUuid {
+				service = s.GetName()
+				break
+			}
+		}
+	}
+	name := dnsLabel(names.Child(board, service))
+	if board == "" || service == "" || name == "" {
+		return "", errors.New(errNoClusterService)
+	}
+	return name, nil
+}
+
+// dnsLabel turns s into a DNS-1035 label, as Service names must be, or
+// returns "" if nothing of it is usable.
+func dnsLabel(s string) string {
+	b := make([]byte, 0, len(s))
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			b = append(b, byte(r))
+		case len(b) > 0 && b[len(b)-1] != '-':
+			b = append(b, '-')
+		}
+	}
+	out := strings.TrimLeft(string(b), "-0123456789")
+	if len(out) > 63 {
+		out = out[:63]
+	}
+	return strings.TrimRight(out, "-")
+}
+
+// protocol maps an IoTronic service protocol to a Kubernetes one. IoTronic
+// tunnels everything but UDP over TCP.
+func protocol(p string) corev1.Protocol {
+	if strings.EqualFold(p, string(corev1.ProtocolUDP)) {
+		return corev1.ProtocolUDP
+	}
+	return corev1.ProtocolTCP
+}
+
+// resolve returns the addresses of the wstun host, all of one family as an
+// EndpointSlice requires, preferring IPv4.
+func resolve(ctx context.Context, host string) ([]string, discoveryv1.AddressType, error) {
+	var ips []netip.Addr
+	if ip, err := netip.ParseAddr(host); err == nil {
+		ips = []netip.Addr{ip}
+	} else {
+		if ips, err = net.DefaultResolver.LookupNetIP(ctx, "ip", host); err != nil {
+			return nil, "", err
+		}
+	}
+	var v4, v6 []string
+	for _, ip := range ips {
+		if ip = ip.Unmap(); ip.Is4() {
+			v4 = append(v4, ip.String())
+		} else {
+			v6 = append(v6, ip.String())
+		}
+	}
+	switch {
+	case len(v4) > 0:
+		return v4, discoveryv1.AddressTypeIPv4, nil
+	case len(v6) > 0:
+		return v6, discoveryv1.AddressTypeIPv6, nil
+	}
+	return nil, "", errors.New("no addresses")
+}
@@ -18,14 +18,10 @@ package device
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log"
-	"os"
-	"strings"
 
 	s4t "github.com/MIKE9708/s4t-sdk-go/pkg/api"
-	read_config "github.com/MIKE9708/s4t-sdk-go/pkg/read_conf"
 
 	boards "github.com/MIKE9708/s4t-sdk-go/pkg/api/data/board"
 
@@ -33,6 +29,7 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/connection"
 	"github.com/crossplane/crossplane-runtime/pkg/controller"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
@@ -43,7 +40,8 @@ import (
 	"github.com/crossplane/provider-s4t/apis/iot/v1alpha1"
 	apisv1alpha1 "github.com/crossplane/provider-s4t/apis/v1alpha1"
 	"github.com/crossplane/provider-s4t/internal/features"
-	"k8s.io/apimachinery/pkg/types"
+	"github.com/crossplane/provider-s4t/internal/geofence"
+	"github.com/crossplane/provider-s4t/internal/providerconfig"
 )
 
 const (
@@ -53,6 +51,11 @@ const (
 	errGetCreds     = "cannot get credentials"
 
 	errNewClient = "cannot create new Service"
+	errListSites = "cannot list Sites"
+	errLabelSite = "cannot label Device with its geofence site"
+
+	reasonSite    event.Reason = "SiteChanged"
+	reasonOutside event.Reason = "OutsideGeofences"
 )
 
 // A NoOpService does nothing.
@@ -62,82 +65,11 @@ type S4TService struct {
 
 var (
 	newS4TService = func(creds []byte, keystoneEndpoint string) (*S4TService, error) {
-		var result map[string]string
-		err := json.Unmarshal(creds, &result)
-		if err != nil {
-			return nil, errors.Wrap(err, errNewClient)
-		}
-		
-		// Set Keystone endpoint via environment variable (OS_AUTH_URL)
-		// IMPORTANT: Must be set BEFORE FormatAuthRequ, as SDK may read it during auth request creation
-		if keystoneEndpoint != "" {
-			os.Setenv("OS_AUTH_URL", keystoneEndpoint)
-		} else {
-			// Default to Kubernetes service
-			os.Setenv("OS_AUTH_URL", "http://keystone.default.svc.cluster.local:5000/v3")
-		}
-		
-		// Also set other OpenStack env vars that SDK might need
-		os.Setenv("OS_IDENTITY_API_VERSION", "3")
-		
-		auth_req := read_config.FormatAuthRequ(
-			result["username"],
-			result["password"],
-			result["domain"],
-		)
-		
-		// Ensure OS_AUTH_URL is still set (in case FormatAuthRequ cleared it)
-		if keystoneEndpoint != "" {
-			os.Setenv("OS_AUTH_URL", keystoneEndpoint)
-		} else {
-			os.Setenv("OS_AUTH_URL", "http://keystone.default.svc.cluster.local:5000/v3")
-		}
-		
-		// Extract host from keystoneEndpoint BEFORE creating client
-		// SDK uses hardcoded 127.0.0.1, we need to use Kubernetes service
-		endpoint := keystoneEndpoint
-		if endpoint == "" {
-			endpoint = "http://keystone.default.svc.cluster.local:5000/v3"
-		}
-		// Remove /v3 suffix if present
-		if strings.HasSuffix(endpoint, "/v3") {
-			endpoint = strings.TrimSuffix(endpoint, "/v3")
-		}
-		// Extract just the host (without port) while keeping scheme for downstream requests
-		scheme := "http://"
-		if strings.HasPrefix(endpoint, "https://") {
-			scheme = "https://"
-			endpoint = strings.TrimPrefix(endpoint, "https://")
-		} else if strings.HasPrefix(endpoint, "http://") {
-			endpoint = strings.TrimPrefix(endpoint, "http://")
-		}
-		// Remove port if present (e.g., :5000)
-		if idx := strings.Index(endpoint, ":"); idx != -1 {
-			endpoint = endpoint[:idx]
-		}
-		keystoneHost := scheme + endpoint
-		
-		// Create client manually with correct host (without port) instead of using GetClientConnection
-		// GetClientConnection uses hardcoded 127.0.0.1, so we create client and authenticate manually
-		// SDK will construct URL as http://<host>:<AuthPort>/v3/auth/tokens
-		s4t_client := s4t.NewClient(keystoneHost)
-		s4t_client.Port = "8812"
-		s4t_client.AuthPort = "5000"
-		
-		// Authenticate with correct endpoint
-		token, err := s4t_client.Authenticate(s4t_client, auth_req)
+		c, err := providerconfig.Connect(creds, keystoneEndpoint)
 		if err != nil {
 			return nil, errors.Wrap(err, errNewClient)
 		}
-		s4t_client.AuthToken = token
-		
-		// After authentication, switch client endpoint to IoTronic conductor service
-		iotronicHost := scheme + "iotronic-conductor.default.svc.cluster.local"
-		s4t_client.Endpoint = iotronicHost
-		
-		return &S4TService{
-			S4tClient: s4t_client,
-		}, nil
+		return &S4TService{S4tClient: c}, nil
 	}
 )
 
@@ -149,15 +81,18 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), apisv1alpha1.StoreConfigGroupVersionKind))
 	}
 
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
+
 	r := managed.NewReconciler(mgr,
 		resource.ManagedKind(v1alpha1.DeviceGroupVersionKind),
 		managed.WithExternalConnecter(&connector{
 			kube:         mgr.GetClient(),
-			usage:        resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
+			usage:        providerconfig.NewTracker(mgr.GetClient()),
+			recorder:     recorder,
 			newServiceFn: newS4TService}),
 		managed.WithLogger(o.Logger.WithValues("controller", name)),
 		managed.WithPollInterval(o.PollInterval),
-		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		managed.WithRecorder(recorder),
 		managed.WithConnectionPublishers(cps...))
 
 	return ctrl.NewControllerManagedBy(mgr).
@@ -173,6 +108,7 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 type connector struct {
 	kube         client.Client
 	usage        resource.Tracker
+	recorder     event.Recorder
 	newServiceFn func(creds []byte, keystoneEndpoint string) (*S4TService, error)
 }
 
@@ -190,9 +126,8 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 	if err := c.usage.Track(ctx, mg); err != nil {
 		return nil, errors.Wrap(err, errTrackPCUsage)
 	}
-	// cr.GetProviderConfigReference().Name
-	pc_domain := &apisv1alpha1.ProviderConfig{}
-	if err := c.kube.Get(ctx, types.NamespacedName{Name: "s4t-provider-domain"}, pc_domain); err != nil {
+	pc_domain, err := providerconfig.Resolve(ctx, c.kube, mg)
+	if err != nil {
 		return nil, errors.Wrap(err, errGetPC)
 	}
 	cd_domain := pc_domain.Spec.Credentials
@@ -211,7 +146,10 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 	if err != nil {
 		return nil, errors.Wrap(err, errNewClient)
 	}
-	return &external{service: svc}, err
+	if err := providerconfig.Configure(svc.S4tClient, pc_domain); err != nil {
+		return nil, errors.Wrap(err, errNewClient)
+	}
+	return &external{kube: c.kube, service: svc, recorder: c.recorder}, err
 }
 
 // An ExternalClient observes, then either creates, updates, or deletes an
@@ -219,7 +157,9 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 type external struct {
 	// A 'client' used to connect to the external resource API. In practice this
 	// would be something like an AWS SDK client.
-	service *S4TService
+	service  *S4TService
+	kube     client.Client
+	recorder event.Recorder
 }
 
 func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
@@ -238,6 +178,19 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	if board.Uuid == "" {
 		return managed.ExternalObservation{ResourceExists: false}, nil
 	}
+
+	// Record the live board state so that dependent injections can react
+	// when the board comes online.
+	cr.Status.Uuid = board.Uuid
+	cr.Status.Status = board.Status
+	cr.Status.AtProvider.Uuid = board.Uuid
+	cr.Status.AtProvider.Code = board.Code
+	cr.Status.AtProvider.Session = board.Session
+
+	if err := c.assignSite(ctx, cr, board); err != nil {
+		return managed.ExternalObservation{}, err
+	}
+
 	if cr.Spec.ForProvider.Code != board.Code {
 		return managed.ExternalObservation{ResourceUpToDate: false, ResourceExists: true}, nil
 	}
@@ -357,3 +310,99 @@ func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
 	}
 	return err
 }
+
+// currentLocation returns the latest location reported by the board, falling
+// back to the last one configured on the Device.
+func currentLocation(cr *v1alpha1.Device, board *boards.Board) *v1alpha1.Location {
+	for i := len(board.Location) - 1; i >= 0; i-- {
+		if l := board.Location[i]; l != nil {
+			return &v1alpha1.Location{Latitude: l.Latitude, Longitude: l.Longitude, Altitude: l.Altitude}
+		}
+	}
+	if n := len(cr.Spec.ForProvider.Location); n > 0 {
+		l := cr.Spec.ForProvider.Location[n-1]
+		return &v1alpha1.Location{Latitude: l.Latitude, Longitude: l.Longitude, Altitude: l.Altitude}
+	}
+	return nil
+}
+
+// assignSite records the board's current location and, when Sites declare
+// geofences, labels the Device with the site whose geofence contains it. The
+// geofence-site label is used rather than the site label, which selects the
+// ProviderConfig serving the device and is left to the user. Boards outside
+// every geofence lose the label and are reported instead.
+func (c *external) assignSite(ctx context.Context, cr *v1alpha1.Device, board *boards.Board) error {
+	obs := &cr.Status.AtProvider
+	wasOutside := obs.OutsideGeofences
+	obs.Location = currentLocation(cr, board)
+	obs.GeofenceSite = ""
+	obs.OutsideGeofences = false
+
+	site, err := c.geofenceSite(ctx, cr)
+	if err != nil {
+		return err
+	}
+	obs.GeofenceSite = site
+	if site == "" && obs.OutsideGeofences && !wasOutside {
+		c.recorder.Event(cr, event.Warning(reasonOutside,
+			errors.Errorf("board location %s, %s is outside every site geofence", obs.Location.Latitude, obs.Location.Longitude)))
+	}
+
+	from := cr.GetLabels()[v1alpha1.LabelGeofenceSite]
+	if from == site {
+		return nil
+	}
+
+	// Patch a copy so the status recorded above is not overwritten by the
+	// object returned from the API server. The watch on Devices requeues the
+	// sites named by both the old and the new label, so both refresh their
+	// counts.
+	labelled := cr.DeepCopy()
+	if site == "" {
+		meta.RemoveLabels(labelled, v1alpha1.LabelGeofenceSite)
+	} else {
+		meta.AddLabels(labelled, map[string]string{v1alpha1.LabelGeofenceSite: site})
+	}
+	if err := c.kube.Patch(ctx, labelled, client.MergeFrom(cr)); err != nil {
+		return errors.Wrap(err, errLabelSite)
+	}
+	cr.SetLabels(labelled.GetLabels())
+	cr.SetResourceVersion(labelled.GetResourceVersion())
+
+	if site == "" {
+		return nil
+	}
+	msg := fmt.Sprintf("Board entered site %q", site)
+	if from != "" {
+		msg = fmt.Sprintf("Board moved from site %q to %q", from, site)
+	}
+	c.recorder.Event(cr, event.Normal(reasonSite, msg))
+	return nil
+}
+
+// geofenceSite returns the site whose geofence contains the Device's recorded
+// location, or "" when it has none, sites declare no geofences or none
+// contains it. The last case sets OutsideGeofences.
+func (c *external) geofenceSite(ctx context.Context, cr *v1alpha1.Device) (string, error) {
+	obs := &cr.Status.AtProvider
+	if obs.Location == nil {
+		return "", nil
+	}
+	p, err := geofence.ParsePoint(obs.Location.Latitude, obs.Location.Longitude)
+	if err != nil {
+		log.Printf("Skipping geofences for Device %s: %v", cr.GetName(), err)
+		return "", nil
+	}
+
+	sl := &v1alpha1.SiteList{}
+	if err := c.kube.List(ctx, sl); err != nil {
+		return "", errors.Wrap(err, errListSites)
+	}
+	if !geofence.Any(sl.Items) {
+		return "", nil
+	}
+
+	site := geofence.Match(sl.Items, p)
+	obs.OutsideGeofences = site == ""
+	return site, nil
+}
@@ -20,11 +20,19 @@ import (
 	"context"
 	"testing"
 
+	boards "github.com/MIKE9708/s4t-sdk-go/pkg/api/data/board"
 	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
+	"github.com/crossplane/crossplane-runtime/pkg/event"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/provider-s4t/apis/iot/v1alpha1"
 )
 
 // Unlike many Kubernetes projects Crossplane does not use third party testing
@@ -68,3 +76,69 @@ func TestObserve(t *testing.T) {
 		})
 	}
 }
+
+func TestAssignSite(t *testing.T) {
+	messina := &v1alpha1.Site{ObjectMeta: metav1.ObjectMeta{Name: "messina"}}
+	messina.Spec.ForProvider.Geofence = &v1alpha1.Geofence{
+		Center:       &v1alpha1.GeoPoint{Latitude: "38.19", Longitude: "15.55"},
+		RadiusMeters: 10000,
+	}
+
+	device := func(labels map[string]string, lat, lon string) *v1alpha1.Device {
+		d := &v1alpha1.Device{ObjectMeta: metav1.ObjectMeta{Name: "board", Labels: labels}}
+		d.Spec.ForProvider.Location = []v1alpha1.Location{{Latitude: lat, Longitude: lon}}
+		return d
+	}
+
+	type want struct {
+		labels  map[string]string
+		site    string
+		outside bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		d      *v1alpha1.Device
+		want   want
+	}{
+		"Inside": {
+			reason: "A board inside a geofence should get the geofence-site label and keep its site label.",
+			d:      device(map[string]string{v1alpha1.LabelSite: "catania"}, "38.20", "15.56"),
+			want: want{
+				labels: map[string]string{v1alpha1.LabelSite: "catania", v1alpha1.LabelGeofenceSite: "messina"},
+				site:   "messina",
+			},
+		},
+		"Outside": {
+			reason: "A board leaving every geofence should lose its geofence-site label and keep its site label.",
+			d:      device(map[string]string{v1alpha1.LabelSite: "catania", v1alpha1.LabelGeofenceSite: "messina"}, "37.50", "15.08"),
+			want: want{
+				labels:  map[string]string{v1alpha1.LabelSite: "catania"},
+				outside: true,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s := runtime.NewScheme()
+			if err := v1alpha1.SchemeBuilder.AddToScheme(s); err != nil {
+				t.Fatal(err)
+			}
+			kube := fake.NewClientBuilder().WithScheme(s).WithObjects(messina.DeepCopy(), tc.d).Build()
+			e := &external{kube: kube, recorder: event.NewNopRecorder()}
+			if err := e.assignSite(context.Background(), tc.d, &boards.Board{}); err != nil {
+				t.Fatalf("\n%s\ne.assignSite(...): unexpected error: %v", tc.reason, err)
+			}
+
+			stored := &v1alpha1.Device{}
+			if err := kube.Get(context.Background(), types.NamespacedName{Name: "board"}, stored); err != nil {
+				t.Fatal(err)
+			}
+			got := want{labels: stored.GetLabels(), site: tc.d.Status.AtProvider.GeofenceSite, outside: tc.d.Status.AtProvider.OutsideGeofences}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\ne.assignSite(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -24,28 +24,36 @@ import (
 	"io"
 	"log"
 	"net/http"
-	"os"
-	"strings"
 	"time"
 
 	s4t "github.com/MIKE9708/s4t-sdk-go/pkg/api"
-	read_config "github.com/MIKE9708/s4t-sdk-go/pkg/read_conf"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/connection"
 	"github.com/crossplane/crossplane-runtime/pkg/controller"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlevent "sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/crossplane/provider-s4t/apis/iot/v1alpha1"
 	apisv1alpha1 "github.com/crossplane/provider-s4t/apis/v1alpha1"
+	"github.com/crossplane/provider-s4t/internal/diff"
 	"github.com/crossplane/provider-s4t/internal/features"
+	"github.com/crossplane/provider-s4t/internal/providerconfig"
 )
 
 const (
@@ -54,6 +62,17 @@ const (
 	errGetPC        = "cannot get ProviderConfig"
 	errGetCreds     = "cannot get credentials"
 	errNewClient    = "cannot create new Service"
+	errListDevices  = "cannot list Devices"
+
+	// boardStatusOnline is the IoTronic status of a board whose Lightning Rod
+	// is connected.
+	boardStatusOnline = "online"
+
+	// defaultDegradedOfflinePercent applies when DegradedOfflinePercent is
+	// unset.
+	defaultDegradedOfflinePercent = 25
+
+	reasonDrift event.Reason = "DriftDetected"
 )
 
 type S4TService struct {
@@ -64,69 +83,11 @@ type S4TService struct {
 
 var (
 	newS4TService = func(creds []byte, keystoneEndpoint string) (*S4TService, error) {
-		var result map[string]string
-		err := json.Unmarshal(creds, &result)
-		if err != nil {
-			return nil, errors.Wrap(err, errNewClient)
-		}
-		auth_req := read_config.FormatAuthRequ(
-			result["username"],
-			result["password"],
-			result["domain"],
-		)
-		
-		// Set Keystone endpoint via environment variable (OS_AUTH_URL)
-		if keystoneEndpoint != "" {
-			os.Setenv("OS_AUTH_URL", keystoneEndpoint)
-		} else {
-			os.Setenv("OS_AUTH_URL", "http://keystone.default.svc.cluster.local:5000/v3")
-		}
-		// Extract host from keystoneEndpoint BEFORE creating client
-		// SDK uses hardcoded 127.0.0.1, we need to use Kubernetes service
-		endpoint := keystoneEndpoint
-		if endpoint == "" {
-			endpoint = "http://keystone.default.svc.cluster.local:5000/v3"
-		}
-		if strings.HasSuffix(endpoint, "/v3") {
-			endpoint = strings.TrimSuffix(endpoint, "/v3")
-		}
-		scheme := "http://"
-		if strings.HasPrefix(endpoint, "https://") {
-			scheme = "https://"
-			endpoint = strings.TrimPrefix(endpoint, "https://")
-		} else if strings.HasPrefix(endpoint, "http://") {
-			endpoint = strings.TrimPrefix(endpoint, "http://")
-		}
-		if idx := strings.Index(endpoint, ":"); idx != -1 {
-			endpoint = endpoint[:idx]
-		}
-		keystoneHost := scheme + endpoint
-		
-		// Create client manually with correct host (without port) instead of using GetClientConnection
-		// SDK will derive final URL using AuthPort
-		s4t_client := s4t.NewClient(keystoneHost)
-		s4t_client.Port = "8812"
-		s4t_client.AuthPort = "5000"
-		
-		// Authenticate with correct endpoint
-		token, err := s4t_client.Authenticate(s4t_client, auth_req)
-		if err != nil {
-			return nil, errors.Wrap(err, errNewClient)
-		}
-		s4t_client.AuthToken = token
-		
-		iotronicHost := scheme + "iotronic-conductor.default.svc.cluster.local"
-		s4t_client.Endpoint = iotronicHost
-		
+		c, err := providerconfig.Connect(creds, keystoneEndpoint)
 		if err != nil {
 			return nil, errors.Wrap(err, errNewClient)
 		}
-		// Extract base URL and token from client if available
-		// For now, we'll use default conductor endpoint
-		return &S4TService{
-			S4tClient: s4t_client,
-			BaseURL:   "http://iotronic-conductor:8812", // Default conductor endpoint
-		}, nil
+		return &S4TService{S4tClient: c}, nil
 	}
 )
 
@@ -139,28 +100,83 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), apisv1alpha1.StoreConfigGroupVersionKind))
 	}
 
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
+
 	r := managed.NewReconciler(mgr,
 		resource.ManagedKind(v1alpha1.FleetGroupVersionKind),
 		managed.WithExternalConnecter(&connector{
 			kube:         mgr.GetClient(),
-			usage:        resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
+			usage:        providerconfig.NewTracker(mgr.GetClient()),
+			recorder:     recorder,
 			newServiceFn: newS4TService}),
 		managed.WithLogger(o.Logger.WithValues("controller", name)),
 		managed.WithPollInterval(o.PollInterval),
-		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		managed.WithRecorder(recorder),
 		managed.WithConnectionPublishers(cps...))
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
 		WithOptions(o.ForControllerRuntime()).
-		WithEventFilter(resource.DesiredStateChanged()).
-		For(&v1alpha1.Fleet{}).
+		For(&v1alpha1.Fleet{}, builder.WithPredicates(resource.DesiredStateChanged())).
+		Watches(&v1alpha1.Device{},
+			handler.EnqueueRequestsFromMapFunc(fleetsForDevice(mgr.GetClient())),
+			builder.WithPredicates(deviceChanged)).
 		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
 }
 
+// fleetsForDevice maps a Device to the Fleets that reference it or select
+// by label, so that membership and health follow the Device promptly.
+func fleetsForDevice(kube client.Client) handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		l := &v1alpha1.FleetList{}
+		if err := kube.List(ctx, l); err != nil {
+			log.Printf("Error listing Fleets for Device %s: %v", obj.GetName(), err)
+			return nil
+		}
+		var reqs []reconcile.Request
+		for _, f := range l.Items {
+			if f.Spec.ForProvider.DeviceSelector == nil && !referencesDevice(f.Spec.ForProvider.DeviceRefs, obj.GetName()) {
+				continue
+			}
+			reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{Name: f.GetName()}})
+		}
+		return reqs
+	}
+}
+
+func referencesDevice(refs []xpv1.Reference, name string) bool {
+	for _, r := range refs {
+		if r.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// deviceChanged passes Device events that can change fleet membership or
+// health: creation, deletion, and changes to labels, board UUID or status.
+var deviceChanged = predicate.Funcs{
+	GenericFunc: func(ctrlevent.GenericEvent) bool { return false },
+	UpdateFunc: func(e ctrlevent.UpdateEvent) bool {
+		o, ok := e.ObjectOld.(*v1alpha1.Device)
+		if !ok {
+			return false
+		}
+		n, ok := e.ObjectNew.(*v1alpha1.Device)
+		if !ok {
+			return false
+		}
+		return !labels.Equals(o.GetLabels(), n.GetLabels()) ||
+			o.Spec.ForProvider.Uuid != n.Spec.ForProvider.Uuid ||
+			o.Status.Status != n.Status.Status ||
+			meta.WasDeleted(o) != meta.WasDeleted(n)
+	},
+}
+
 type connector struct {
 	kube         client.Client
 	usage        resource.Tracker
+	recorder     event.Recorder
 	newServiceFn func(creds []byte, keystoneEndpoint string) (*S4TService, error)
 }
 
@@ -174,8 +190,8 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 		return nil, errors.Wrap(err, errTrackPCUsage)
 	}
 
-	pc_domain := &apisv1alpha1.ProviderConfig{}
-	if err := c.kube.Get(ctx, types.NamespacedName{Name: "s4t-provider-domain"}, pc_domain); err != nil {
+	pc_domain, err := providerconfig.Resolve(ctx, c.kube, mg)
+	if err != nil {
 		return nil, errors.Wrap(err, errGetPC)
 	}
 	cd_domain := pc_domain.Spec.Credentials
@@ -194,7 +210,10 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 	if err != nil {
 		return nil, errors.Wrap(err, errNewClient)
 	}
-	return &external{service: svc}, err
+	if err := providerconfig.Configure(svc.S4tClient, pc_domain); err != nil {
+		return nil, errors.Wrap(err, errNewClient)
+	}
+	return &external{kube: c.kube, service: svc, recorder: c.recorder}, err
 }
 
 // An ExternalClient observes, then either creates, updates, or deletes an
@@ -202,14 +221,139 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 type external struct {
 	// A 'client' used to connect to the external resource API. In practice this
 	// would be something like an AWS SDK client.
-	service *S4TService
+	kube     client.Client
+	service  *S4TService
+	recorder event.Recorder
+}
+
+// memberBoard is a board as listed by the fleet's boards endpoint.
+type memberBoard struct {
+	Uuid   string `json:"uuid"`
+	Status string `json:"status"`
+}
+
+// fleetBoards returns the boards IoTronic associates with the fleet.
+// API: GET /v1/fleets/{uuid}/boards
+// Response: {"boards": [{"uuid": "...", "status": "...", ...}]}
+func (c *external) fleetBoards(uuid string) ([]memberBoard, error) {
+	resp, err := c.makeRESTCall("GET", fmt.Sprintf("/fleets/%s/boards", uuid), nil)
+	if err != nil {
+		log.Printf("Error getting fleet boards: %v", err)
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Boards []memberBoard `json:"boards"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, errors.Wrap(err, "failed to decode response")
+	}
+	return body.Boards, nil
+}
+
+// desiredMembers returns the UUIDs of the boards of the referenced and
+// selected Devices, or nil if the fleet does not manage its membership.
+// Devices without a board UUID yet are skipped until they are registered.
+func (c *external) desiredMembers(ctx context.Context, cr *v1alpha1.Fleet) (map[string]bool, error) {
+	p := cr.Spec.ForProvider
+	if len(p.DeviceRefs) == 0 && p.DeviceSelector == nil {
+		return nil, nil
+	}
+
+	l := &v1alpha1.DeviceList{}
+	if err := c.kube.List(ctx, l); err != nil {
+		return nil, errors.Wrap(err, errListDevices)
+	}
+	sel := labels.Nothing()
+	if p.DeviceSelector != nil {
+		s, err := metav1.LabelSelectorAsSelector(p.DeviceSelector)
+		if err != nil {
+			return nil, errors.Wrap(err, errListDevices)
+		}
+		sel = s
+	}
+
+	members := map[string]bool{}
+	for _, d := range l.Items {
+		if meta.WasDeleted(&d) || d.Spec.ForProvider.Uuid == "" {
+			continue
+		}
+		if referencesDevice(p.DeviceRefs, d.GetName()) || sel.Matches(labels.Set(d.GetLabels())) {
+			members[d.Spec.ForProvider.Uuid] = true
+		}
+	}
+	return members, nil
+}
+
+// setBoardFleet associates a board with a fleet, or removes it from its fleet
+// when fleet is empty.
+// API: PATCH /v1/boards/{uuid}
+// Request Body: {"fleet": "fleet_uuid" | null}
+func (c *external) setBoardFleet(board, fleet string) error {
+	var f interface{}
+	if fleet != "" {
+		f = fleet
+	}
+	resp, err := c.makeRESTCall("PATCH", fmt.Sprintf("/boards/%s", board), map[string]interface{}{"fleet": f})
+	if err != nil {
+		log.Printf("Error updating board fleet: %v", err)
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// observeMembers records member and online counts, sets the Degraded
+// condition and reports whether membership matches the desired set.
+func observeMembers(cr *v1alpha1.Fleet, boards []memberBoard, desired map[string]bool) bool {
+	obs := &cr.Status.AtProvider
+	obs.Members = len(boards)
+	obs.Online = 0
+	for _, b := range boards {
+		if b.Status == boardStatusOnline {
+			obs.Online++
+		}
+	}
+	obs.Offline = obs.Members - obs.Online
+
+	threshold := defaultDegradedOfflinePercent
+	if p := cr.Spec.ForProvider.DegradedOfflinePercent; p != nil {
+		threshold = *p
+	}
+	if obs.Members > 0 && obs.Offline*100 > threshold*obs.Members {
+		cr.Status.SetConditions(v1alpha1.Degraded(obs.Offline, obs.Members))
+	} else {
+		cr.Status.SetConditions(v1alpha1.NotDegraded())
+	}
+
+	if desired == nil {
+		return true
+	}
+	if len(boards) != len(desired) {
+		return false
+	}
+	for _, b := range boards {
+		if !desired[b.Uuid] {
+			return false
+		}
+	}
+	return true
 }
 
 // makeRESTCall makes a REST API call to the IoTronic service
 func (c *external) makeRESTCall(method, path string, data interface{}) (*http.Response, error) {
 	// Build URL using the service client's endpoint
 	// Default to Kubernetes service if host is not set
-	baseURL := fmt.Sprintf("http://iotronic-conductor.default.svc.cluster.local:%s", c.service.S4tClient.Port)
+	baseURL := fmt.Sprintf("%s:%s", c.service.S4tClient.Endpoint, c.service.S4tClient.Port)
 	url := fmt.Sprintf("%s/v1%s", baseURL, path)
 	
 	var reqBody io.Reader
@@ -253,33 +397,79 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		return managed.ExternalObservation{ResourceExists: false}, nil
 	}
 
-	resp, err := c.makeRESTCall("GET", fmt.Sprintf("/fleets/%s", cr.Spec.ForProvider.Uuid), nil)
+	fleet, err := c.getFleet(cr.Spec.ForProvider.Uuid)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+	if fleet == nil {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	d := diff.Compute(fleetFields(cr.Spec.ForProvider), fleet)
+
+	boards, err := c.fleetBoards(cr.Spec.ForProvider.Uuid)
 	if err != nil {
-		log.Printf("Error getting fleet: %v", err)
 		return managed.ExternalObservation{}, err
 	}
+	desired, err := c.desiredMembers(ctx, cr)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+	membersUpToDate := observeMembers(cr, boards, desired)
+
+	cr.Status.SetConditions(xpv1.Available())
+
+	return managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: membersUpToDate && d.Empty(),
+		ConnectionDetails: managed.ConnectionDetails{},
+	}, nil
+}
+
+// getFleet returns the fleet as IoTronic reports it, or nil
+// if it does not exist.
+// API: GET /v1/fleets/{uuid}
+func (c *external) getFleet(uuid string) (map[string]interface{}, error) {
+	resp, err := c.makeRESTCall("GET", fmt.Sprintf("/fleets/%s", uuid), nil)
+	if err != nil {
+		log.Printf("Error getting fleet: %v", err)
+		return nil, err
+	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusNotFound {
-		return managed.ExternalObservation{ResourceExists: false}, nil
+		return nil, nil
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return managed.ExternalObservation{}, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
 	}
 
 	var fleet map[string]interface{}
 	if err := json.NewDecoder(resp.Body).Decode(&fleet); err != nil {
-		return managed.ExternalObservation{}, errors.Wrap(err, "failed to decode response")
+		return nil, errors.Wrap(err, "failed to decode response")
 	}
+	return fleet, nil
+}
 
-	cr.Status.SetConditions(xpv1.Available())
+// fleetFields maps FleetParameters to IoTronic fleet attributes.
+func fleetFields(p v1alpha1.FleetParameters) []diff.Field {
+	return []diff.Field{
+		{Name: "name", Desired: p.Name},
+		{Name: "description", Desired: p.Description, IgnoreZero: true},
+		{Name: "extra", Desired: rawObject(p.Extra), IgnoreZero: true},
+	}
+}
 
-	return managed.ExternalObservation{
-		ResourceExists:   true,
-		ResourceUpToDate: true,
-		ConnectionDetails: managed.ConnectionDetails{},
-	}, nil
+// rawObject decodes a RawExtension holding a JSON object, or returns nil.
+func rawObject(r runtime.RawExtension) map[string]interface{} {
+	if len(r.Raw) == 0 {
+		return nil
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(r.Raw, &m); err != nil {
+		return nil
+	}
+	return m
 }
 
 func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
@@ -337,21 +527,19 @@ func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 
 	fmt.Printf("Updating Fleet: %+v", cr)
 
-	fleetData := map[string]interface{}{}
-	if cr.Spec.ForProvider.Name != "" {
-		fleetData["name"] = cr.Spec.ForProvider.Name
+	observed, err := c.getFleet(cr.Spec.ForProvider.Uuid)
+	if err != nil {
+		return managed.ExternalUpdate{}, err
 	}
-	if cr.Spec.ForProvider.Description != "" {
-		fleetData["description"] = cr.Spec.ForProvider.Description
+	d := diff.Compute(fleetFields(cr.Spec.ForProvider), observed)
+	if d.Empty() {
+		return managed.ExternalUpdate{ConnectionDetails: managed.ConnectionDetails{}}, c.syncMembers(ctx, cr)
 	}
-	if len(cr.Spec.ForProvider.Extra.Raw) > 0 {
-		var extra map[string]interface{}
-		if err := json.Unmarshal(cr.Spec.ForProvider.Extra.Raw, &extra); err == nil {
-			fleetData["extra"] = extra
-		}
+	if c.recorder != nil {
+		c.recorder.Event(cr, event.Normal(reasonDrift, "Updating drifted fields: "+d.String()))
 	}
 
-	resp, err := c.makeRESTCall("PATCH", fmt.Sprintf("/fleets/%s", cr.Spec.ForProvider.Uuid), fleetData)
+	resp, err := c.makeRESTCall("PATCH", fmt.Sprintf("/fleets/%s", cr.Spec.ForProvider.Uuid), d.Patch())
 	if err != nil {
 		log.Printf("Error updating fleet: %v", err)
 		return managed.ExternalUpdate{}, err
@@ -362,11 +550,46 @@ func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalUpdate{}, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
 	}
 
+	if err := c.syncMembers(ctx, cr); err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+
 	return managed.ExternalUpdate{
 		ConnectionDetails: managed.ConnectionDetails{},
 	}, nil
 }
 
+// syncMembers adds the boards of the desired Devices to the fleet and
+// removes any other board from it.
+func (c *external) syncMembers(ctx context.Context, cr *v1alpha1.Fleet) error {
+	desired, err := c.desiredMembers(ctx, cr)
+	if err != nil || desired == nil {
+		return err
+	}
+	boards, err := c.fleetBoards(cr.Spec.ForProvider.Uuid)
+	if err != nil {
+		return err
+	}
+
+	for _, b := range boards {
+		if desired[b.Uuid] {
+			delete(desired, b.Uuid)
+			continue
+		}
+		log.Printf("Removing board %s from fleet %s", b.Uuid, cr.Spec.ForProvider.Uuid)
+		if err := c.setBoardFleet(b.Uuid, ""); err != nil {
+			return err
+		}
+	}
+	for b := range desired {
+		log.Printf("Adding board %s to fleet %s", b, cr.Spec.ForProvider.Uuid)
+		if err := c.setBoardFleet(b, cr.Spec.ForProvider.Uuid); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
 	cr, ok := mg.(*v1alpha1.Fleet)
 	if !ok {
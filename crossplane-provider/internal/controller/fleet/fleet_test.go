@@ -0,0 +1,156 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fleet
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/crossplane/provider-s4t/apis/iot/v1alpha1"
+)
+
+func device(name, uuid string, labels map[string]string) *v1alpha1.Device {
+	d := &v1alpha1.Device{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+	d.Spec.ForProvider.Uuid = uuid
+	return d
+}
+
+func TestDesiredMembers(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := v1alpha1.SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatalf("AddToScheme: %v", err)
+	}
+	deleted := device("gone", "b5", map[string]string{"zone": "north"})
+	deleted.SetDeletionTimestamp(&metav1.Time{Time: time.Now()})
+	deleted.SetFinalizers([]string{"finalizer.managedresource.crossplane.io"})
+	objs := []client.Object{
+		device("a", "b1", map[string]string{"zone": "north"}),
+		device("b", "b2", map[string]string{"zone": "south"}),
+		device("c", "b3", nil),
+		device("unregistered", "", map[string]string{"zone": "north"}),
+		deleted,
+	}
+	e := &external{kube: fake.NewClientBuilder().WithScheme(s).WithObjects(objs...).Build()}
+
+	cases := map[string]struct {
+		params v1alpha1.FleetParameters
+		want   map[string]bool
+	}{
+		"Unmanaged": {
+			want: nil,
+		},
+		"References": {
+			params: v1alpha1.FleetParameters{DeviceRefs: []xpv1.Reference{{Name: "b"}, {Name: "unregistered"}, {Name: "missing"}}},
+			want:   map[string]bool{"b2": true},
+		},
+		"Selector": {
+			params: v1alpha1.FleetParameters{DeviceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"zone": "north"}}},
+			want:   map[string]bool{"b1": true},
+		},
+		"Both": {
+			params: v1alpha1.FleetParameters{
+				DeviceRefs:     []xpv1.Reference{{Name: "c"}},
+				DeviceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"zone": "north"}},
+			},
+			want: map[string]bool{"b1": true, "b3": true},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := &v1alpha1.Fleet{}
+			cr.Spec.ForProvider = tc.params
+			got, err := e.desiredMembers(context.Background(), cr)
+			if err != nil {
+				t.Fatalf("desiredMembers: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("desiredMembers: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestObserveMembers(t *testing.T) {
+	boards := []memberBoard{
+		{Uuid: "b1", Status: boardStatusOnline},
+		{Uuid: "b2", Status: boardStatusOnline},
+		{Uuid: "b3", Status: "offline"},
+		{Uuid: "b4", Status: boardStatusOnline},
+	}
+
+	type want struct {
+		upToDate bool
+		obs      v1alpha1.FleetObservation
+		degraded corev1.ConditionStatus
+	}
+	cases := map[string]struct {
+		threshold *int
+		boards    []memberBoard
+		desired   map[string]bool
+		want      want
+	}{
+		"Unmanaged": {
+			boards: boards,
+			want:   want{upToDate: true, obs: v1alpha1.FleetObservation{Members: 4, Online: 3, Offline: 1}, degraded: corev1.ConditionFalse},
+		},
+		"Matching": {
+			boards:  boards,
+			desired: map[string]bool{"b1": true, "b2": true, "b3": true, "b4": true},
+			want:    want{upToDate: true, obs: v1alpha1.FleetObservation{Members: 4, Online: 3, Offline: 1}, degraded: corev1.ConditionFalse},
+		},
+		"MissingMember": {
+			boards:  boards[:3],
+			desired: map[string]bool{"b1": true, "b2": true, "b3": true, "b4": true},
+			want:    want{obs: v1alpha1.FleetObservation{Members: 3, Online: 2, Offline: 1}, degraded: corev1.ConditionTrue},
+		},
+		"ExtraMember": {
+			boards:  boards,
+			desired: map[string]bool{"b1": true, "b2": true, "b3": true, "b5": true},
+			want:    want{obs: v1alpha1.FleetObservation{Members: 4, Online: 3, Offline: 1}, degraded: corev1.ConditionFalse},
+		},
+		"Threshold": {
+			threshold: ptr.To(20),
+			boards:    boards,
+			want:      want{upToDate: true, obs: v1alpha1.FleetObservation{Members: 4, Online: 3, Offline: 1}, degraded: corev1.ConditionTrue},
+		},
+		"Empty": {
+			desired: map[string]bool{},
+			want:    want{upToDate: true, degraded: corev1.ConditionFalse},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := &v1alpha1.Fleet{}
+			cr.Spec.ForProvider.DegradedOfflinePercent = tc.threshold
+			upToDate := observeMembers(cr, tc.boards, tc.desired)
+			got := want{upToDate: upToDate, obs: cr.Status.AtProvider, degraded: cr.Status.GetCondition(v1alpha1.TypeDegraded).Status}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("observeMembers: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
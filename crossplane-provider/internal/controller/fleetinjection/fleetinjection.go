@@ -0,0 +1,615 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fleetinjection
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	s4t "github.com/MIKE9708/s4t-sdk-go/pkg/api"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/connection"
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlevent "sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/provider-s4t/apis/iot/v1alpha1"
+	apisv1alpha1 "github.com/crossplane/provider-s4t/apis/v1alpha1"
+	"github.com/crossplane/provider-s4t/internal/features"
+	"github.com/crossplane/provider-s4t/internal/names"
+	"github.com/crossplane/provider-s4t/internal/providerconfig"
+)
+
+const (
+	errNotFleetInjection = "managed resource is not a FleetInjection custom resource"
+	errTrackPCUsage      = "cannot track ProviderConfig usage"
+	errGetPC             = "cannot get ProviderConfig"
+	errGetCreds          = "cannot get credentials"
+	errNewClient         = "cannot create new Service"
+	errNoTarget          = "exactly one of pluginRef and serviceRef must be set"
+	errNoSelector        = "exactly one of deviceSelector and fleetRef must be set"
+	errListDevices       = "cannot list Devices"
+	errListChildren      = "cannot list owned injections"
+	errGetFleet          = "cannot get referenced Fleet"
+	errFleetNoUUID       = "referenced Fleet has no UUID yet"
+	errApplyChild        = "cannot create owned injection"
+	errDeleteChild       = "cannot delete owned injection"
+	errGetChild          = "cannot get existing injection"
+	errNotControlled     = "injection %q exists and is not controlled by this FleetInjection"
+)
+
+type S4TService struct {
+	S4tClient *s4t.Client
+	BaseURL   string
+	Token     string
+}
+
+var (
+	newS4TService = func(creds []byte, keystoneEndpoint string) (*S4TService, error) {
+		c, err := providerconfig.Connect(creds, keystoneEndpoint)
+		if err != nil {
+			return nil, errors.Wrap(err, errNewClient)
+		}
+		return &S4TService{S4tClient: c}, nil
+	}
+)
+
+// Setup adds a controller that reconciles FleetInjection managed resources.
+func Setup(mgr ctrl.Manager, o controller.Options) error {
+	name := managed.ControllerName(v1alpha1.FleetInjectionGroupKind)
+
+	cps := []managed.ConnectionPublisher{managed.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme())}
+	if o.Features.Enabled(features.EnableAlphaExternalSecretStores) {
+		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), apisv1alpha1.StoreConfigGroupVersionKind))
+	}
+
+	r := managed.NewReconciler(mgr,
+		resource.ManagedKind(v1alpha1.FleetInjectionGroupVersionKind),
+		managed.WithExternalConnecter(&connector{
+			kube:         mgr.GetClient(),
+			usage:        providerconfig.NewTracker(mgr.GetClient()),
+			newServiceFn: newS4TService}),
+		managed.WithLogger(o.Logger.WithValues("controller", name)),
+		managed.WithPollInterval(o.PollInterval),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		managed.WithConnectionPublishers(cps...))
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		For(&v1alpha1.FleetInjection{}, builder.WithPredicates(resource.DesiredStateChanged())).
+		Owns(&v1alpha1.BoardPluginInjection{}).
+		Owns(&v1alpha1.BoardServiceInjection{}).
+		Watches(&v1alpha1.Device{},
+			handler.EnqueueRequestsFromMapFunc(allFleetInjections(mgr.GetClient())),
+			builder.WithPredicates(membershipChanged)).
+		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+}
+
+// allFleetInjections maps a Device to every FleetInjection, so that boards
+// are picked up or released as soon as they start or stop matching rather
+// than on the next poll.
+func allFleetInjections(kube client.Client) handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		l := &v1alpha1.FleetInjectionList{}
+		if err := kube.List(ctx, l); err != nil {
+			log.Printf("Error listing FleetInjections for Device %s: %v", obj.GetName(), err)
+			return nil
+		}
+		reqs := make([]reconcile.Request, 0, len(l.Items))
+		for _, i := range l.Items {
+			reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{Name: i.GetName()}})
+		}
+		return reqs
+	}
+}
+
+// membershipChanged passes Device events that can change which
+// FleetInjections select it: creation, deletion, and changes to its labels or
+// board UUID.
+var membershipChanged = predicate.Funcs{
+	GenericFunc: func(ctrlevent.GenericEvent) bool { return false },
+	UpdateFunc: func(e ctrlevent.UpdateEvent) bool {
+		o, ok := e.ObjectOld.(*v1alpha1.Device)
+		if !ok {
+			return false
+		}
+		n, ok := e.ObjectNew.(*v1alpha1.Device)
+		if !ok {
+			return false
+		}
+		return !labels.Equals(o.GetLabels(), n.GetLabels()) ||
+			o.Spec.ForProvider.Uuid != n.Spec.ForProvider.Uuid ||
+			meta.WasDeleted(o) != meta.WasDeleted(n)
+	},
+}
+
+type connector struct {
+	kube         client.Client
+	usage        resource.Tracker
+	newServiceFn func(creds []byte, keystoneEndpoint string) (*S4TService, error)
+}
+
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	_, ok := mg.(*v1alpha1.FleetInjection)
+	if !ok {
+		return nil, errors.New(errNotFleetInjection)
+	}
+
+	if err := c.usage.Track(ctx, mg); err != nil {
+		return nil, errors.Wrap(err, errTrackPCUsage)
+	}
+
+	pc_domain, err := providerconfig.Resolve(ctx, c.kube, mg)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetPC)
+	}
+	cd_domain := pc_domain.Spec.Credentials
+	data_domain, err := resource.CommonCredentialExtractor(ctx, cd_domain.Source, c.kube, cd_domain.CommonCredentialSelectors)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetCreds)
+	}
+
+	// Get Keystone endpoint from ProviderConfig, default to Kubernetes service
+	keystoneEndpoint := pc_domain.Spec.KeystoneEndpoint
+	if keystoneEndpoint == "" {
+		keystoneEndpoint = "http://keystone.default.svc.cluster.local:5000/v3"
+	}
+
+	svc, err := c.newServiceFn(data_domain, keystoneEndpoint)
+	if err != nil {
+		return nil, errors.Wrap(err, errNewClient)
+	}
+	if err := providerconfig.Configure(svc.S4tClient, pc_domain); err != nil {
+		return nil, errors.Wrap(err, errNewClient)
+	}
+	return &external{kube: c.kube, service: svc}, err
+}
+
+// An ExternalClient observes, then either creates, updates, or deletes an
+// external resource to ensure it reflects the managed resource's desired state.
+// For a FleetInjection the "external" resources are the BoardPluginInjections
+// or BoardServiceInjections it owns; IoTronic is only queried to resolve Fleet
+// membership.
+type external struct {
+	kube    client.Client
+	service *S4TService
+}
+
+// makeRESTCall makes a REST API call to the IoTronic service
+func (c *external) makeRESTCall(method, path string, data interface{}) (*http.Response, error) {
+	// Build URL using the service client's endpoint
+	// Default to Kubernetes service if host is not set
+	baseURL := fmt.Sprintf("%s:%s", c.service.S4tClient.Endpoint, c.service.S4tClient.Port)
+	url := fmt.Sprintf("%s/v1%s", baseURL, path)
+
+	var reqBody io.Reader
+	if data != nil {
+		jsonData, err := json.Marshal(data)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal request data")
+		}
+		reqBody = bytes.NewBuffer(jsonData)
+	}
+
+	req, err := http.NewRequest(method, url, reqBody)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create request")
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if c.service.S4tClient.AuthToken != "" {
+		req.Header.Set("X-Auth-Token", c.service.S4tClient.AuthToken)
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to execute request")
+	}
+
+	return resp, nil
+}
+
+// ownedInjection is a BoardPluginInjection or BoardServiceInjection owned by
+// a FleetInjection.
+type ownedInjection struct {
+	resource.Managed
+
+	// device is the name of the Device the injection targets.
+	device string
+
+	// pluginRef and serviceRef are the names of the referenced Plugin or
+	// Service; only the one matching the kind is set.
+	pluginRef  string
+	serviceRef string
+}
+
+// matches reports whether the injection still implements cr for device.
+func (o ownedInjection) matches(cr *v1alpha1.FleetInjection) bool {
+	p := cr.Spec.ForProvider
+	switch {
+	case p.PluginRef != nil:
+		return o.pluginRef == p.PluginRef.Name
+	case p.ServiceRef != nil:
+		return o.serviceRef == p.ServiceRef.Name
+	}
+	return false
+}
+
+func refName(ref *xpv1.Reference) string {
+	if ref == nil {
+		return ""
+	}
+	return ref.Name
+}
+
+func validate(cr *v1alpha1.FleetInjection) error {
+	p := cr.Spec.ForProvider
+	if (p.PluginRef == nil) == (p.ServiceRef == nil) {
+		return errors.New(errNoTarget)
+	}
+	if (p.DeviceSelector == nil) == (p.FleetRef == nil) {
+		return errors.New(errNoSelector)
+	}
+	return nil
+}
+
+// fleetBoards returns the UUIDs of the boards that belong to the referenced
+// Fleet.
+// API: GET /v1/fleets/{fleet_uuid}/boards
+// Response: {"boards": [{"uuid": "...", ...}]}
+func (c *external) fleetBoards(ctx context.Context, ref *xpv1.Reference) (map[string]bool, error) {
+	f := &v1alpha1.Fleet{}
+	if err := c.kube.Get(ctx, types.NamespacedName{Name: ref.Name}, f); err != nil {
+		return nil, errors.Wrap(err, errGetFleet)
+	}
+	if f.Spec.ForProvider.Uuid == "" {
+		return nil, errors.New(errFleetNoUUID)
+	}
+
+	resp, err := c.makeRESTCall("GET", fmt.Sprintf("/fleets/%s/boards", f.Spec.ForProvider.Uuid), nil)
+	if err != nil {
+		log.Printf("Error getting fleet boards: %v", err)
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Boards []struct {
+			Uuid string `json:"uuid"`
+		} `json:"boards"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, errors.Wrap(err, "failed to decode response")
+	}
+
+	boards := make(map[string]bool, len(body.Boards))
+	for _, b := range body.Boards {
+		boards[b.Uuid] = true
+	}
+	return boards, nil
+}
+
+// matchingDevices returns the Devices targeted by cr, keyed by name.
+func (c *external) matchingDevices(ctx context.Context, cr *v1alpha1.FleetInjection) (map[string]*v1alpha1.Device, error) {
+	l := &v1alpha1.DeviceList{}
+	if err := c.kube.List(ctx, l); err != nil {
+		return nil, errors.Wrap(err, errListDevices)
+	}
+
+	var match func(*v1alpha1.Device) bool
+	if s := cr.Spec.ForProvider.DeviceSelector; s != nil {
+		sel, err := metav1.LabelSelectorAsSelector(s)
+		if err != nil {
+			return nil, errors.Wrap(err, errListDevices)
+		}
+		match = func(d *v1alpha1.Device) bool { return sel.Matches(labels.Set(d.GetLabels())) }
+	} else {
+		boards, err := c.fleetBoards(ctx, cr.Spec.ForProvider.FleetRef)
+		if err != nil {
+			return nil, err
+		}
+		match = func(d *v1alpha1.Device) bool {
+			return d.Spec.ForProvider.Uuid != "" && boards[d.Spec.ForProvider.Uuid]
+		}
+	}
+
+	devices := map[string]*v1alpha1.Device{}
+	for i := range l.Items {
+		d := &l.Items[i]
+		if meta.WasDeleted(d) || !match(d) {
+			continue
+		}
+		devices[d.GetName()] = d
+	}
+	return devices, nil
+}
+
+// ownedInjections returns the injections controlled by cr, of either kind.
+func (c *external) ownedInjections(ctx context.Context, cr *v1alpha1.FleetInjection) ([]ownedInjection, error) {
+	sel := client.MatchingLabels{v1alpha1.LabelFleetInjection: cr.GetName()}
+	var owned []ownedInjection
+
+	pl := &v1alpha1.BoardPluginInjectionList{}
+	if err := c.kube.List(ctx, pl, sel); err != nil {
+		return nil, errors.Wrap(err, errListChildren)
+	}
+	for i := range pl.Items {
+		p := &pl.Items[i]
+		if !metav1.IsControlledBy(p, cr) {
+			continue
+		}
+		owned = append(owned, ownedInjection{
+			Managed:   p,
+			device:    refName(p.Spec.ForProvider.BoardRef),
+			pluginRef: refName(p.Spec.ForProvider.PluginRef),
+		})
+	}
+
+	sl := &v1alpha1.BoardServiceInjectionList{}
+	if err := c.kube.List(ctx, sl, sel); err != nil {
+		return nil, errors.Wrap(err, errListChildren)
+	}
+	for i := range sl.Items {
+		s := &sl.Items[i]
+		if !metav1.IsControlledBy(s, cr) {
+			continue
+		}
+		owned = append(owned, ownedInjection{
+			Managed:    s,
+			device:     refName(s.Spec.ForProvider.BoardRef),
+			serviceRef: refName(s.Spec.ForProvider.ServiceRef),
+		})
+	}
+	return owned, nil
+}
+
+// newInjection returns the injection cr owns for Device d.
+func newInjection(cr *v1alpha1.FleetInjection, d *v1alpha1.Device) resource.Managed {
+	om := metav1.ObjectMeta{
+		Name:            names.Child(cr.GetName(), d.GetName()),
+		Labels:          map[string]string{v1alpha1.LabelFleetInjection: cr.GetName()},
+		OwnerReferences: []metav1.OwnerReference{meta.AsController(meta.TypedReferenceTo(cr, v1alpha1.FleetInjectionGroupVersionKind))},
+	}
+	rs := xpv1.ResourceSpec{
+		ProviderConfigReference: cr.GetProviderConfigReference().DeepCopy(),
+		DeletionPolicy:          cr.GetDeletionPolicy(),
+	}
+	board := &xpv1.Reference{Name: d.GetName()}
+
+	var mg resource.Managed = &v1alpha1.BoardServiceInjection{
+		ObjectMeta: om,
+		Spec: v1alpha1.BoardServiceInjectionSpec{
+			ResourceSpec: rs,
+			ForProvider: v1alpha1.BoardServiceInjectionParameters{
+				BoardRef:   board,
+				ServiceRef: cr.Spec.ForProvider.ServiceRef.DeepCopy(),
+			},
+		},
+	}
+	if cr.Spec.ForProvider.PluginRef != nil {
+		mg = &v1alpha1.BoardPluginInjection{
+			ObjectMeta: om,
+			Spec: v1alpha1.BoardPluginInjectionSpec{
+				ResourceSpec: rs,
+				ForProvider: v1alpha1.BoardPluginInjectionParameters{
+					BoardRef:  board,
+					PluginRef: cr.Spec.ForProvider.PluginRef.DeepCopy(),
+				},
+			},
+		}
+	}
+	// The injection acts on the deployment serving the board, which need
+	// not be the one serving the FleetInjection.
+	providerconfig.Inherit(mg, cr, d)
+	return mg
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha1.FleetInjection)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotFleetInjection)
+	}
+
+	fmt.Printf("Observing FleetInjection: %+v", cr)
+
+	owned, err := c.ownedInjections(ctx, cr)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+	if meta.WasDeleted(cr) {
+		return managed.ExternalObservation{ResourceExists: len(owned) > 0}, nil
+	}
+	if err := validate(cr); err != nil {
+		return managed.ExternalObservation{}, err
+	}
+
+	devices, err := c.matchingDevices(ctx, cr)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+
+	obs := v1alpha1.FleetInjectionObservation{Matched: len(devices)}
+	upToDate := true
+	covered := map[string]bool{}
+	for _, o := range owned {
+		if devices[o.device] == nil || !o.matches(cr) {
+			upToDate = false
+			continue
+		}
+		covered[o.device] = true
+		switch ready := o.GetCondition(xpv1.TypeReady); {
+		case ready.Status == corev1.ConditionTrue:
+			obs.Ready++
+		case ready.Reason == v1alpha1.ReasonWaitingForBoard, ready.Reason == v1alpha1.ReasonWaitingForMaintenanceWindow:
+			obs.Waiting++
+		case o.GetCondition(xpv1.TypeSynced).Status == corev1.ConditionFalse:
+			obs.Failed++
+		}
+	}
+	if len(covered) != len(devices) {
+		upToDate = false
+	}
+	cr.Status.AtProvider = obs
+
+	if obs.Ready == obs.Matched {
+		cr.Status.SetConditions(xpv1.Available())
+	} else {
+		cr.Status.SetConditions(xpv1.Unavailable().WithMessage(
+			fmt.Sprintf("%d of %d boards ready, %d waiting, %d failed", obs.Ready, obs.Matched, obs.Waiting, obs.Failed)))
+	}
+
+	return managed.ExternalObservation{
+		ResourceExists:    len(owned) > 0 || len(devices) == 0,
+		ResourceUpToDate:  upToDate,
+		ConnectionDetails: managed.ConnectionDetails{},
+	}, nil
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1alpha1.FleetInjection)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotFleetInjection)
+	}
+
+	fmt.Printf("Creating FleetInjection: %+v", cr)
+
+	return managed.ExternalCreation{
+		ConnectionDetails: managed.ConnectionDetails{},
+	}, c.sync(ctx, cr)
+}
+
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*v1alpha1.FleetInjection)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotFleetInjection)
+	}
+
+	fmt.Printf("Updating FleetInjection: %+v", cr)
+
+	return managed.ExternalUpdate{
+		ConnectionDetails: managed.ConnectionDetails{},
+	}, c.sync(ctx, cr)
+}
+
+// sync deletes the owned injections whose Device no longer matches, or that
+// target a different Plugin or Service, and creates one for every matching
+// Device that lacks it.
+func (c *external) sync(ctx context.Context, cr *v1alpha1.FleetInjection) error {
+	devices, err := c.matchingDevices(ctx, cr)
+	if err != nil {
+		return err
+	}
+	owned, err := c.ownedInjections(ctx, cr)
+	if err != nil {
+		return err
+	}
+
+	covered := map[string]bool{}
+	for _, o := range owned {
+		if devices[o.device] != nil && o.matches(cr) {
+			covered[o.device] = true
+			continue
+		}
+		log.Printf("FleetInjection %s: releasing board of Device %s", cr.GetName(), o.device)
+		if err := c.kube.Delete(ctx, o.Managed); resource.IgnoreNotFound(err) != nil {
+			return errors.Wrap(err, errDeleteChild)
+		}
+	}
+
+	for name, d := range devices {
+		if covered[name] {
+			continue
+		}
+		log.Printf("FleetInjection %s: targeting board of Device %s", cr.GetName(), name)
+		if err := c.create(ctx, cr, newInjection(cr, d)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// create creates an injection owned by cr. An injection of the same name that
+// cr controls was created by an earlier reconcile that the cache does not
+// reflect yet; one that cr does not control is never taken over.
+func (c *external) create(ctx context.Context, cr *v1alpha1.FleetInjection, mg resource.Managed) error {
+	err := c.kube.Create(ctx, mg)
+	if !kerrors.IsAlreadyExists(err) {
+		return errors.Wrap(err, errApplyChild)
+	}
+	existing, ok := mg.DeepCopyObject().(resource.Managed)
+	if !ok {
+		return errors.Wrap(err, errApplyChild)
+	}
+	if err := c.kube.Get(ctx, types.NamespacedName{Name: mg.GetName()}, existing); err != nil {
+		return errors.Wrap(err, errGetChild)
+	}
+	if !metav1.IsControlledBy(existing, cr) {
+		return errors.Errorf(errNotControlled, mg.GetName())
+	}
+	return nil
+}
+
+// Delete removes every owned injection. Each of them in turn removes its
+// plugin or service from the board before it goes away.
+func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(*v1alpha1.FleetInjection)
+	if !ok {
+		return errors.New(errNotFleetInjection)
+	}
+
+	fmt.Printf("Deleting FleetInjection: %+v", cr)
+
+	owned, err := c.ownedInjections(ctx, cr)
+	if err != nil {
+		return err
+	}
+	for _, o := range owned {
+		if err := c.kube.Delete(ctx, o.Managed); resource.IgnoreNotFound(err) != nil {
+			return errors.Wrap(err, errDeleteChild)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fleetinjection
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/crossplane/provider-s4t/apis/iot/v1alpha1"
+	"github.com/crossplane/provider-s4t/internal/names"
+)
+
+func fleetInjection() *v1alpha1.FleetInjection {
+	cr := &v1alpha1.FleetInjection{ObjectMeta: metav1.ObjectMeta{Name: "collector", UID: types.UID("fi")}}
+	cr.Spec.ForProvider.PluginRef = &xpv1.Reference{Name: "collector"}
+	cr.Spec.ForProvider.DeviceSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"board-class": "gateway"}}
+	return cr
+}
+
+func device(name string, gateway bool) *v1alpha1.Device {
+	d := &v1alpha1.Device{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	if gateway {
+		d.SetLabels(map[string]string{"board-class": "gateway"})
+	}
+	return d
+}
+
+// owned returns the injection cr owns for the Device named device, in the
+// given Ready state.
+func owned(cr *v1alpha1.FleetInjection, device string, ready xpv1.Condition) *v1alpha1.BoardPluginInjection {
+	i := newInjection(cr, &v1alpha1.Device{ObjectMeta: metav1.ObjectMeta{Name: device}}).(*v1alpha1.BoardPluginInjection)
+	i.Status.SetConditions(ready)
+	return i
+}
+
+func newKube(t *testing.T, objs ...client.Object) client.Client {
+	t.Helper()
+	s := runtime.NewScheme()
+	if err := v1alpha1.SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatalf("AddToScheme: %v", err)
+	}
+	return fake.NewClientBuilder().WithScheme(s).WithObjects(objs...).WithStatusSubresource(&v1alpha1.BoardPluginInjection{}).Build()
+}
+
+func TestObserve(t *testing.T) {
+	cr := fleetInjection()
+
+	type want struct {
+		exists   bool
+		upToDate bool
+		obs      v1alpha1.FleetInjectionObservation
+	}
+	cases := map[string]struct {
+		objs []client.Object
+		want want
+	}{
+		"NothingOwned": {
+			objs: []client.Object{device("a", true)},
+			want: want{obs: v1alpha1.FleetInjectionObservation{Matched: 1}},
+		},
+		"Converged": {
+			objs: []client.Object{
+				device("a", true), device("b", true), device("c", false),
+				owned(cr, "a", xpv1.Available()),
+				owned(cr, "b", v1alpha1.WaitingForBoard("offline")),
+			},
+			want: want{exists: true, upToDate: true, obs: v1alpha1.FleetInjectionObservation{Matched: 2, Ready: 1, Waiting: 1}},
+		},
+		"Released": {
+			objs: []client.Object{
+				device("a", true), device("c", false),
+				owned(cr, "a", xpv1.Available()),
+				owned(cr, "c", xpv1.Available()),
+			},
+			want: want{exists: true, obs: v1alpha1.FleetInjectionObservation{Matched: 1, Ready: 1}},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := fleetInjection()
+			e := &external{kube: newKube(t, tc.objs...)}
+			o, err := e.Observe(context.Background(), cr)
+			if err != nil {
+				t.Fatalf("Observe: %v", err)
+			}
+			got := want{exists: o.ResourceExists, upToDate: o.ResourceUpToDate, obs: cr.Status.AtProvider}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("Observe: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSync(t *testing.T) {
+	cr := fleetInjection()
+	foreign := owned(cr, "a", xpv1.Available())
+	foreign.SetOwnerReferences(nil)
+
+	cases := map[string]struct {
+		objs    []client.Object
+		want    []string
+		wantErr bool
+	}{
+		"CreatesMissing": {
+			objs: []client.Object{device("a", true), device("b", true), owned(cr, "a", xpv1.Available())},
+			want: []string{"a", "b"},
+		},
+		"ReleasesUnmatched": {
+			objs: []client.Object{device("a", true), device("c", false), owned(cr, "c", xpv1.Available())},
+			want: []string{"a"},
+		},
+		"NotControlled": {
+			objs:    []client.Object{device("a", true), foreign},
+			wantErr: true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			kube := newKube(t, tc.objs...)
+			e := &external{kube: kube}
+			err := e.sync(context.Background(), fleetInjection())
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("sync: want error %t, got %v", tc.wantErr, err)
+			}
+			if tc.wantErr {
+				return
+			}
+			l := &v1alpha1.BoardPluginInjectionList{}
+			if err := kube.List(context.Background(), l); err != nil {
+				t.Fatalf("List: %v", err)
+			}
+			got := []string{}
+			for _, i := range l.Items {
+				if i.GetName() != names.Child(cr.GetName(), i.Spec.ForProvider.BoardRef.Name) {
+					t.Errorf("sync: injection %q is not named after its Device", i.GetName())
+				}
+				if !metav1.IsControlledBy(&i, cr) || meta.WasDeleted(&i) {
+					t.Errorf("sync: injection %q is not controlled by the FleetInjection", i.GetName())
+				}
+				got = append(got, i.Spec.ForProvider.BoardRef.Name)
+			}
+			sort.Strings(got)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("sync: -want, +got boards:\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,260 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maintenancewindow
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/connection"
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane/provider-s4t/apis/iot/v1alpha1"
+	apisv1alpha1 "github.com/crossplane/provider-s4t/apis/v1alpha1"
+	"github.com/crossplane/provider-s4t/internal/features"
+	"github.com/crossplane/provider-s4t/internal/maintenance"
+	"github.com/crossplane/provider-s4t/internal/providerconfig"
+)
+
+const (
+	errNotMaintenanceWindow = "managed resource is not a MaintenanceWindow custom resource"
+	errTrackPCUsage         = "cannot track ProviderConfig usage"
+	errListDevices          = "cannot list Devices"
+	errListFleets           = "cannot list Fleets"
+	errListSites            = "cannot list Sites"
+	errInUse                = "maintenance window is still referenced by %d Devices, Fleets and Sites"
+
+	reasonOpened event.Reason = "Opened"
+	reasonClosed event.Reason = "Closed"
+)
+
+// Setup adds a controller that reconciles MaintenanceWindow managed
+// resources. MaintenanceWindows have no IoTronic counterpart; they are
+// reconciled natively, and only report whether they are open. The
+// controllers that act on boards consult them through the maintenance
+// package.
+func Setup(mgr ctrl.Manager, o controller.Options) error {
+	name := managed.ControllerName(v1alpha1.MaintenanceWindowGroupKind)
+
+	cps := []managed.ConnectionPublisher{managed.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme())}
+	if o.Features.Enabled(features.EnableAlphaExternalSecretStores) {
+		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), apisv1alpha1.StoreConfigGroupVersionKind))
+	}
+
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
+
+	r := managed.NewReconciler(mgr,
+		resource.ManagedKind(v1alpha1.MaintenanceWindowGroupVersionKind),
+		managed.WithExternalConnecter(&connector{
+			kube:     mgr.GetClient(),
+			usage:    providerconfig.NewTracker(mgr.GetClient()),
+			recorder: recorder}),
+		managed.WithLogger(o.Logger.WithValues("controller", name)),
+		managed.WithPollInterval(o.PollInterval),
+		managed.WithPollIntervalHook(pollInterval),
+		managed.WithRecorder(recorder),
+		managed.WithConnectionPublishers(cps...))
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		For(&v1alpha1.MaintenanceWindow{}, builder.WithPredicates(resource.DesiredStateChanged())).
+		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+}
+
+// pollInterval observes MaintenanceWindows when they open or close, if that
+// is before their next poll, so that their status flips on time.
+func pollInterval(mg resource.Managed, d time.Duration) time.Duration {
+	cr, ok := mg.(*v1alpha1.MaintenanceWindow)
+	if !ok {
+		return d
+	}
+	for _, t := range []*metav1.Time{cr.Status.AtProvider.OpenUntil, cr.Status.AtProvider.NextOpen} {
+		if t == nil {
+			continue
+		}
+		if until := time.Until(t.Time); until < d {
+			d = max(until, 0) + time.Second
+		}
+	}
+	return d
+}
+
+// A connector is expected to produce an ExternalClient when its Connect method
+// is called.
+type connector struct {
+	kube     client.Client
+	usage    resource.Tracker
+	recorder event.Recorder
+}
+
+// Connect only tracks ProviderConfig usage; a MaintenanceWindow is reconciled
+// against the Kubernetes API.
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	_, ok := mg.(*v1alpha1.MaintenanceWindow)
+	if !ok {
+		return nil, errors.New(errNotMaintenanceWindow)
+	}
+
+	if err := c.usage.Track(ctx, mg); err != nil {
+		return nil, errors.Wrap(err, errTrackPCUsage)
+	}
+
+	return &external{kube: c.kube, recorder: c.recorder}, nil
+}
+
+// An ExternalClient observes, then either creates, updates, or deletes an
+// external resource to ensure it reflects the managed resource's desired state.
+type external struct {
+	kube     client.Client
+	recorder event.Recorder
+}
+
+// references counts the Devices, Fleets and Sites that reference the
+// window named name.
+func (c *external) references(ctx context.Context, name string) (int, error) {
+	n := 0
+	dl := &v1alpha1.DeviceList{}
+	if err := c.kube.List(ctx, dl); err != nil {
+		return 0, errors.Wrap(err, errListDevices)
+	}
+	for _, d := range dl.Items {
+		if ref := d.Spec.ForProvider.MaintenanceWindowRef; ref != nil && ref.Name == name {
+			n++
+		}
+	}
+	fl := &v1alpha1.FleetList{}
+	if err := c.kube.List(ctx, fl); err != nil {
+		return 0, errors.Wrap(err, errListFleets)
+	}
+	for _, f := range fl.Items {
+		if ref := f.Spec.ForProvider.MaintenanceWindowRef; ref != nil && ref.Name == name {
+			n++
+		}
+	}
+	sl := &v1alpha1.SiteList{}
+	if err := c.kube.List(ctx, sl); err != nil {
+		return 0, errors.Wrap(err, errListSites)
+	}
+	for _, s := range sl.Items {
+		if ref := s.Spec.ForProvider.MaintenanceWindowRef; ref != nil && ref.Name == name {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha1.MaintenanceWindow)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotMaintenanceWindow)
+	}
+	fmt.Printf("Observing MaintenanceWindow: %+v", cr)
+
+	refs, err := c.references(ctx, cr.GetName())
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+
+	// A MaintenanceWindow being deleted is kept, and Delete is called to
+	// report why, for as long as anything references it: without it, the
+	// boards it gates would lose their protection.
+	if meta.WasDeleted(cr) {
+		return managed.ExternalObservation{ResourceExists: refs > 0}, nil
+	}
+
+	open, until, next, err := maintenance.State(cr.Spec.ForProvider, time.Now())
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+
+	obs := &cr.Status.AtProvider
+	if open != obs.Open && (obs.OpenUntil != nil || obs.NextOpen != nil) {
+		if open {
+			c.recorder.Event(cr, event.Normal(reasonOpened, fmt.Sprintf("Maintenance window open until %s", until.UTC().Format(time.RFC3339))))
+		} else {
+			c.recorder.Event(cr, event.Normal(reasonClosed, fmt.Sprintf("Maintenance window closed until %s", next.UTC().Format(time.RFC3339))))
+		}
+	}
+	obs.Open = open
+	obs.OpenUntil = nil
+	if open {
+		u := metav1.NewTime(until)
+		obs.OpenUntil = &u
+	}
+	obs.NextOpen = nil
+	if !next.IsZero() {
+		n := metav1.NewTime(next)
+		obs.NextOpen = &n
+	}
+	obs.References = refs
+
+	cr.Status.SetConditions(xpv1.Available())
+	return managed.ExternalObservation{
+		ResourceExists:    true,
+		ResourceUpToDate:  true,
+		ConnectionDetails: managed.ConnectionDetails{},
+	}, nil
+}
+
+// Create is a no-op; Observe reports a MaintenanceWindow as existing from
+// the start.
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	return managed.ExternalCreation{
+		ConnectionDetails: managed.ConnectionDetails{},
+	}, nil
+}
+
+// Update is a no-op; Observe recomputes the window on every poll.
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	return managed.ExternalUpdate{
+		ConnectionDetails: managed.ConnectionDetails{},
+	}, nil
+}
+
+// Delete refuses to remove a window that Devices, Fleets or Sites still
+// reference.
+func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(*v1alpha1.MaintenanceWindow)
+	if !ok {
+		return errors.New(errNotMaintenanceWindow)
+	}
+
+	fmt.Printf("Deleting MaintenanceWindow: %+v", cr)
+
+	refs, err := c.references(ctx, cr.GetName())
+	if err != nil {
+		return err
+	}
+	if refs > 0 {
+		return errors.Errorf(errInUse, refs)
+	}
+	return nil
+}
@@ -0,0 +1,414 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	s4t "github.com/MIKE9708/s4t-sdk-go/pkg/api"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/connection"
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/provider-s4t/apis/iot/v1alpha1"
+	apisv1alpha1 "github.com/crossplane/provider-s4t/apis/v1alpha1"
+	"github.com/crossplane/provider-s4t/internal/diff"
+	"github.com/crossplane/provider-s4t/internal/features"
+	"github.com/crossplane/provider-s4t/internal/providerconfig"
+)
+
+const (
+	errNotNetwork   = "managed resource is not a Network custom resource"
+	errTrackPCUsage = "cannot track ProviderConfig usage"
+	errGetPC        = "cannot get ProviderConfig"
+	errGetCreds     = "cannot get credentials"
+	errNewClient    = "cannot create new Service"
+	errListPorts    = "cannot list Ports"
+	errInUse        = "network still has %d ports attached: %s"
+
+	reasonDrift event.Reason = "DriftDetected"
+)
+
+type S4TService struct {
+	S4tClient *s4t.Client
+	BaseURL   string
+	Token     string
+}
+
+var (
+	newS4TService = func(creds []byte, keystoneEndpoint string) (*S4TService, error) {
+		c, err := providerconfig.Connect(creds, keystoneEndpoint)
+		if err != nil {
+			return nil, errors.Wrap(err, errNewClient)
+		}
+		return &S4TService{S4tClient: c}, nil
+	}
+)
+
+// Setup adds a controller that reconciles Network managed resources.
+func Setup(mgr ctrl.Manager, o controller.Options) error {
+	name := managed.ControllerName(v1alpha1.NetworkGroupKind)
+
+	cps := []managed.ConnectionPublisher{managed.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme())}
+	if o.Features.Enabled(features.EnableAlphaExternalSecretStores) {
+		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), apisv1alpha1.StoreConfigGroupVersionKind))
+	}
+
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
+
+	r := managed.NewReconciler(mgr,
+		resource.ManagedKind(v1alpha1.NetworkGroupVersionKind),
+		managed.WithExternalConnecter(&connector{
+			kube:         mgr.GetClient(),
+			usage:        providerconfig.NewTracker(mgr.GetClient()),
+			recorder:     recorder,
+			newServiceFn: newS4TService}),
+		managed.WithLogger(o.Logger.WithValues("controller", name)),
+		managed.WithPollInterval(o.PollInterval),
+		managed.WithRecorder(recorder),
+		managed.WithConnectionPublishers(cps...))
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		For(&v1alpha1.Network{}, builder.WithPredicates(resource.DesiredStateChanged())).
+		Watches(&v1alpha1.Port{}, handler.EnqueueRequestsFromMapFunc(networkForPort)).
+		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+}
+
+// networkForPort maps a Port to the Network it references, so that the
+// Network's port count is refreshed and a pending deletion resumes once its
+// last Port is gone.
+func networkForPort(_ context.Context, obj client.Object) []reconcile.Request {
+	p, ok := obj.(*v1alpha1.Port)
+	if !ok || p.Spec.ForProvider.NetworkRef == nil {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: p.Spec.ForProvider.NetworkRef.Name}}}
+}
+
+type connector struct {
+	kube         client.Client
+	usage        resource.Tracker
+	recorder     event.Recorder
+	newServiceFn func(creds []byte, keystoneEndpoint string) (*S4TService, error)
+}
+
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	_, ok := mg.(*v1alpha1.Network)
+	if !ok {
+		return nil, errors.New(errNotNetwork)
+	}
+
+	if err := c.usage.Track(ctx, mg); err != nil {
+		return nil, errors.Wrap(err, errTrackPCUsage)
+	}
+
+	pc_domain, err := providerconfig.Resolve(ctx, c.kube, mg)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetPC)
+	}
+	cd_domain := pc_domain.Spec.Credentials
+	data_domain, err := resource.CommonCredentialExtractor(ctx, cd_domain.Source, c.kube, cd_domain.CommonCredentialSelectors)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetCreds)
+	}
+
+	svc, err := c.newServiceFn(data_domain, providerconfig.KeystoneEndpoint(pc_domain))
+	if err != nil {
+		return nil, errors.Wrap(err, errNewClient)
+	}
+	if err := providerconfig.Configure(svc.S4tClient, pc_domain); err != nil {
+		return nil, errors.Wrap(err, errNewClient)
+	}
+	return &external{kube: c.kube, service: svc, recorder: c.recorder}, err
+}
+
+// An ExternalClient observes, then either creates, updates, or deletes an
+// external resource to ensure it reflects the managed resource's desired state.
+type external struct {
+	// A 'client' used to connect to the external resource API. In practice this
+	// would be something like an AWS SDK client.
+	service  *S4TService
+	kube     client.Client
+	recorder event.Recorder
+}
+
+// makeRESTCall makes a REST API call to the IoTronic service
+func (c *external) makeRESTCall(method, path string, data interface{}) (*http.Response, error) {
+	// Build URL using the service client's endpoint
+	baseURL := fmt.Sprintf("%s:%s", c.service.S4tClient.Endpoint, c.service.S4tClient.Port)
+	url := fmt.Sprintf("%s/v1%s", baseURL, path)
+
+	var reqBody io.Reader
+	if data != nil {
+		jsonData, err := json.Marshal(data)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal request data")
+		}
+		reqBody = bytes.NewBuffer(jsonData)
+	}
+
+	req, err := http.NewRequest(method, url, reqBody)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create request")
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if c.service.S4tClient.AuthToken != "" {
+		req.Header.Set("X-Auth-Token", c.service.S4tClient.AuthToken)
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to execute request")
+	}
+
+	return resp, nil
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha1.Network)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotNetwork)
+	}
+
+	fmt.Printf("Observing Network: %+v", cr)
+
+	if cr.Spec.ForProvider.Uuid == "" {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	network, err := c.getNetwork(cr.Spec.ForProvider.Uuid)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+	if network == nil {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	d := diff.Compute(networkFields(cr.Spec.ForProvider), network)
+
+	ports, err := c.ports(ctx, cr)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+	cr.Status.AtProvider.Uuid = cr.Spec.ForProvider.Uuid
+	cr.Status.AtProvider.Ports = len(ports)
+	if id, ok := network["segmentation_id"].(float64); ok {
+		cr.Status.AtProvider.SegmentationID = int(id)
+	}
+
+	cr.Status.SetConditions(xpv1.Available())
+
+	return managed.ExternalObservation{
+		ResourceExists:    true,
+		ResourceUpToDate:  d.Empty(),
+		ConnectionDetails: managed.ConnectionDetails{},
+	}, nil
+}
+
+// getNetwork returns the network as IoTronic reports it, or nil if it does
+// not exist.
+// API: GET /v1/networks/{uuid}
+func (c *external) getNetwork(uuid string) (map[string]interface{}, error) {
+	resp, err := c.makeRESTCall("GET", fmt.Sprintf("/networks/%s", uuid), nil)
+	if err != nil {
+		log.Printf("Error getting network: %v", err)
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var network map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&network); err != nil {
+		return nil, errors.Wrap(err, "failed to decode response")
+	}
+	return network, nil
+}
+
+// networkFields maps the mutable NetworkParameters to IoTronic network
+// attributes. The others cannot be changed on an existing network and are
+// rejected on update by the CRD.
+func networkFields(p v1alpha1.NetworkParameters) []diff.Field {
+	return []diff.Field{
+		{Name: "name", Desired: p.Name},
+	}
+}
+
+// ports returns the names of the Ports attached to the network, either by
+// reference or by UUID.
+func (c *external) ports(ctx context.Context, cr *v1alpha1.Network) ([]string, error) {
+	l := &v1alpha1.PortList{}
+	if err := c.kube.List(ctx, l); err != nil {
+		return nil, errors.Wrap(err, errListPorts)
+	}
+	var names []string
+	for _, p := range l.Items {
+		ref := p.Spec.ForProvider.NetworkRef
+		if (ref != nil && ref.Name == cr.GetName()) || (cr.Spec.ForProvider.Uuid != "" && p.Spec.ForProvider.Network == cr.Spec.ForProvider.Uuid) {
+			names = append(names, p.GetName())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1alpha1.Network)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotNetwork)
+	}
+
+	fmt.Printf("Creating Network: %+v", cr)
+
+	networkData := map[string]interface{}{
+		"name": cr.Spec.ForProvider.Name,
+		"cidr": cr.Spec.ForProvider.CIDR,
+		"type": cr.Spec.ForProvider.Type,
+	}
+	if cr.Spec.ForProvider.SegmentationID != 0 {
+		networkData["segmentation_id"] = cr.Spec.ForProvider.SegmentationID
+	}
+	if cr.Spec.ForProvider.Project != "" {
+		networkData["project"] = cr.Spec.ForProvider.Project
+	}
+
+	resp, err := c.makeRESTCall("POST", "/networks", networkData)
+	if err != nil {
+		log.Printf("Error creating network: %v", err)
+		return managed.ExternalCreation{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		bodyBytes := make([]byte, 1024)
+		resp.Body.Read(bodyBytes)
+		return managed.ExternalCreation{}, fmt.Errorf("unexpected status code: %d, body: %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	var network map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&network); err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, "failed to decode response")
+	}
+
+	if uuid, ok := network["uuid"].(string); ok {
+		cr.Spec.ForProvider.Uuid = uuid
+		cr.Status.AtProvider.Uuid = uuid
+	}
+
+	return managed.ExternalCreation{
+		ConnectionDetails: managed.ConnectionDetails{},
+	}, nil
+}
+
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*v1alpha1.Network)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotNetwork)
+	}
+
+	fmt.Printf("Updating Network: %+v", cr)
+
+	observed, err := c.getNetwork(cr.Spec.ForProvider.Uuid)
+	if err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+	d := diff.Compute(networkFields(cr.Spec.ForProvider), observed)
+	if d.Empty() {
+		return managed.ExternalUpdate{ConnectionDetails: managed.ConnectionDetails{}}, nil
+	}
+	if c.recorder != nil {
+		c.recorder.Event(cr, event.Normal(reasonDrift, "Updating drifted fields: "+d.String()))
+	}
+
+	resp, err := c.makeRESTCall("PATCH", fmt.Sprintf("/networks/%s", cr.Spec.ForProvider.Uuid), d.Patch())
+	if err != nil {
+		log.Printf("Error updating network: %v", err)
+		return managed.ExternalUpdate{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return managed.ExternalUpdate{}, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	return managed.ExternalUpdate{
+		ConnectionDetails: managed.ConnectionDetails{},
+	}, nil
+}
+
+// Delete refuses to remove a network that Ports are still attached to; the
+// deletion resumes when the last Port is deleted.
+func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(*v1alpha1.Network)
+	if !ok {
+		return errors.New(errNotNetwork)
+	}
+
+	fmt.Printf("Deleting Network: %+v", cr)
+
+	ports, err := c.ports(ctx, cr)
+	if err != nil {
+		return err
+	}
+	if len(ports) > 0 {
+		cr.Status.AtProvider.Ports = len(ports)
+		return errors.Errorf(errInUse, len(ports), strings.Join(ports, ", "))
+	}
+
+	resp, err := c.makeRESTCall("DELETE", fmt.Sprintf("/networks/%s", cr.Spec.ForProvider.Uuid), nil)
+	if err != nil {
+		log.Printf("Error deleting network: %v", err)
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	return nil
+}
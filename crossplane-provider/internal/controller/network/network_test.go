@@ -0,0 +1,153 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	s4t "github.com/MIKE9708/s4t-sdk-go/pkg/api"
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/crossplane/provider-s4t/apis/iot/v1alpha1"
+)
+
+// iotronic serves the given networks, by UUID, as IoTronic would.
+func iotronic(t *testing.T, networks map[string]map[string]interface{}) *S4TService {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n, ok := networks[r.URL.Path[len("/v1/networks/"):]]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(n)
+	}))
+	t.Cleanup(srv.Close)
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &S4TService{S4tClient: &s4t.Client{Endpoint: u.Scheme + "://" + u.Hostname(), Port: u.Port()}}
+}
+
+func network(uuid string) *v1alpha1.Network {
+	n := &v1alpha1.Network{ObjectMeta: metav1.ObjectMeta{Name: "lan"}}
+	n.Spec.ForProvider = v1alpha1.NetworkParameters{Uuid: uuid, Name: "lan", CIDR: "10.0.0.0/24", Type: v1alpha1.NetworkTypeVXLAN}
+	return n
+}
+
+func newKube(t *testing.T, objs ...client.Object) client.Client {
+	t.Helper()
+	s := runtime.NewScheme()
+	if err := v1alpha1.SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	return fake.NewClientBuilder().WithScheme(s).WithObjects(objs...).Build()
+}
+
+func TestObserve(t *testing.T) {
+	attached := &v1alpha1.Port{ObjectMeta: metav1.ObjectMeta{Name: "eth0"}}
+	attached.Spec.ForProvider.NetworkRef = &xpv1.Reference{Name: "lan"}
+	byUUID := &v1alpha1.Port{ObjectMeta: metav1.ObjectMeta{Name: "eth1"}}
+	byUUID.Spec.ForProvider.Network = "net-1"
+	other := &v1alpha1.Port{ObjectMeta: metav1.ObjectMeta{Name: "eth2"}}
+	other.Spec.ForProvider.Network = "net-2"
+
+	svc := iotronic(t, map[string]map[string]interface{}{
+		"net-1": {"uuid": "net-1", "name": "lan", "cidr": "10.0.0.0/24", "type": "vxlan", "segmentation_id": 42},
+		"net-2": {"uuid": "net-2", "name": "renamed", "cidr": "10.0.0.0/24", "type": "vxlan"},
+		"net-3": {"uuid": "net-3", "name": "lan", "cidr": "10.9.0.0/24", "type": "vlan"},
+	})
+	kube := newKube(t, attached, byUUID, other)
+
+	type want struct {
+		o     managed.ExternalObservation
+		obs   v1alpha1.NetworkObservation
+		noErr bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		uuid   string
+		want   want
+	}{
+		"NotCreated": {
+			reason: "A Network without a UUID has not been created yet.",
+			want:   want{o: managed.ExternalObservation{ResourceExists: false}, noErr: true},
+		},
+		"Gone": {
+			reason: "A Network IoTronic does not know should be recreated.",
+			uuid:   "net-0",
+			want:   want{o: managed.ExternalObservation{ResourceExists: false}, noErr: true},
+		},
+		"UpToDate": {
+			reason: "A Network whose name matches should be up to date, and count the Ports attached by reference or UUID.",
+			uuid:   "net-1",
+			want: want{
+				o:     managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true, ConnectionDetails: managed.ConnectionDetails{}},
+				obs:   v1alpha1.NetworkObservation{Uuid: "net-1", SegmentationID: 42, Ports: 2},
+				noErr: true,
+			},
+		},
+		"Renamed": {
+			reason: "A Network renamed in IoTronic should be updated.",
+			uuid:   "net-2",
+			want: want{
+				o:     managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false, ConnectionDetails: managed.ConnectionDetails{}},
+				obs:   v1alpha1.NetworkObservation{Uuid: "net-2", Ports: 2},
+				noErr: true,
+			},
+		},
+		"ImmutableFields": {
+			reason: "Fields that cannot be patched, such as cidr and type, should not be reported as drift.",
+			uuid:   "net-3",
+			want: want{
+				o:     managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true, ConnectionDetails: managed.ConnectionDetails{}},
+				obs:   v1alpha1.NetworkObservation{Uuid: "net-3", Ports: 1},
+				noErr: true,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := network(tc.uuid)
+			e := &external{service: svc, kube: kube}
+			o, err := e.Observe(context.Background(), cr)
+			if (err == nil) != tc.want.noErr {
+				t.Fatalf("\n%s\ne.Observe(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.o, o); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.obs, cr.Status.AtProvider); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want status, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -21,9 +21,7 @@ import (
 	"fmt"
 	"log"
 
-	"encoding/json"
 	s4t "github.com/MIKE9708/s4t-sdk-go/pkg/api"
-	read_config "github.com/MIKE9708/s4t-sdk-go/pkg/read_conf"
 
 	plugins "github.com/MIKE9708/s4t-sdk-go/pkg/api/data/plugin"
 
@@ -37,11 +35,16 @@ import (
 	"github.com/crossplane/provider-s4t/apis/iot/v1alpha1"
 	apisv1alpha1 "github.com/crossplane/provider-s4t/apis/v1alpha1"
 	"github.com/crossplane/provider-s4t/internal/features"
+	"github.com/crossplane/provider-s4t/internal/params"
+	"github.com/crossplane/provider-s4t/internal/providerconfig"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 )
 
 const (
@@ -57,21 +60,12 @@ type S4TService struct {
 }
 
 var (
-	newS4TService = func(creds []byte) (*S4TService, error) {
-		var result map[string]string
-		err := json.Unmarshal(creds, &result)
+	newS4TService = func(creds []byte, keystoneEndpoint string) (*S4TService, error) {
+		c, err := providerconfig.Connect(creds, keystoneEndpoint)
 		if err != nil {
 			return nil, errors.Wrap(err, errNewClient)
 		}
-		auth_req := read_config.FormatAuthRequ(
-			result["username"],
-			result["password"],
-			result["domain"],
-		)
-		s4t_client, err := s4t.GetClientConnection(*auth_req)
-		return &S4TService{
-			S4tClient: s4t_client,
-		}, err
+		return &S4TService{S4tClient: c}, nil
 	}
 )
 
@@ -83,29 +77,43 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), apisv1alpha1.StoreConfigGroupVersionKind))
 	}
 
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
+
 	r := managed.NewReconciler(mgr,
 		resource.ManagedKind(v1alpha1.PluginGroupVersionKind),
 		managed.WithExternalConnecter(&connector{
 			kube:         mgr.GetClient(),
-			usage:        resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
+			usage:        providerconfig.NewTracker(mgr.GetClient()),
+			recorder:     recorder,
 			newServiceFn: newS4TService}),
 		managed.WithLogger(o.Logger.WithValues("controller", name)),
 		managed.WithPollInterval(o.PollInterval),
-		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		managed.WithRecorder(recorder),
 		managed.WithConnectionPublishers(cps...))
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
 		WithOptions(o.ForControllerRuntime()).
-		WithEventFilter(resource.DesiredStateChanged()).
-		For(&v1alpha1.Plugin{}).
+		For(&v1alpha1.Plugin{}, builder.WithPredicates(resource.DesiredStateChanged())).
+		Watches(&corev1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(params.Referencing(mgr.GetClient(), func() resource.ManagedList { return &v1alpha1.PluginList{} }, parametersFrom))).
 		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
 }
 
+// parametersFrom returns the Secrets a Plugin sets parameters from.
+func parametersFrom(mg resource.Managed) []v1alpha1.ParameterFrom {
+	cr, ok := mg.(*v1alpha1.Plugin)
+	if !ok {
+		return nil
+	}
+	return cr.Spec.ForProvider.ParametersFrom
+}
+
 type connector struct {
 	kube         client.Client
 	usage        resource.Tracker
-	newServiceFn func(creds []byte) (*S4TService, error)
+	recorder     event.Recorder
+	newServiceFn func(creds []byte, keystoneEndpoint string) (*S4TService, error)
 }
 
 func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
@@ -117,9 +125,8 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 	if err := c.usage.Track(ctx, mg); err != nil {
 		return nil, errors.Wrap(err, errTrackPCUsage)
 	}
-	// cr.GetProviderConfigReference().Name
-	pc_domain := &apisv1alpha1.ProviderConfig{}
-	if err := c.kube.Get(ctx, types.NamespacedName{Name: "s4t-provider-domain"}, pc_domain); err != nil {
+	pc_domain, err := providerconfig.Resolve(ctx, c.kube, mg)
+	if err != nil {
 		return nil, errors.Wrap(err, errGetPC)
 	}
 	cd_domain := pc_domain.Spec.Credentials
@@ -127,15 +134,24 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 	if err != nil {
 		return nil, errors.Wrap(err, errGetCreds)
 	}
-	svc, err := c.newServiceFn(data_domain)
+	svc, err := c.newServiceFn(data_domain, providerconfig.KeystoneEndpoint(pc_domain))
 	if err != nil {
 		return nil, errors.Wrap(err, errNewClient)
 	}
-	return &external{service: svc}, err
+	if err := providerconfig.Configure(svc.S4tClient, pc_domain); err != nil {
+		return nil, errors.Wrap(err, errNewClient)
+	}
+	return &external{kube: c.kube, service: svc, recorder: c.recorder}, err
 }
 
 type external struct {
-	service *S4TService
+	kube     client.Client
+	service  *S4TService
+	recorder event.Recorder
+
+	// rollout makes the IoTronic calls of a rollout. When nil, external
+	// makes them itself.
+	rollout rolloutClient
 }
 
 func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
@@ -154,19 +170,56 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	if plugin.UUID == "" {
 		return managed.ExternalObservation{ResourceExists: false}, nil
 	}
-	if cr.Spec.ForProvider.Name != plugin.Name {
-		return managed.ExternalObservation{ResourceUpToDate: false, ResourceExists: true}, nil
+
+	if _, err := c.parameters(ctx, cr); err != nil {
+		cr.Status.SetConditions(v1alpha1.InvalidParameters(err))
+		return managed.ExternalObservation{
+			ResourceExists:   true,
+			ResourceUpToDate: true,
+		}, nil
 	}
 
+	// The first time a plugin is observed, whatever IoTronic holds is taken
+	// as rolled out; only later changes are redeployed to boards.
+	rev, err := c.revision(ctx, cr)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+	if cr.Status.AtProvider.Rollout == nil {
+		cr.Status.AtProvider.Rollout = &v1alpha1.PluginRolloutStatus{
+			Revision:       rev,
+			StableRevision: rev,
+			Phase:          v1alpha1.RolloutCompleted,
+		}
+	}
+	ro := cr.Status.AtProvider.Rollout
+
 	cr.Status.SetConditions(xpv1.Available())
 
 	return managed.ExternalObservation{
-		ResourceExists:    true,
-		ResourceUpToDate:  true,
+		ResourceExists: true,
+		ResourceUpToDate: cr.Spec.ForProvider.Name == plugin.Name &&
+			ro.Revision == rev && ro.Phase != v1alpha1.RolloutProgressing,
 		ConnectionDetails: managed.ConnectionDetails{},
 	}, nil
 }
 
+// parameters returns the parameters of cr with the values of its Secrets
+// merged in, once they match its parameters schema, if any. They must not be
+// logged or recorded.
+func (c *external) parameters(ctx context.Context, cr *v1alpha1.Plugin) ([]byte, error) {
+	fp := cr.Spec.ForProvider
+	raw, err := params.Merge(ctx, c.kube, fp.Parameters.Raw, fp.ParametersFrom)
+	if err != nil || fp.ParametersSchema == nil {
+		return raw, err
+	}
+	schema, err := params.NewSchema(fp.ParametersSchema.Raw)
+	if err != nil {
+		return nil, err
+	}
+	return raw, schema.Validate(field.NewPath("spec", "forProvider", "parameters"), raw, fp.ParametersFrom).ToAggregate()
+}
+
 // Create creates a new plugin in IoTronic.
 // API: POST /v1/plugins
 // Request Body:
@@ -186,9 +239,13 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 	fmt.Printf("Creating: %+v", cr)
 
 	log.Printf("\n\n %s \n\n", cr.Spec.ForProvider.Parameters)
+	parameters, err := c.parameters(ctx, cr)
+	if err != nil {
+		return managed.ExternalCreation{}, err
+	}
 	req := plugins.PluginReq{
 		Name:       cr.Spec.ForProvider.Name,
-		Parameters: runtime.RawExtension{Raw: cr.Spec.ForProvider.Parameters.Raw},
+		Parameters: runtime.RawExtension{Raw: parameters},
 		Code:       cr.Spec.ForProvider.Code,
 	}
 
@@ -204,7 +261,9 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 	}, err
 }
 
-// Update updates an existing plugin in IoTronic.
+// Update updates an existing plugin in IoTronic and, when a rollout is
+// configured, redeploys a code or parameter change to the boards the plugin
+// is injected into one batch at a time.
 // API: PATCH /v1/plugins/{uuid}
 // Request Body: Partial plugin object (name, code, parameters)
 // Note: SDK method name has typo: PacthPlugin (should be PatchPlugin)
@@ -214,16 +273,58 @@ func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalUpdate{}, errors.New(errNotPlugin)
 	}
 	fmt.Printf("Updating: %+v", cr)
-	req := map[string]interface{}{
-		"name":       cr.Spec.ForProvider.Name,
-		"parameters": runtime.RawExtension{Raw: cr.Spec.ForProvider.Parameters.Raw},
-		"code":       cr.Spec.ForProvider.Code,
+
+	current, err := c.service.S4tClient.GetPlugin(cr.Spec.ForProvider.Uuid)
+	if err != nil {
+		log.Printf("####ERROR-LOG#### Error s4t client Plugin Get %q", err)
+		return managed.ExternalUpdate{}, err
 	}
-	// SDK method name typo: PacthPlugin instead of PatchPlugin
-	_, err := c.service.S4tClient.PacthPlugin(cr.Spec.ForProvider.Uuid, req)
+
+	parameters, err := c.parameters(ctx, cr)
 	if err != nil {
-		log.Printf("####ERROR-LOG#### Error s4t client Plugin Update %q", err)
-		return managed.ExternalUpdate{}, errors.New(errNewClient)
+		return managed.ExternalUpdate{}, err
+	}
+	rev, err := c.revision(ctx, cr)
+	if err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+	if cr.Status.AtProvider.Rollout == nil {
+		cr.Status.AtProvider.Rollout = &v1alpha1.PluginRolloutStatus{}
+	}
+	ro := cr.Status.AtProvider.Rollout
+	changed := ro.Revision != rev
+
+	if changed || current.Name != cr.Spec.ForProvider.Name {
+		req := map[string]interface{}{
+			"name":       cr.Spec.ForProvider.Name,
+			"parameters": runtime.RawExtension{Raw: parameters},
+			"code":       cr.Spec.ForProvider.Code,
+		}
+		// A halted rollout leaves the stable code in IoTronic; renaming the
+		// plugin must not push the broken revision back.
+		if !changed && (ro.Phase == v1alpha1.RolloutHalted || ro.Phase == v1alpha1.RolloutRolledBack) {
+			req = map[string]interface{}{"name": cr.Spec.ForProvider.Name}
+		}
+		// SDK method name typo: PacthPlugin instead of PatchPlugin
+		_, err = c.service.S4tClient.PacthPlugin(cr.Spec.ForProvider.Uuid, req)
+		if err != nil {
+			log.Printf("####ERROR-LOG#### Error s4t client Plugin Update %q", err)
+			return managed.ExternalUpdate{}, errors.New(errNewClient)
+		}
+	}
+
+	switch {
+	case changed && cr.Spec.ForProvider.Rollout != nil:
+		c.startRollout(cr, rev, current)
+		err = c.advanceRollout(ctx, cr)
+	case changed:
+		*ro = v1alpha1.PluginRolloutStatus{Revision: rev, StableRevision: rev, Phase: v1alpha1.RolloutCompleted}
+	case ro.Phase == v1alpha1.RolloutProgressing && cr.Spec.ForProvider.Rollout == nil:
+		ro.Phase = v1alpha1.RolloutHalted
+		ro.Batch = nil
+		ro.Message = "rollout configuration removed"
+	case ro.Phase == v1alpha1.RolloutProgressing:
+		err = c.advanceRollout(ctx, cr)
 	}
 
 	return managed.ExternalUpdate{
@@ -0,0 +1,478 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	plugins "github.com/MIKE9708/s4t-sdk-go/pkg/api/data/plugin"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/provider-s4t/apis/iot/v1alpha1"
+	"github.com/crossplane/provider-s4t/internal/maintenance"
+	"github.com/crossplane/provider-s4t/internal/params"
+)
+
+const (
+	errListInjections = "cannot list BoardPluginInjections"
+	errListDevices    = "cannot list Devices"
+	errCanarySelector = "cannot parse canary selector"
+
+	// pluginStatusRunning is what IoTronic reports for an injected plugin
+	// that Lightning Rod is running.
+	pluginStatusRunning = "running"
+
+	defaultHealthTimeout = 5 * time.Minute
+
+	reasonRolloutStarted    event.Reason = "RolloutStarted"
+	reasonRolloutBatch      event.Reason = "RolloutBatch"
+	reasonRolloutCompleted  event.Reason = "RolloutCompleted"
+	reasonRolloutHalted     event.Reason = "RolloutHalted"
+	reasonRolloutRolledBack event.Reason = "RolloutRolledBack"
+)
+
+// pluginRevision identifies the code and parameters a plugin is deployed
+// with. parameters are those of the spec, without the values of Secrets;
+// versions identify those Secrets, so that a change to one of them is a new
+// revision without the revision covering secret values.
+func pluginRevision(code string, parameters, versions []byte) string {
+	h := sha256.New()
+	h.Write([]byte(code))
+	h.Write([]byte{0})
+	h.Write(parameters)
+	if len(versions) > 0 {
+		h.Write([]byte{0})
+		h.Write(versions)
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// revision returns the pluginRevision of the spec of cr.
+func (c *external) revision(ctx context.Context, cr *v1alpha1.Plugin) (string, error) {
+	versions, err := params.Versions(ctx, c.kube, cr.Spec.ForProvider.ParametersFrom)
+	if err != nil {
+		return "", err
+	}
+	return pluginRevision(cr.Spec.ForProvider.Code, cr.Spec.ForProvider.Parameters.Raw, versions), nil
+}
+
+// A rolloutClient makes the IoTronic calls of a rollout.
+type rolloutClient interface {
+	// redeploy re-injects the plugin into a board and starts it again.
+	redeploy(ctx context.Context, cr *v1alpha1.Plugin, board string) error
+
+	// healthy reports whether a redeployed board passes the health gate.
+	healthy(ctx context.Context, cr *v1alpha1.Plugin, board string) (bool, error)
+
+	// restore patches code and parameters back into the plugin.
+	restore(cr *v1alpha1.Plugin, code string, parameters []byte) error
+}
+
+func (c *external) boards() rolloutClient {
+	if c.rollout != nil {
+		return c.rollout
+	}
+	return c
+}
+
+// startRollout records the start of a rollout of rev. current is the plugin
+// as IoTronic held it before the update, kept to roll back to.
+func (c *external) startRollout(cr *v1alpha1.Plugin, rev string, current *plugins.Plugin) {
+	ro := cr.Status.AtProvider.Rollout
+	// StableCode is only cleared once a rollout completes; until then it
+	// still holds the last good code even if a newer revision is pushed.
+	if ro.StableCode == "" {
+		ro.StableCode = current.Code
+		// Parameters set from Secrets are not recorded; a rollback sets
+		// them from the Secrets again.
+		ro.StableParameters = runtime.RawExtension{Raw: params.Strip(current.Parameters.Raw, cr.Spec.ForProvider.ParametersFrom)}
+	}
+	stable := ro.StableRevision
+	if stable == "" {
+		stable = ro.Revision
+	}
+	*ro = v1alpha1.PluginRolloutStatus{
+		Revision:         rev,
+		StableRevision:   stable,
+		StableCode:       ro.StableCode,
+		StableParameters: ro.StableParameters,
+		Phase:            v1alpha1.RolloutProgressing,
+	}
+	c.event(cr, event.Normal(reasonRolloutStarted, fmt.Sprintf("Rolling out revision %s", rev)))
+}
+
+// advanceRollout takes the next step of a progressing rollout: it checks the
+// health of the batch in flight, halts if too many boards failed, waits out
+// the pause between batches, then redeploys the next batch. Canaries go
+// first, as a batch of their own.
+func (c *external) advanceRollout(ctx context.Context, cr *v1alpha1.Plugin) error {
+	ro := cr.Status.AtProvider.Rollout
+	spec := cr.Spec.ForProvider.Rollout
+	now := metav1.Now()
+
+	if len(ro.Batch) > 0 {
+		timeout := defaultHealthTimeout
+		if spec.HealthCheck.Timeout != nil {
+			timeout = spec.HealthCheck.Timeout.Duration
+		}
+		var pending []string
+		for _, b := range ro.Batch {
+			ok, err := c.boards().healthy(ctx, cr, b)
+			switch {
+			case ok:
+				ro.Updated = append(ro.Updated, b)
+			case ro.BatchStartTime == nil || now.Sub(ro.BatchStartTime.Time) >= timeout:
+				log.Printf("Plugin %s: board %s failed the health gate: %v", cr.Spec.ForProvider.Uuid, b, err)
+				ro.Failed = append(ro.Failed, b)
+			default:
+				pending = append(pending, b)
+			}
+		}
+		ro.Batch = pending
+		if len(ro.Failed) > spec.MaxUnavailable {
+			return c.haltRollout(ctx, cr, fmt.Sprintf("%d boards failed the health gate", len(ro.Failed)))
+		}
+		if len(pending) > 0 {
+			ro.Message = fmt.Sprintf("waiting for %d boards to pass the health gate", len(pending))
+			return nil
+		}
+		ro.BatchStartTime = nil
+		if spec.Pause != nil {
+			next := metav1.NewTime(now.Add(spec.Pause.Duration))
+			ro.NextBatchTime = &next
+		}
+	}
+
+	if ro.NextBatchTime != nil && now.Before(ro.NextBatchTime) {
+		ro.Message = fmt.Sprintf("paused until %s", ro.NextBatchTime.Format(time.RFC3339))
+		return nil
+	}
+	ro.NextBatchTime = nil
+
+	canaries, others, err := c.rolloutTargets(ctx, cr)
+	if err != nil {
+		return err
+	}
+	done := map[string]bool{}
+	for _, b := range append(append([]string{}, ro.Updated...), ro.Failed...) {
+		done[b] = true
+	}
+	batch, size := remaining(canaries, done), 0
+	if len(batch) == 0 {
+		batch = remaining(others, done)
+		size = spec.BatchSize
+		if size < 1 {
+			size = 1
+		}
+	}
+
+	if len(batch) == 0 {
+		ro.Phase = v1alpha1.RolloutCompleted
+		ro.StableRevision = ro.Revision
+		ro.StableCode = ""
+		ro.StableParameters = runtime.RawExtension{}
+		ro.Message = fmt.Sprintf("%d boards updated", len(ro.Updated))
+		c.event(cr, event.Normal(reasonRolloutCompleted, fmt.Sprintf("Revision %s rolled out to %d boards", ro.Revision, len(ro.Updated))))
+		return nil
+	}
+
+	// Boards outside their maintenance window are left for a later batch.
+	batch, waiting, err := c.inWindow(ctx, cr, batch)
+	if err != nil {
+		return err
+	}
+	if len(batch) == 0 {
+		ro.Message = fmt.Sprintf("waiting for the maintenance window of %d boards", waiting)
+		return nil
+	}
+	if size > 0 && len(batch) > size {
+		batch = batch[:size]
+	}
+
+	for _, b := range batch {
+		if err := c.boards().redeploy(ctx, cr, b); err != nil {
+			log.Printf("Plugin %s: cannot redeploy to board %s: %v", cr.Spec.ForProvider.Uuid, b, err)
+			ro.Failed = append(ro.Failed, b)
+			continue
+		}
+		ro.Batch = append(ro.Batch, b)
+	}
+	ro.BatchStartTime = &now
+	if len(ro.Failed) > spec.MaxUnavailable {
+		return c.haltRollout(ctx, cr, fmt.Sprintf("%d boards could not be redeployed", len(ro.Failed)))
+	}
+	ro.Message = fmt.Sprintf("redeployed %d boards, %d of %d done", len(ro.Batch), len(ro.Updated), len(canaries)+len(others))
+	c.event(cr, event.Normal(reasonRolloutBatch, fmt.Sprintf("Redeployed revision %s to boards %s", ro.Revision, strings.Join(ro.Batch, ", "))))
+	return nil
+}
+
+// haltRollout stops a rollout and, unless disabled, restores the stable code
+// and parameters in IoTronic and on every board the rollout touched.
+func (c *external) haltRollout(ctx context.Context, cr *v1alpha1.Plugin, reason string) error {
+	ro := cr.Status.AtProvider.Rollout
+	touched := append(append(append([]string{}, ro.Updated...), ro.Batch...), ro.Failed...)
+	ro.Phase = v1alpha1.RolloutHalted
+	ro.Batch = nil
+	ro.BatchStartTime = nil
+	ro.NextBatchTime = nil
+	ro.Message = reason
+	c.event(cr, event.Warning(reasonRolloutHalted, errors.Errorf("rollout of revision %s halted: %s", ro.Revision, reason)))
+
+	if ar := cr.Spec.ForProvider.Rollout.AutoRollback; (ar != nil && !*ar) || ro.StableCode == "" {
+		return nil
+	}
+
+	stable, err := params.Merge(ctx, c.kube, ro.StableParameters.Raw, cr.Spec.ForProvider.ParametersFrom)
+	if err != nil {
+		return errors.Wrap(err, "cannot restore stable plugin parameters")
+	}
+	if err := c.boards().restore(cr, ro.StableCode, stable); err != nil {
+		return errors.Wrap(err, "cannot restore stable plugin code")
+	}
+	var failed []string
+	for _, b := range touched {
+		if err := c.boards().redeploy(ctx, cr, b); err != nil {
+			log.Printf("Plugin %s: cannot roll back board %s: %v", cr.Spec.ForProvider.Uuid, b, err)
+			failed = append(failed, b)
+		}
+	}
+	ro.Phase = v1alpha1.RolloutRolledBack
+	ro.Message = fmt.Sprintf("%s; rolled back to revision %s", reason, ro.StableRevision)
+	if len(failed) > 0 {
+		ro.Message += fmt.Sprintf(", boards %s could not be rolled back", strings.Join(failed, ", "))
+	}
+	c.event(cr, event.Warning(reasonRolloutRolledBack, errors.New(ro.Message)))
+	return nil
+}
+
+// rolloutTargets returns the UUIDs of the boards the plugin is injected into,
+// split into the canaries and the rest, each sorted.
+func (c *external) rolloutTargets(ctx context.Context, cr *v1alpha1.Plugin) ([]string, []string, error) {
+	il := &v1alpha1.BoardPluginInjectionList{}
+	if err := c.kube.List(ctx, il); err != nil {
+		return nil, nil, errors.Wrap(err, errListInjections)
+	}
+
+	canary := func(string) bool { return false }
+	if s := cr.Spec.ForProvider.Rollout.CanarySelector; s != nil {
+		sel, err := metav1.LabelSelectorAsSelector(s)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, errCanarySelector)
+		}
+		dl := &v1alpha1.DeviceList{}
+		if err := c.kube.List(ctx, dl); err != nil {
+			return nil, nil, errors.Wrap(err, errListDevices)
+		}
+		canaries := map[string]bool{}
+		for _, d := range dl.Items {
+			if d.Spec.ForProvider.Uuid != "" && sel.Matches(labels.Set(d.GetLabels())) {
+				canaries[d.Spec.ForProvider.Uuid] = true
+			}
+		}
+		canary = func(b string) bool { return canaries[b] }
+	}
+
+	seen := map[string]bool{}
+	var canaries, others []string
+	for _, i := range il.Items {
+		b := i.Spec.ForProvider.BoardUuid
+		if meta.WasDeleted(&i) || b == "" || seen[b] || i.Spec.ForProvider.PluginUuid != cr.Spec.ForProvider.Uuid {
+			continue
+		}
+		seen[b] = true
+		if canary(b) {
+			canaries = append(canaries, b)
+		} else {
+			others = append(others, b)
+		}
+	}
+	sort.Strings(canaries)
+	sort.Strings(others)
+	return canaries, others, nil
+}
+
+// inWindow returns the boards inside their maintenance window, and counts
+// the others.
+func (c *external) inWindow(ctx context.Context, cr *v1alpha1.Plugin, boards []string) ([]string, int, error) {
+	var open []string
+	for _, b := range boards {
+		deferred, err := maintenance.Check(ctx, c.kube, cr, b)
+		if err != nil {
+			return nil, 0, err
+		}
+		if deferred == nil {
+			open = append(open, b)
+		}
+	}
+	return open, len(boards) - len(open), nil
+}
+
+func remaining(boards []string, done map[string]bool) []string {
+	var r []string
+	for _, b := range boards {
+		if !done[b] {
+			r = append(r, b)
+		}
+	}
+	return r
+}
+
+// restore patches code and parameters back into the plugin.
+// API: PATCH /v1/plugins/{uuid}
+func (c *external) restore(cr *v1alpha1.Plugin, code string, parameters []byte) error {
+	req := map[string]interface{}{
+		"name":       cr.Spec.ForProvider.Name,
+		"parameters": runtime.RawExtension{Raw: parameters},
+		"code":       code,
+	}
+	if _, err := c.service.S4tClient.PacthPlugin(cr.Spec.ForProvider.Uuid, req); err != nil {
+		log.Printf("####ERROR-LOG#### Error s4t client Plugin Rollback %q", err)
+		return err
+	}
+	return nil
+}
+
+// redeploy re-injects the plugin into a board, replacing the code it runs,
+// and starts it again.
+// API: PUT /v1/boards/{board_uuid}/plugins/  {"plugin": uuid, "force": true}
+// API: POST /v1/boards/{board_uuid}/plugins/{plugin_uuid}  {"action": "PluginStart"}
+func (c *external) redeploy(ctx context.Context, cr *v1alpha1.Plugin, board string) error {
+	err := c.service.S4tClient.InjectPLuginBoard(board, map[string]interface{}{
+		"plugin": cr.Spec.ForProvider.Uuid,
+		"force":  true,
+	})
+	if err != nil {
+		log.Printf("####ERROR-LOG#### Error s4t client BoardPlugin Inject %q", err)
+		return err
+	}
+	_, err = c.pluginAction(board, cr.Spec.ForProvider.Uuid, "PluginStart", nil)
+	return err
+}
+
+// healthy reports whether a redeployed board passes the health gate.
+func (c *external) healthy(ctx context.Context, cr *v1alpha1.Plugin, board string) (bool, error) {
+	if call := cr.Spec.ForProvider.Rollout.HealthCheck.Call; call != nil {
+		var params interface{}
+		if len(call.Parameters.Raw) > 0 {
+			if err := json.Unmarshal(call.Parameters.Raw, &params); err != nil {
+				return false, errors.Wrap(err, "cannot decode health check parameters")
+			}
+		}
+		out, err := c.pluginAction(board, cr.Spec.ForProvider.Uuid, "PluginCall", params)
+		if err != nil {
+			return false, err
+		}
+		if !strings.Contains(out, call.ExpectedResult) {
+			return false, errors.Errorf("plugin call returned %q", out)
+		}
+		return true, nil
+	}
+
+	injected, err := c.service.S4tClient.GetBoardPlugins(board)
+	if err != nil {
+		return false, err
+	}
+	for _, p := range injected {
+		if p.Plugin == cr.Spec.ForProvider.Uuid {
+			if p.Status == pluginStatusRunning {
+				return true, nil
+			}
+			return false, errors.Errorf("plugin is %q", p.Status)
+		}
+	}
+	return false, errors.New("plugin is not injected")
+}
+
+// pluginAction performs an action on a plugin injected into a board and
+// returns the response body.
+// API: POST /v1/boards/{board_uuid}/plugins/{plugin_uuid}
+// Request Body: {"action": "PluginStart|PluginCall|...", "parameters": {...}}
+func (c *external) pluginAction(board, plugin, action string, params interface{}) (string, error) {
+	data := map[string]interface{}{"action": action}
+	if params != nil {
+		data["parameters"] = params
+	}
+	resp, err := c.makeRESTCall("POST", fmt.Sprintf("/boards/%s/plugins/%s", board, plugin), data)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to read response")
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
+		return "", fmt.Errorf("unexpected status code: %d, body: %s", resp.StatusCode, string(body))
+	}
+	return string(body), nil
+}
+
+// makeRESTCall makes a REST API call to the IoTronic service
+func (c *external) makeRESTCall(method, path string, data interface{}) (*http.Response, error) {
+	baseURL := fmt.Sprintf("%s:%s", c.service.S4tClient.Endpoint, c.service.S4tClient.Port)
+	url := fmt.Sprintf("%s/v1%s", baseURL, path)
+
+	var reqBody io.Reader
+	if data != nil {
+		jsonData, err := json.Marshal(data)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal request data")
+		}
+		reqBody = bytes.NewBuffer(jsonData)
+	}
+
+	req, err := http.NewRequest(method, url, reqBody)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create request")
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if c.service.S4tClient.AuthToken != "" {
+		req.Header.Set("X-Auth-Token", c.service.S4tClient.AuthToken)
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to execute request")
+	}
+
+	return resp, nil
+}
+
+func (c *external) event(cr *v1alpha1.Plugin, e event.Event) {
+	if c.recorder != nil {
+		c.recorder.Event(cr, e)
+	}
+}
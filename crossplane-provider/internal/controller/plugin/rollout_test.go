@@ -0,0 +1,336 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	plugins "github.com/MIKE9708/s4t-sdk-go/pkg/api/data/plugin"
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/crossplane/provider-s4t/apis/iot/v1alpha1"
+)
+
+// fakeRollout records the IoTronic calls of a rollout.
+type fakeRollout struct {
+	health     map[string]bool
+	redeployed []string
+	code       string
+	parameters string
+}
+
+func (f *fakeRollout) redeploy(_ context.Context, _ *v1alpha1.Plugin, board string) error {
+	f.redeployed = append(f.redeployed, board)
+	return nil
+}
+
+func (f *fakeRollout) healthy(_ context.Context, _ *v1alpha1.Plugin, board string) (bool, error) {
+	if f.health[board] {
+		return true, nil
+	}
+	return false, errors.New("plugin is \"failed\"")
+}
+
+func (f *fakeRollout) restore(_ *v1alpha1.Plugin, code string, parameters []byte) error {
+	f.code, f.parameters = code, string(parameters)
+	return nil
+}
+
+func newKube(t *testing.T, objs ...client.Object) client.Client {
+	t.Helper()
+	s := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(s); err != nil {
+		t.Fatalf("AddToScheme: %v", err)
+	}
+	if err := v1alpha1.SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatalf("AddToScheme: %v", err)
+	}
+	return fake.NewClientBuilder().WithScheme(s).WithObjects(objs...).Build()
+}
+
+// injected returns a BoardPluginInjection of plugin p1 into board.
+func injected(board string) *v1alpha1.BoardPluginInjection {
+	i := &v1alpha1.BoardPluginInjection{ObjectMeta: metav1.ObjectMeta{Name: "p1-" + board}}
+	i.Spec.ForProvider.PluginUuid = "p1"
+	i.Spec.ForProvider.BoardUuid = board
+	return i
+}
+
+// board returns the Device of board, with labels.
+func board(uuid string, labels map[string]string) *v1alpha1.Device {
+	d := &v1alpha1.Device{ObjectMeta: metav1.ObjectMeta{Name: "dev-" + uuid, Labels: labels}}
+	d.Spec.ForProvider.Uuid = uuid
+	return d
+}
+
+func rollingPlugin(spec v1alpha1.PluginRollout, ro v1alpha1.PluginRolloutStatus) *v1alpha1.Plugin {
+	cr := &v1alpha1.Plugin{ObjectMeta: metav1.ObjectMeta{Name: "collector"}}
+	cr.Spec.ForProvider.Uuid = "p1"
+	cr.Spec.ForProvider.Rollout = &spec
+	ro.Revision = "r2"
+	ro.StableRevision = "r1"
+	ro.StableCode = "stable"
+	ro.StableParameters = runtime.RawExtension{Raw: []byte(`{"interval":5}`)}
+	ro.Phase = v1alpha1.RolloutProgressing
+	cr.Status.AtProvider.Rollout = &ro
+	return cr
+}
+
+func TestAdvanceRollout(t *testing.T) {
+	now := metav1.Now()
+	started := metav1.NewTime(now.Add(-10 * time.Minute))
+	closed := &v1alpha1.MaintenanceWindow{ObjectMeta: metav1.ObjectMeta{Name: "closed"}}
+	// Opens for a minute at midnight on January 1st.
+	closed.Spec.ForProvider.Schedules = []v1alpha1.MaintenanceWindowSchedule{{Start: "0 0 1 1 *", Duration: metav1.Duration{Duration: time.Minute}}}
+	gated := board("b1", nil)
+	gated.Spec.ForProvider.MaintenanceWindowRef = &xpv1.Reference{Name: closed.GetName()}
+	all := []client.Object{injected("b1"), injected("b2"), injected("b3")}
+
+	type want struct {
+		phase      string
+		batch      []string
+		updated    []string
+		failed     []string
+		paused     bool
+		redeployed []string
+		restored   string
+		message    string
+	}
+	cases := map[string]struct {
+		objs    []client.Object
+		spec    v1alpha1.PluginRollout
+		status  v1alpha1.PluginRolloutStatus
+		healthy map[string]bool
+		want    want
+	}{
+		"CanariesFirst": {
+			objs: append([]client.Object{board("b3", map[string]string{"canary": "true"})}, all...),
+			spec: v1alpha1.PluginRollout{BatchSize: 2, CanarySelector: &metav1.LabelSelector{MatchLabels: map[string]string{"canary": "true"}}},
+			want: want{phase: v1alpha1.RolloutProgressing, batch: []string{"b3"}, redeployed: []string{"b3"}},
+		},
+		"FirstBatch": {
+			objs: all,
+			spec: v1alpha1.PluginRollout{BatchSize: 2},
+			want: want{phase: v1alpha1.RolloutProgressing, batch: []string{"b1", "b2"}, redeployed: []string{"b1", "b2"}},
+		},
+		"HealthGatePassed": {
+			objs:    all,
+			spec:    v1alpha1.PluginRollout{BatchSize: 2},
+			status:  v1alpha1.PluginRolloutStatus{Batch: []string{"b1", "b2"}, BatchStartTime: &now},
+			healthy: map[string]bool{"b1": true, "b2": true},
+			want:    want{phase: v1alpha1.RolloutProgressing, batch: []string{"b3"}, updated: []string{"b1", "b2"}, redeployed: []string{"b3"}},
+		},
+		"HealthGatePending": {
+			objs:    all,
+			spec:    v1alpha1.PluginRollout{BatchSize: 2},
+			status:  v1alpha1.PluginRolloutStatus{Batch: []string{"b1", "b2"}, BatchStartTime: &now},
+			healthy: map[string]bool{"b1": true},
+			want:    want{phase: v1alpha1.RolloutProgressing, batch: []string{"b2"}, updated: []string{"b1"}, message: "waiting for 1 boards"},
+		},
+		"Paused": {
+			objs:    all,
+			spec:    v1alpha1.PluginRollout{Pause: &metav1.Duration{Duration: time.Hour}},
+			status:  v1alpha1.PluginRolloutStatus{Batch: []string{"b1"}, BatchStartTime: &now},
+			healthy: map[string]bool{"b1": true},
+			want:    want{phase: v1alpha1.RolloutProgressing, updated: []string{"b1"}, paused: true, message: "paused until"},
+		},
+		"Completed": {
+			objs:    all,
+			spec:    v1alpha1.PluginRollout{},
+			status:  v1alpha1.PluginRolloutStatus{Batch: []string{"b3"}, BatchStartTime: &now, Updated: []string{"b1", "b2"}},
+			healthy: map[string]bool{"b3": true},
+			want:    want{phase: v1alpha1.RolloutCompleted, updated: []string{"b1", "b2", "b3"}, message: "3 boards updated"},
+		},
+		"FailedWithinBudget": {
+			objs:   all,
+			spec:   v1alpha1.PluginRollout{MaxUnavailable: 1},
+			status: v1alpha1.PluginRolloutStatus{Batch: []string{"b1"}, BatchStartTime: &started},
+			want:   want{phase: v1alpha1.RolloutProgressing, batch: []string{"b2"}, failed: []string{"b1"}, redeployed: []string{"b2"}},
+		},
+		"RolledBack": {
+			objs:    all,
+			spec:    v1alpha1.PluginRollout{},
+			status:  v1alpha1.PluginRolloutStatus{Batch: []string{"b2"}, BatchStartTime: &started, Updated: []string{"b1"}},
+			healthy: map[string]bool{"b1": true},
+			want: want{
+				phase:      v1alpha1.RolloutRolledBack,
+				updated:    []string{"b1"},
+				failed:     []string{"b2"},
+				redeployed: []string{"b1", "b2"},
+				restored:   `stable {"interval":5}`,
+				message:    "rolled back to revision r1",
+			},
+		},
+		"HaltedWithoutRollback": {
+			objs:   all,
+			spec:   v1alpha1.PluginRollout{AutoRollback: ptr.To(false)},
+			status: v1alpha1.PluginRolloutStatus{Batch: []string{"b1"}, BatchStartTime: &started},
+			want:   want{phase: v1alpha1.RolloutHalted, failed: []string{"b1"}, message: "1 boards failed the health gate"},
+		},
+		"MaintenanceWindowClosed": {
+			objs:   []client.Object{closed, gated, injected("b1")},
+			spec:   v1alpha1.PluginRollout{},
+			status: v1alpha1.PluginRolloutStatus{},
+			want:   want{phase: v1alpha1.RolloutProgressing, message: "waiting for the maintenance window of 1 boards"},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := rollingPlugin(tc.spec, tc.status)
+			f := &fakeRollout{health: tc.healthy}
+			e := &external{kube: newKube(t, tc.objs...), rollout: f}
+			if err := e.advanceRollout(context.Background(), cr); err != nil {
+				t.Fatalf("advanceRollout: %v", err)
+			}
+			ro := cr.Status.AtProvider.Rollout
+			got := want{
+				phase:      ro.Phase,
+				batch:      ro.Batch,
+				updated:    ro.Updated,
+				failed:     ro.Failed,
+				paused:     ro.NextBatchTime != nil,
+				redeployed: f.redeployed,
+			}
+			if f.code != "" {
+				got.restored = f.code + " " + f.parameters
+			}
+			if !strings.Contains(ro.Message, tc.want.message) {
+				t.Errorf("advanceRollout: message %q does not contain %q", ro.Message, tc.want.message)
+			}
+			got.message = tc.want.message
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{}), cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("advanceRollout: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestStartRollout(t *testing.T) {
+	cr := &v1alpha1.Plugin{}
+	cr.Spec.ForProvider.ParametersFrom = []v1alpha1.ParameterFrom{{
+		Name: "apiKey",
+		SecretKeyRef: xpv1.SecretKeySelector{
+			SecretReference: xpv1.SecretReference{Namespace: "iot", Name: "cloud"},
+			Key:             "apiKey",
+		},
+	}}
+	cr.Status.AtProvider.Rollout = &v1alpha1.PluginRolloutStatus{Revision: "r1", StableRevision: "r1", Phase: v1alpha1.RolloutCompleted}
+	e := &external{}
+
+	e.startRollout(cr, "r2", &plugins.Plugin{Code: "v1", Parameters: runtime.RawExtension{Raw: []byte(`{"apiKey":"s3cr3t","interval":5}`)}})
+	want := &v1alpha1.PluginRolloutStatus{
+		Revision:         "r2",
+		StableRevision:   "r1",
+		StableCode:       "v1",
+		StableParameters: runtime.RawExtension{Raw: []byte(`{"interval":5}`)},
+		Phase:            v1alpha1.RolloutProgressing,
+	}
+	if diff := cmp.Diff(want, cr.Status.AtProvider.Rollout); diff != "" {
+		t.Errorf("startRollout: -want, +got:\n%s", diff)
+	}
+
+	// A newer revision pushed mid-rollout still rolls back to the last good
+	// code, not to the revision it interrupts.
+	e.startRollout(cr, "r3", &plugins.Plugin{Code: "v2", Parameters: runtime.RawExtension{Raw: []byte(`{"interval":6}`)}})
+	want.Revision = "r3"
+	if diff := cmp.Diff(want, cr.Status.AtProvider.Rollout); diff != "" {
+		t.Errorf("startRollout: -want, +got:\n%s", diff)
+	}
+}
+
+func TestHaltRolloutRestoresSecrets(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "iot", Name: "cloud", Labels: map[string]string{v1alpha1.LabelParameterSource: "true"}},
+		Data:       map[string][]byte{"apiKey": []byte("s3cr3t")},
+	}
+	cr := rollingPlugin(v1alpha1.PluginRollout{}, v1alpha1.PluginRolloutStatus{Updated: []string{"b1"}})
+	cr.Spec.ForProvider.ParametersFrom = []v1alpha1.ParameterFrom{{
+		Name: "apiKey",
+		SecretKeyRef: xpv1.SecretKeySelector{
+			SecretReference: xpv1.SecretReference{Namespace: "iot", Name: "cloud"},
+			Key:             "apiKey",
+		},
+	}}
+	f := &fakeRollout{}
+	e := &external{kube: newKube(t, secret), rollout: f}
+	if err := e.haltRollout(context.Background(), cr, "halted"); err != nil {
+		t.Fatalf("haltRollout: %v", err)
+	}
+	if diff := cmp.Diff(`stable {"apiKey":"s3cr3t","interval":5}`, f.code+" "+f.parameters); diff != "" {
+		t.Errorf("haltRollout: restored -want, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"b1"}, f.redeployed); diff != "" {
+		t.Errorf("haltRollout: redeployed -want, +got:\n%s", diff)
+	}
+	if cr.Status.AtProvider.Rollout.Phase != v1alpha1.RolloutRolledBack {
+		t.Errorf("haltRollout: want phase %s, got %s", v1alpha1.RolloutRolledBack, cr.Status.AtProvider.Rollout.Phase)
+	}
+}
+
+func TestRevision(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "iot", Name: "cloud", UID: "cloud-uid", Labels: map[string]string{v1alpha1.LabelParameterSource: "true"}},
+		Data:       map[string][]byte{"apiKey": []byte("1234")},
+	}
+	cr := &v1alpha1.Plugin{}
+	cr.Spec.ForProvider.Code = "v1"
+	cr.Spec.ForProvider.Parameters = runtime.RawExtension{Raw: []byte(`{"interval":5}`)}
+	cr.Spec.ForProvider.ParametersFrom = []v1alpha1.ParameterFrom{{
+		Name: "apiKey",
+		SecretKeyRef: xpv1.SecretKeySelector{
+			SecretReference: xpv1.SecretReference{Namespace: "iot", Name: "cloud"},
+			Key:             "apiKey",
+		},
+	}}
+	kube := newKube(t, secret)
+	e := &external{kube: kube}
+
+	rev, err := e.revision(context.Background(), cr)
+	if err != nil {
+		t.Fatalf("revision: %v", err)
+	}
+	// The revision is recorded in the status, so it must not be a hash of
+	// the secret value that could be brute-forced.
+	if merged := pluginRevision("v1", []byte(`{"apiKey":"1234","interval":5}`), nil); rev == merged {
+		t.Error("revision: covers the merged secret value")
+	}
+
+	secret.Data["apiKey"] = []byte("5678")
+	if err := kube.Update(context.Background(), secret); err != nil {
+		t.Fatal(err)
+	}
+	changed, err := e.revision(context.Background(), cr)
+	if err != nil {
+		t.Fatalf("revision: %v", err)
+	}
+	if changed == rev {
+		t.Error("revision: want a change to the Secret to be a new revision")
+	}
+}
@@ -0,0 +1,722 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pluginschedule
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	s4t "github.com/MIKE9708/s4t-sdk-go/pkg/api"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/connection"
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/provider-s4t/apis/iot/v1alpha1"
+	apisv1alpha1 "github.com/crossplane/provider-s4t/apis/v1alpha1"
+	"github.com/crossplane/provider-s4t/internal/features"
+	"github.com/crossplane/provider-s4t/internal/maintenance"
+	"github.com/crossplane/provider-s4t/internal/params"
+	"github.com/crossplane/provider-s4t/internal/providerconfig"
+)
+
+const (
+	errNotPluginSchedule = "managed resource is not a PluginSchedule custom resource"
+	errTrackPCUsage      = "cannot track ProviderConfig usage"
+	errGetPC             = "cannot get ProviderConfig"
+	errGetCreds          = "cannot get credentials"
+	errNewClient         = "cannot create new Service"
+	errSchedule          = "cannot parse schedule"
+	errGetPlugin         = "cannot get referenced Plugin"
+	errPluginNoUUID      = "referenced Plugin has no UUID yet"
+	errGetDevice         = "cannot get referenced Device"
+	errDeviceNoUUID      = "referenced Device has no board UUID yet"
+	errListDevices       = "cannot list Devices"
+	errGetFleet          = "cannot get referenced Fleet"
+	errFleetNoUUID       = "referenced Fleet has no UUID yet"
+	errGetResults        = "cannot get request results"
+	errParameters        = "cannot decode action parameters"
+
+	// IoTronic results, as reported per board.
+	resultSuccess = "SUCCESS"
+	resultError   = "ERROR"
+	resultWarning = "WARNING"
+	resultRunning = "RUNNING"
+
+	// maxMessage bounds the plugin output recorded per board.
+	maxMessage = 256
+)
+
+// actions maps PluginSchedule actions to IoTronic plugin actions.
+var actions = map[v1alpha1.PluginScheduleActionType]string{
+	v1alpha1.PluginScheduleStart:   "PluginStart",
+	v1alpha1.PluginScheduleStop:    "PluginStop",
+	v1alpha1.PluginScheduleRestart: "PluginReboot",
+	v1alpha1.PluginScheduleCall:    "PluginCall",
+}
+
+type S4TService struct {
+	S4tClient *s4t.Client
+	BaseURL   string
+	Token     string
+}
+
+var (
+	newS4TService = func(creds []byte, keystoneEndpoint string) (*S4TService, error) {
+		c, err := providerconfig.Connect(creds, keystoneEndpoint)
+		if err != nil {
+			return nil, errors.Wrap(err, errNewClient)
+		}
+		return &S4TService{S4tClient: c}, nil
+	}
+)
+
+// Setup adds a controller that reconciles PluginSchedule managed resources.
+func Setup(mgr ctrl.Manager, o controller.Options) error {
+	name := managed.ControllerName(v1alpha1.PluginScheduleGroupKind)
+
+	cps := []managed.ConnectionPublisher{managed.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme())}
+	if o.Features.Enabled(features.EnableAlphaExternalSecretStores) {
+		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), apisv1alpha1.StoreConfigGroupVersionKind))
+	}
+
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
+
+	r := managed.NewReconciler(mgr,
+		resource.ManagedKind(v1alpha1.PluginScheduleGroupVersionKind),
+		managed.WithExternalConnecter(&connector{
+			kube:         mgr.GetClient(),
+			usage:        providerconfig.NewTracker(mgr.GetClient()),
+			recorder:     recorder,
+			newServiceFn: newS4TService}),
+		managed.WithLogger(o.Logger.WithValues("controller", name)),
+		managed.WithPollInterval(o.PollInterval),
+		managed.WithPollIntervalHook(pollInterval),
+		managed.WithRecorder(recorder),
+		managed.WithConnectionPublishers(cps...))
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		For(&v1alpha1.PluginSchedule{}, builder.WithPredicates(resource.DesiredStateChanged())).
+		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+}
+
+type connector struct {
+	kube         client.Client
+	usage        resource.Tracker
+	recorder     event.Recorder
+	newServiceFn func(creds []byte, keystoneEndpoint string) (*S4TService, error)
+}
+
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	_, ok := mg.(*v1alpha1.PluginSchedule)
+	if !ok {
+		return nil, errors.New(errNotPluginSchedule)
+	}
+
+	if err := c.usage.Track(ctx, mg); err != nil {
+		return nil, errors.Wrap(err, errTrackPCUsage)
+	}
+
+	pc_domain, err := providerconfig.Resolve(ctx, c.kube, mg)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetPC)
+	}
+	cd_domain := pc_domain.Spec.Credentials
+	data_domain, err := resource.CommonCredentialExtractor(ctx, cd_domain.Source, c.kube, cd_domain.CommonCredentialSelectors)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetCreds)
+	}
+
+	svc, err := c.newServiceFn(data_domain, providerconfig.KeystoneEndpoint(pc_domain))
+	if err != nil {
+		return nil, errors.Wrap(err, errNewClient)
+	}
+	if err := providerconfig.Configure(svc.S4tClient, pc_domain); err != nil {
+		return nil, errors.Wrap(err, errNewClient)
+	}
+	return &external{service: svc, kube: c.kube, recorder: c.recorder}, nil
+}
+
+// An ExternalClient observes, then either creates, updates, or deletes an
+// external resource to ensure it reflects the managed resource's desired state.
+// A PluginSchedule has no IoTronic counterpart of its own: it is up to date
+// unless a run is due, and updating it starts the run.
+type external struct {
+	service  *S4TService
+	kube     client.Client
+	recorder event.Recorder
+}
+
+// makeRESTCall makes a REST API call to the IoTronic service
+func (c *external) makeRESTCall(method, path string, data interface{}) (*http.Response, error) {
+	// Build URL using the service client's endpoint
+	// Default to Kubernetes service if host is not set
+	baseURL := fmt.Sprintf("%s:%s", c.service.S4tClient.Endpoint, c.service.S4tClient.Port)
+	url := fmt.Sprintf("%s/v1%s", baseURL, path)
+
+	var reqBody io.Reader
+	if data != nil {
+		jsonData, err := json.Marshal(data)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal request data")
+		}
+		reqBody = bytes.NewBuffer(jsonData)
+	}
+
+	req, err := http.NewRequest(method, url, reqBody)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create request")
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if c.service.S4tClient.AuthToken != "" {
+		req.Header.Set("X-Auth-Token", c.service.S4tClient.AuthToken)
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to execute request")
+	}
+
+	return resp, nil
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha1.PluginSchedule)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotPluginSchedule)
+	}
+
+	fmt.Printf("Observing PluginSchedule: %+v", cr)
+
+	// Runs in progress are left to finish on their own.
+	if meta.WasDeleted(cr) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	sched, err := parseSchedule(cr.Spec.ForProvider)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errSchedule)
+	}
+	if err := c.refresh(cr); err != nil {
+		return managed.ExternalObservation{}, err
+	}
+
+	now := time.Now()
+	obs := &cr.Status.AtProvider
+	due := mostRecent(sched, c.since(cr), now)
+	next := metav1.NewTime(sched.Next(now))
+	obs.NextScheduleTime = &next
+	cr.Status.SetConditions(xpv1.Available())
+	if err := c.validate(ctx, cr); err != nil {
+		cr.Status.SetConditions(v1alpha1.InvalidParameters(err))
+	}
+
+	return managed.ExternalObservation{
+		ResourceExists:    true,
+		ResourceUpToDate:  due.IsZero() || cr.Spec.ForProvider.Suspend,
+		ConnectionDetails: managed.ConnectionDetails{},
+	}, nil
+}
+
+// validate checks the parameters of a Call, as declared, so that a schedule
+// whose calls would all fail is reported before it runs. That includes one
+// whose Plugin's parameters schema changed since it was admitted.
+func (c *external) validate(ctx context.Context, cr *v1alpha1.PluginSchedule) error {
+	a := cr.Spec.ForProvider.Action
+	if a.Type != v1alpha1.PluginScheduleCall || a.Parameters == nil {
+		return nil
+	}
+	if err := params.Validate(a.Parameters.Raw); err != nil {
+		return err
+	}
+	schema, err := params.SchemaFor(ctx, c.kube, cr.Spec.ForProvider.PluginRef.Name, "")
+	if err != nil || schema == nil {
+		return err
+	}
+	return schema.ValidateSpec(field.NewPath("spec", "forProvider", "action", "parameters"), a.Parameters.Raw, a.ParametersFrom).ToAggregate()
+}
+
+// since is the time after which runs of cr are due: its last scheduled time,
+// or its creation.
+func (c *external) since(cr *v1alpha1.PluginSchedule) time.Time {
+	if t := cr.Status.AtProvider.LastScheduleTime; t != nil {
+		return t.Time
+	}
+	return cr.GetCreationTimestamp().Time
+}
+
+// refresh follows the IoTronic requests of the runs in progress, finishing
+// the runs every board has answered or that timed out.
+func (c *external) refresh(cr *v1alpha1.PluginSchedule) error {
+	obs := &cr.Status.AtProvider
+	obs.Active = 0
+	now := time.Now()
+	for i := range obs.Runs {
+		run := &obs.Runs[i]
+		if run.Phase != v1alpha1.RunRunning {
+			continue
+		}
+		for j := range run.Boards {
+			b := &run.Boards[j]
+			if b.RequestUuid == "" || !strings.EqualFold(b.Result, resultRunning) {
+				continue
+			}
+			results, err := c.getResults(b.RequestUuid)
+			if err != nil {
+				return errors.Wrap(err, errGetResults)
+			}
+			for _, r := range results {
+				if r.BoardUuid == b.BoardUuid && r.Result != "" {
+					b.Result, b.Message = r.Result, r.Message
+				}
+			}
+		}
+		// A request IoTronic no longer knows, or that never reports a
+		// result, would otherwise keep the run going forever.
+		timeOut(run, runTimeout(cr.Spec.ForProvider), now)
+		if run.Phase = runPhase(run.Boards); run.Phase == v1alpha1.RunRunning {
+			obs.Active++
+			continue
+		}
+		c.finished(cr, run)
+	}
+	obs.Runs = prune(obs.Runs, cr.Spec.ForProvider)
+	return nil
+}
+
+// finished records that a run reached a terminal phase.
+func (c *external) finished(cr *v1alpha1.PluginSchedule, run *v1alpha1.PluginScheduleRun) {
+	now := metav1.Now()
+	run.CompletionTime = &now
+	if run.Phase == v1alpha1.RunSucceeded {
+		cr.Status.AtProvider.LastSuccessfulTime = &now
+		c.recorder.Event(cr, event.Normal("RunSucceeded", fmt.Sprintf("Run scheduled for %s succeeded on %d boards", run.ScheduledTime.UTC().Format(time.RFC3339), len(run.Boards))))
+		return
+	}
+	failed := 0
+	for _, b := range run.Boards {
+		if strings.EqualFold(b.Result, resultError) {
+			failed++
+		}
+	}
+	c.recorder.Event(cr, event.Warning("RunFailed", errors.Errorf("run scheduled for %s failed on %d of %d boards", run.ScheduledTime.UTC().Format(time.RFC3339), failed, len(run.Boards))))
+}
+
+// getResults returns the per-board results of a request.
+// API: GET /v1/requests/{uuid}/results
+func (c *external) getResults(uuid string) ([]v1alpha1.PluginScheduleRunBoard, error) {
+	resp, err := c.makeRESTCall("GET", fmt.Sprintf("/requests/%s/results", uuid), nil)
+	if err != nil {
+		log.Printf("Error getting request results: %v", err)
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	// IoTronic wraps collections in an object named after them.
+	var body json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, errors.Wrap(err, "failed to decode response")
+	}
+	var items []map[string]interface{}
+	if err := json.Unmarshal(body, &items); err != nil {
+		var collection struct {
+			Results []map[string]interface{} `json:"results"`
+		}
+		if err := json.Unmarshal(body, &collection); err != nil {
+			return nil, errors.Wrap(err, "failed to decode response")
+		}
+		items = collection.Results
+	}
+
+	results := make([]v1alpha1.PluginScheduleRunBoard, 0, len(items))
+	for _, i := range items {
+		r := v1alpha1.PluginScheduleRunBoard{}
+		r.BoardUuid, _ = i["board_uuid"].(string)
+		r.Result, _ = i["result"].(string)
+		r.Message, _ = i["message"].(string)
+		results = append(results, r)
+	}
+	return results, nil
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1alpha1.PluginSchedule)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotPluginSchedule)
+	}
+
+	fmt.Printf("Creating PluginSchedule: %+v", cr)
+
+	return managed.ExternalCreation{
+		ConnectionDetails: managed.ConnectionDetails{},
+	}, nil
+}
+
+// Update starts the run that is due, subject to the missed run and
+// concurrency policies.
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*v1alpha1.PluginSchedule)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotPluginSchedule)
+	}
+
+	fmt.Printf("Updating PluginSchedule: %+v", cr)
+
+	p := cr.Spec.ForProvider
+	sched, err := parseSchedule(p)
+	if err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errSchedule)
+	}
+	now := time.Now()
+	due := mostRecent(sched, c.since(cr), now)
+	if due.IsZero() || p.Suspend {
+		return managed.ExternalUpdate{}, nil
+	}
+	when := due.UTC().Format(time.RFC3339)
+
+	if late := now.Sub(due); late > startingDeadline(p) && p.MissedRunPolicy == v1alpha1.SkipMissed {
+		c.schedule(cr, due)
+		c.recorder.Event(cr, event.Warning("MissedSchedule", errors.Errorf("skipped run scheduled for %s: it is %s late", when, late.Round(time.Second))))
+		return managed.ExternalUpdate{}, nil
+	}
+
+	// The plugin and boards are resolved before the run is recorded, so
+	// that a run whose target is not ready yet is retried rather than lost.
+	plugin, err := c.pluginUuid(ctx, p.PluginRef)
+	if err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+
+	obs := &cr.Status.AtProvider
+	if obs.Active > 0 {
+		switch p.ConcurrencyPolicy {
+		case v1alpha1.ForbidConcurrent:
+			c.schedule(cr, due)
+			c.recorder.Event(cr, event.Normal("SkippedSchedule", fmt.Sprintf("Skipped run scheduled for %s: %d runs still in progress", when, obs.Active)))
+			return managed.ExternalUpdate{}, nil
+		case v1alpha1.ReplaceConcurrent:
+			for i := range obs.Runs {
+				if obs.Runs[i].Phase == v1alpha1.RunRunning {
+					c.replace(cr, &obs.Runs[i], plugin)
+				}
+			}
+			obs.Active = 0
+		}
+	}
+
+	boards, err := c.boards(ctx, p.Target)
+	if err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+	// A run happens at its scheduled time, so boards outside their
+	// maintenance window are skipped rather than deferred.
+	deferred := map[string]*maintenance.Deferred{}
+	for _, b := range boards {
+		d, err := maintenance.Check(ctx, c.kube, cr, b)
+		if err != nil {
+			return managed.ExternalUpdate{}, err
+		}
+		if d != nil {
+			deferred[b] = d
+		}
+	}
+	schema, err := params.SchemaFor(ctx, c.kube, p.PluginRef.Name, "")
+	if err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+
+	c.schedule(cr, due)
+	start := metav1.Now()
+	run := v1alpha1.PluginScheduleRun{
+		ScheduledTime: metav1.NewTime(due),
+		StartTime:     &start,
+		Boards:        make([]v1alpha1.PluginScheduleRunBoard, 0, len(boards)),
+	}
+	for _, b := range boards {
+		if d := deferred[b]; d != nil {
+			run.Boards = append(run.Boards, v1alpha1.PluginScheduleRunBoard{BoardUuid: b, Result: resultWarning, Message: "skipped: " + d.Error()})
+			continue
+		}
+		parameters, err := c.parameters(ctx, p.Action, b, schema)
+		if err != nil {
+			run.Boards = append(run.Boards, v1alpha1.PluginScheduleRunBoard{BoardUuid: b, Result: resultError, Message: truncate(err.Error())})
+			continue
+		}
+		run.Boards = append(run.Boards, c.act(b, plugin, actions[p.Action.Type], parameters))
+	}
+	c.recorder.Event(cr, event.Normal("ScheduledRun", fmt.Sprintf("%s plugin on %d boards for run scheduled for %s", p.Action.Type, len(boards)-len(deferred), when)))
+	if len(deferred) > 0 {
+		c.recorder.Event(cr, event.Normal("SkippedBoards", fmt.Sprintf("Skipped %d boards outside their maintenance window", len(deferred))))
+	}
+	if run.Phase = runPhase(run.Boards); run.Phase == v1alpha1.RunRunning {
+		obs.Active++
+	} else {
+		c.finished(cr, &run)
+	}
+	obs.Runs = prune(append(obs.Runs, run), p)
+
+	return managed.ExternalUpdate{
+		ConnectionDetails: managed.ConnectionDetails{},
+	}, nil
+}
+
+// replace stops the plugin on the boards of run that are still running and
+// stops tracking it.
+func (c *external) replace(cr *v1alpha1.PluginSchedule, run *v1alpha1.PluginScheduleRun, plugin string) {
+	stopped, failed := 0, 0
+	for i := range run.Boards {
+		b := &run.Boards[i]
+		if !strings.EqualFold(b.Result, resultRunning) {
+			continue
+		}
+		if r := c.act(b.BoardUuid, plugin, actions[v1alpha1.PluginScheduleStop], nil); strings.EqualFold(r.Result, resultError) {
+			b.Message = truncate("replaced, cannot stop plugin: " + r.Message)
+			failed++
+			continue
+		}
+		b.Message = "replaced, plugin stopped"
+		stopped++
+	}
+	now := metav1.Now()
+	run.Phase, run.CompletionTime = v1alpha1.RunReplaced, &now
+	when := run.ScheduledTime.UTC().Format(time.RFC3339)
+	if failed > 0 {
+		c.recorder.Event(cr, event.Warning("ReplacedRun", errors.Errorf("replaced run scheduled for %s: cannot stop plugin on %d of %d running boards", when, failed, stopped+failed)))
+		return
+	}
+	c.recorder.Event(cr, event.Normal("ReplacedRun", fmt.Sprintf("Replaced run scheduled for %s: stopped plugin on %d boards", when, stopped)))
+}
+
+// parameters decodes the parameters of a Call, rendered for board and with
+// the values of its Secrets merged in, once they match the schema of the
+// plugin, if any.
+func (c *external) parameters(ctx context.Context, a v1alpha1.PluginScheduleAction, board string, schema *params.Schema) (interface{}, error) {
+	var raw []byte
+	if a.Parameters != nil {
+		raw = a.Parameters.Raw
+	}
+	if a.Type != v1alpha1.PluginScheduleCall || (len(raw) == 0 && len(a.ParametersFrom) == 0) {
+		return nil, nil
+	}
+	if params.Templated(raw) {
+		d, err := params.ForBoard(ctx, c.kube, board, a.TemplateValues)
+		if err != nil {
+			return nil, err
+		}
+		if raw, err = params.Render(raw, d); err != nil {
+			return nil, err
+		}
+	}
+	raw, err := params.Merge(ctx, c.kube, raw, a.ParametersFrom)
+	if err != nil {
+		return nil, err
+	}
+	if schema != nil {
+		if errs := schema.Validate(field.NewPath("parameters"), raw, a.ParametersFrom); len(errs) > 0 {
+			return nil, errs.ToAggregate()
+		}
+	}
+	var v interface{}
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return nil, errors.Wrap(err, errParameters)
+	}
+	return v, nil
+}
+
+// schedule records that the run due at t was handled.
+func (c *external) schedule(cr *v1alpha1.PluginSchedule, t time.Time) {
+	last := metav1.NewTime(t)
+	cr.Status.AtProvider.LastScheduleTime = &last
+}
+
+// pluginUuid returns the IoTronic UUID of the referenced Plugin.
+func (c *external) pluginUuid(ctx context.Context, ref xpv1.Reference) (string, error) {
+	p := &v1alpha1.Plugin{}
+	if err := c.kube.Get(ctx, types.NamespacedName{Name: ref.Name}, p); err != nil {
+		return "", errors.Wrap(err, errGetPlugin)
+	}
+	if p.Spec.ForProvider.Uuid == "" {
+		return "", errors.New(errPluginNoUUID)
+	}
+	return p.Spec.ForProvider.Uuid, nil
+}
+
+// boards returns the UUIDs of the boards t selects, sorted.
+func (c *external) boards(ctx context.Context, t v1alpha1.PluginScheduleTarget) ([]string, error) {
+	switch {
+	case t.DeviceRef != nil:
+		d := &v1alpha1.Device{}
+		if err := c.kube.Get(ctx, types.NamespacedName{Name: t.DeviceRef.Name}, d); err != nil {
+			return nil, errors.Wrap(err, errGetDevice)
+		}
+		if d.Spec.ForProvider.Uuid == "" {
+			return nil, errors.New(errDeviceNoUUID)
+		}
+		return []string{d.Spec.ForProvider.Uuid}, nil
+
+	case t.DeviceSelector != nil:
+		sel, err := metav1.LabelSelectorAsSelector(t.DeviceSelector)
+		if err != nil {
+			return nil, errors.Wrap(err, errListDevices)
+		}
+		l := &v1alpha1.DeviceList{}
+		if err := c.kube.List(ctx, l, client.MatchingLabelsSelector{Selector: sel}); err != nil {
+			return nil, errors.Wrap(err, errListDevices)
+		}
+		var boards []string
+		for _, d := range l.Items {
+			if !meta.WasDeleted(&d) && d.Spec.ForProvider.Uuid != "" && sel.Matches(labels.Set(d.GetLabels())) {
+				boards = append(boards, d.Spec.ForProvider.Uuid)
+			}
+		}
+		sort.Strings(boards)
+		return boards, nil
+	}
+	return c.fleetBoards(ctx, t.FleetRef)
+}
+
+// fleetBoards returns the UUIDs of the boards that belong to the referenced
+// Fleet, sorted.
+// API: GET /v1/fleets/{fleet_uuid}/boards
+// Response: {"boards": [{"uuid": "...", ...}]}
+func (c *external) fleetBoards(ctx context.Context, ref *xpv1.Reference) ([]string, error) {
+	f := &v1alpha1.Fleet{}
+	if err := c.kube.Get(ctx, types.NamespacedName{Name: ref.Name}, f); err != nil {
+		return nil, errors.Wrap(err, errGetFleet)
+	}
+	if f.Spec.ForProvider.Uuid == "" {
+		return nil, errors.New(errFleetNoUUID)
+	}
+
+	resp, err := c.makeRESTCall("GET", fmt.Sprintf("/fleets/%s/boards", f.Spec.ForProvider.Uuid), nil)
+	if err != nil {
+		log.Printf("Error getting fleet boards: %v", err)
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Boards []struct {
+			Uuid string `json:"uuid"`
+		} `json:"boards"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, errors.Wrap(err, "failed to decode response")
+	}
+
+	boards := make([]string, 0, len(body.Boards))
+	for _, b := range body.Boards {
+		boards = append(boards, b.Uuid)
+	}
+	sort.Strings(boards)
+	return boards, nil
+}
+
+// act performs action on the plugin injected into board and reports the
+// outcome. IoTronic either answers with the plugin's output, or with the
+// UUID of a request whose results follow.
+// API: POST /v1/boards/{board_uuid}/plugins/{plugin_uuid}
+// Request Body: {"action": "PluginStart|PluginStop|PluginReboot|PluginCall", "parameters": {...}}
+func (c *external) act(board, plugin, action string, parameters interface{}) v1alpha1.PluginScheduleRunBoard {
+	out := v1alpha1.PluginScheduleRunBoard{BoardUuid: board}
+	data := map[string]interface{}{"action": action}
+	if parameters != nil {
+		data["parameters"] = parameters
+	}
+	resp, err := c.makeRESTCall("POST", fmt.Sprintf("/boards/%s/plugins/%s", board, plugin), data)
+	if err != nil {
+		out.Result, out.Message = resultError, err.Error()
+		return out
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		out.Result, out.Message = resultError, errors.Wrap(err, "failed to read response").Error()
+		return out
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
+		out.Result, out.Message = resultError, truncate(fmt.Sprintf("unexpected status code: %d, body: %s", resp.StatusCode, string(body)))
+		return out
+	}
+
+	var tracked struct {
+		RequestUuid string `json:"request_uuid"`
+	}
+	if json.Unmarshal(body, &tracked) == nil && tracked.RequestUuid != "" {
+		out.RequestUuid, out.Result = tracked.RequestUuid, resultRunning
+		return out
+	}
+	out.Result, out.Message = resultSuccess, truncate(strings.TrimSpace(string(body)))
+	return out
+}
+
+func truncate(s string) string {
+	if len(s) > maxMessage {
+		return s[:maxMessage]
+	}
+	return s
+}
+
+// Delete does nothing: a PluginSchedule owns nothing in IoTronic, and runs in
+// progress are left to finish.
+func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(*v1alpha1.PluginSchedule)
+	if !ok {
+		return errors.New(errNotPluginSchedule)
+	}
+
+	fmt.Printf("Deleting PluginSchedule: %+v", cr)
+
+	return nil
+}
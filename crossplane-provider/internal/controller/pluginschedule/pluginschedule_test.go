@@ -0,0 +1,124 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pluginschedule
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	s4t "github.com/MIKE9708/s4t-sdk-go/pkg/api"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/crossplane/provider-s4t/apis/iot/v1alpha1"
+)
+
+// iotronic answers every request with status, recording the paths of the
+// POSTs it receives.
+func iotronic(t *testing.T, status map[string]int) (*S4TService, *[]string) {
+	t.Helper()
+	var (
+		mu    sync.Mutex
+		posts []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			mu.Lock()
+			posts = append(posts, r.URL.Path)
+			mu.Unlock()
+		}
+		code, ok := status[r.URL.Path]
+		if !ok {
+			code = http.StatusNotFound
+		}
+		w.WriteHeader(code)
+	}))
+	t.Cleanup(srv.Close)
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &S4TService{S4tClient: &s4t.Client{Endpoint: u.Scheme + "://" + u.Hostname(), Port: u.Port()}}, &posts
+}
+
+func TestRefreshTimedOut(t *testing.T) {
+	// IoTronic no longer knows the request of board-1.
+	svc, _ := iotronic(t, nil)
+	c := &external{service: svc, recorder: event.NewNopRecorder()}
+
+	cr := &v1alpha1.PluginSchedule{}
+	cr.Status.AtProvider.Active = 1
+	cr.Status.AtProvider.Runs = []v1alpha1.PluginScheduleRun{{
+		Phase:     v1alpha1.RunRunning,
+		StartTime: ptr.To(metav1.NewTime(time.Now().Add(-2 * time.Hour))),
+		Boards:    []v1alpha1.PluginScheduleRunBoard{{BoardUuid: "board-1", RequestUuid: "req-1", Result: "RUNNING"}},
+	}}
+	if err := c.refresh(cr); err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	obs := cr.Status.AtProvider
+	if obs.Active != 0 {
+		t.Errorf("refresh: want no active runs, got %d", obs.Active)
+	}
+	if got := obs.Runs[0].Phase; got != v1alpha1.RunFailed {
+		t.Errorf("refresh: want phase %s, got %s", v1alpha1.RunFailed, got)
+	}
+	if obs.Runs[0].CompletionTime == nil {
+		t.Errorf("refresh: want completion time")
+	}
+}
+
+func TestReplace(t *testing.T) {
+	svc, posts := iotronic(t, map[string]int{
+		"/v1/boards/board-1/plugins/plugin-1": http.StatusOK,
+		"/v1/boards/board-2/plugins/plugin-1": http.StatusInternalServerError,
+	})
+	c := &external{service: svc, recorder: event.NewNopRecorder()}
+
+	run := &v1alpha1.PluginScheduleRun{
+		Phase: v1alpha1.RunRunning,
+		Boards: []v1alpha1.PluginScheduleRunBoard{
+			{BoardUuid: "board-1", Result: "RUNNING"},
+			{BoardUuid: "board-2", Result: "RUNNING"},
+			{BoardUuid: "board-3", Result: "SUCCESS"},
+		},
+	}
+	c.replace(&v1alpha1.PluginSchedule{}, run, "plugin-1")
+
+	if run.Phase != v1alpha1.RunReplaced || run.CompletionTime == nil {
+		t.Errorf("replace: want phase %s with a completion time, got %s", v1alpha1.RunReplaced, run.Phase)
+	}
+	want := []string{"/v1/boards/board-1/plugins/plugin-1", "/v1/boards/board-2/plugins/plugin-1"}
+	if diff := cmp.Diff(want, *posts); diff != "" {
+		t.Errorf("replace: -want, +got POSTs:\n%s", diff)
+	}
+	if got := run.Boards[0].Message; got != "replaced, plugin stopped" {
+		t.Errorf("replace: board-1 message %q", got)
+	}
+	if got := run.Boards[1].Message; got == "" || got == "replaced, plugin stopped" {
+		t.Errorf("replace: board-2 message %q, want the stop error", got)
+	}
+	if got := run.Boards[2].Message; got != "" {
+		t.Errorf("replace: finished board-3 message %q, want none", got)
+	}
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pluginschedule
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	// Time zones are resolved from the binary, as the provider image has no
+	// zoneinfo.
+	_ "time/tzdata"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	"github.com/robfig/cron/v3"
+
+	"github.com/crossplane/provider-s4t/apis/iot/v1alpha1"
+)
+
+const (
+	defaultStartingDeadline = 60 * time.Second
+	defaultRunTimeout       = time.Hour
+	defaultSuccessfulRuns   = 3
+	defaultFailedRuns       = 1
+
+	// activePollInterval is how often PluginSchedules with runs in progress
+	// are observed.
+	activePollInterval = 5 * time.Second
+)
+
+// parseSchedule parses the cron expression of p in its time zone.
+func parseSchedule(p v1alpha1.PluginScheduleParameters) (cron.Schedule, error) {
+	if strings.HasPrefix(p.Schedule, "CRON_TZ=") || strings.HasPrefix(p.Schedule, "TZ=") {
+		return nil, errors.New("set the time zone with timeZone, not in the schedule")
+	}
+	tz := "UTC"
+	if p.TimeZone != nil && *p.TimeZone != "" {
+		tz = *p.TimeZone
+	}
+	return cron.ParseStandard("CRON_TZ=" + tz + " " + p.Schedule)
+}
+
+// mostRecent returns the latest time s was due after from and no later than
+// now, or the zero time if it was not due.
+func mostRecent(s cron.Schedule, from, now time.Time) time.Time {
+	var last time.Time
+	for t := s.Next(from); !t.IsZero() && !t.After(now); t = s.Next(t) {
+		last = t
+	}
+	return last
+}
+
+// startingDeadline is how late a run of p may start.
+func startingDeadline(p v1alpha1.PluginScheduleParameters) time.Duration {
+	if p.StartingDeadlineSeconds == nil {
+		return defaultStartingDeadline
+	}
+	return time.Duration(*p.StartingDeadlineSeconds) * time.Second
+}
+
+// runTimeout is how long the boards of a run of p may take to report an
+// outcome.
+func runTimeout(p v1alpha1.PluginScheduleParameters) time.Duration {
+	if p.RunTimeoutSeconds == nil {
+		return defaultRunTimeout
+	}
+	return time.Duration(*p.RunTimeoutSeconds) * time.Second
+}
+
+// timeOut fails the boards of run that have not reported an outcome if run
+// started more than timeout before now.
+func timeOut(run *v1alpha1.PluginScheduleRun, timeout time.Duration, now time.Time) {
+	if run.StartTime == nil || now.Sub(run.StartTime.Time) <= timeout {
+		return
+	}
+	for i := range run.Boards {
+		b := &run.Boards[i]
+		switch strings.ToUpper(b.Result) {
+		case resultRunning, "":
+			b.Result, b.Message = resultError, fmt.Sprintf("no outcome reported within %s", timeout)
+		}
+	}
+}
+
+// runPhase derives the phase of a run from its per-board results.
+func runPhase(boards []v1alpha1.PluginScheduleRunBoard) v1alpha1.PluginScheduleRunPhase {
+	failed := false
+	for _, b := range boards {
+		switch strings.ToUpper(b.Result) {
+		case resultRunning, "":
+			return v1alpha1.RunRunning
+		case resultError:
+			failed = true
+		}
+	}
+	if failed {
+		return v1alpha1.RunFailed
+	}
+	return v1alpha1.RunSucceeded
+}
+
+// prune drops the oldest finished runs beyond the history limits of p.
+// Running runs are always kept.
+func prune(runs []v1alpha1.PluginScheduleRun, p v1alpha1.PluginScheduleParameters) []v1alpha1.PluginScheduleRun {
+	succeeded, failed := int32(defaultSuccessfulRuns), int32(defaultFailedRuns)
+	if p.SuccessfulRunsHistoryLimit != nil {
+		succeeded = *p.SuccessfulRunsHistoryLimit
+	}
+	if p.FailedRunsHistoryLimit != nil {
+		failed = *p.FailedRunsHistoryLimit
+	}
+
+	keep := make([]bool, len(runs))
+	for i := len(runs) - 1; i >= 0; i-- {
+		switch runs[i].Phase {
+		case v1alpha1.RunRunning:
+			keep[i] = true
+		case v1alpha1.RunSucceeded:
+			keep[i] = succeeded > 0
+			succeeded--
+		default:
+			keep[i] = failed > 0
+			failed--
+		}
+	}
+	out := make([]v1alpha1.PluginScheduleRun, 0, len(runs))
+	for i, r := range runs {
+		if keep[i] {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// pollInterval observes PluginSchedules often while runs are in progress,
+// and otherwise when their next run is due, if that is before their next
+// poll.
+func pollInterval(mg resource.Managed, d time.Duration) time.Duration {
+	cr, ok := mg.(*v1alpha1.PluginSchedule)
+	if !ok {
+		return d
+	}
+	if cr.Status.AtProvider.Active > 0 && d > activePollInterval {
+		return activePollInterval
+	}
+	if next := cr.Status.AtProvider.NextScheduleTime; next != nil && !cr.Spec.ForProvider.Suspend {
+		if until := time.Until(next.Time); until < d {
+			return max(until, 0) + time.Second
+		}
+	}
+	return d
+}
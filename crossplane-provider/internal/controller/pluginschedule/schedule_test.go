@@ -0,0 +1,145 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pluginschedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/crossplane/provider-s4t/apis/iot/v1alpha1"
+)
+
+func TestMostRecent(t *testing.T) {
+	rome, err := time.LoadLocation("Europe/Rome")
+	if err != nil {
+		t.Fatalf("LoadLocation: %v", err)
+	}
+	cases := map[string]struct {
+		p    v1alpha1.PluginScheduleParameters
+		from time.Time
+		now  time.Time
+		want time.Time
+	}{
+		"NotDue": {
+			p:    v1alpha1.PluginScheduleParameters{Schedule: "0 3 * * *"},
+			from: time.Date(2026, 1, 1, 4, 0, 0, 0, time.UTC),
+			now:  time.Date(2026, 1, 2, 2, 0, 0, 0, time.UTC),
+		},
+		"LatestOfMissed": {
+			p:    v1alpha1.PluginScheduleParameters{Schedule: "@hourly"},
+			from: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+			now:  time.Date(2026, 1, 1, 5, 30, 0, 0, time.UTC),
+			want: time.Date(2026, 1, 1, 5, 0, 0, 0, time.UTC),
+		},
+		"TimeZone": {
+			p:    v1alpha1.PluginScheduleParameters{Schedule: "0 3 * * *", TimeZone: ptr.To("Europe/Rome")},
+			from: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+			now:  time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC),
+			want: time.Date(2026, 1, 1, 3, 0, 0, 0, rome),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s, err := parseSchedule(tc.p)
+			if err != nil {
+				t.Fatalf("parseSchedule: %v", err)
+			}
+			if got := mostRecent(s, tc.from, tc.now); !got.Equal(tc.want) {
+				t.Errorf("mostRecent: want %s, got %s", tc.want, got)
+			}
+		})
+	}
+
+	if _, err := parseSchedule(v1alpha1.PluginScheduleParameters{Schedule: "CRON_TZ=UTC 0 3 * * *"}); err == nil {
+		t.Error("parseSchedule with CRON_TZ: want error")
+	}
+}
+
+func TestRunPhase(t *testing.T) {
+	cases := map[string]struct {
+		results []string
+		want    v1alpha1.PluginScheduleRunPhase
+	}{
+		"Succeeded":  {results: []string{"SUCCESS", "WARNING"}, want: v1alpha1.RunSucceeded},
+		"Failed":     {results: []string{"SUCCESS", "ERROR"}, want: v1alpha1.RunFailed},
+		"Running":    {results: []string{"ERROR", "RUNNING"}, want: v1alpha1.RunRunning},
+		"NoBoards":   {want: v1alpha1.RunSucceeded},
+		"Unanswered": {results: []string{""}, want: v1alpha1.RunRunning},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			boards := make([]v1alpha1.PluginScheduleRunBoard, 0, len(tc.results))
+			for _, r := range tc.results {
+				boards = append(boards, v1alpha1.PluginScheduleRunBoard{Result: r})
+			}
+			if got := runPhase(boards); got != tc.want {
+				t.Errorf("runPhase: want %s, got %s", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestPrune(t *testing.T) {
+	run := func(hour int, p v1alpha1.PluginScheduleRunPhase) v1alpha1.PluginScheduleRun {
+		return v1alpha1.PluginScheduleRun{ScheduledTime: metav1.NewTime(time.Date(2026, 1, 1, hour, 0, 0, 0, time.UTC)), Phase: p}
+	}
+	runs := []v1alpha1.PluginScheduleRun{
+		run(0, v1alpha1.RunRunning),
+		run(1, v1alpha1.RunSucceeded),
+		run(2, v1alpha1.RunFailed),
+		run(3, v1alpha1.RunSucceeded),
+		run(4, v1alpha1.RunReplaced),
+		run(5, v1alpha1.RunSucceeded),
+	}
+	p := v1alpha1.PluginScheduleParameters{SuccessfulRunsHistoryLimit: ptr.To[int32](2)}
+
+	want := []v1alpha1.PluginScheduleRun{runs[0], runs[3], runs[4], runs[5]}
+	if diff := cmp.Diff(want, prune(runs, p)); diff != "" {
+		t.Errorf("prune: -want, +got:\n%s", diff)
+	}
+}
+
+func TestTimeOut(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	cases := map[string]struct {
+		start time.Time
+		want  []string
+	}{
+		"InTime":   {start: now.Add(-30 * time.Minute), want: []string{"SUCCESS", "RUNNING", ""}},
+		"TimedOut": {start: now.Add(-2 * time.Hour), want: []string{"SUCCESS", "ERROR", "ERROR"}},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			run := &v1alpha1.PluginScheduleRun{
+				StartTime: ptr.To(metav1.NewTime(tc.start)),
+				Boards:    []v1alpha1.PluginScheduleRunBoard{{Result: "SUCCESS"}, {Result: "RUNNING"}, {}},
+			}
+			timeOut(run, time.Hour, now)
+			got := make([]string, 0, len(run.Boards))
+			for _, b := range run.Boards {
+				got = append(got, b.Result)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("timeOut: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
@@ -24,28 +24,28 @@ import (
 	"io"
 	"log"
 	"net/http"
-	"os"
-	"strings"
 	"time"
 
 	s4t "github.com/MIKE9708/s4t-sdk-go/pkg/api"
-	read_config "github.com/MIKE9708/s4t-sdk-go/pkg/read_conf"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/connection"
 	"github.com/crossplane/crossplane-runtime/pkg/controller"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/pkg/errors"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/crossplane/provider-s4t/apis/iot/v1alpha1"
 	apisv1alpha1 "github.com/crossplane/provider-s4t/apis/v1alpha1"
+	"github.com/crossplane/provider-s4t/internal/diff"
 	"github.com/crossplane/provider-s4t/internal/features"
+	"github.com/crossplane/provider-s4t/internal/ipam"
+	"github.com/crossplane/provider-s4t/internal/providerconfig"
 )
 
 const (
@@ -54,6 +54,10 @@ const (
 	errGetPC        = "cannot get ProviderConfig"
 	errGetCreds     = "cannot get credentials"
 	errNewClient    = "cannot create new Service"
+	errAllocate     = "cannot allocate port address"
+	errRelease      = "cannot release port address"
+
+	reasonDrift event.Reason = "DriftDetected"
 )
 
 type S4TService struct {
@@ -64,69 +68,11 @@ type S4TService struct {
 
 var (
 	newS4TService = func(creds []byte, keystoneEndpoint string) (*S4TService, error) {
-		var result map[string]string
-		err := json.Unmarshal(creds, &result)
-		if err != nil {
-			return nil, errors.Wrap(err, errNewClient)
-		}
-		auth_req := read_config.FormatAuthRequ(
-			result["username"],
-			result["password"],
-			result["domain"],
-		)
-		
-		// Set Keystone endpoint via environment variable (OS_AUTH_URL)
-		if keystoneEndpoint != "" {
-			os.Setenv("OS_AUTH_URL", keystoneEndpoint)
-		} else {
-			os.Setenv("OS_AUTH_URL", "http://keystone.default.svc.cluster.local:5000/v3")
-		}
-		// Extract host from keystoneEndpoint BEFORE creating client
-		// SDK uses hardcoded 127.0.0.1, we need to use Kubernetes service
-		endpoint := keystoneEndpoint
-		if endpoint == "" {
-			endpoint = "http://keystone.default.svc.cluster.local:5000/v3"
-		}
-		if strings.HasSuffix(endpoint, "/v3") {
-			endpoint = strings.TrimSuffix(endpoint, "/v3")
-		}
-		scheme := "http://"
-		if strings.HasPrefix(endpoint, "https://") {
-			scheme = "https://"
-			endpoint = strings.TrimPrefix(endpoint, "https://")
-		} else if strings.HasPrefix(endpoint, "http://") {
-			endpoint = strings.TrimPrefix(endpoint, "http://")
-		}
-		if idx := strings.Index(endpoint, ":"); idx != -1 {
-			endpoint = endpoint[:idx]
-		}
-		keystoneHost := scheme + endpoint
-		
-		// Create client manually with correct host (without port) instead of using GetClientConnection
-		// SDK will derive final URL using AuthPort
-		s4t_client := s4t.NewClient(keystoneHost)
-		s4t_client.Port = "8812"
-		s4t_client.AuthPort = "5000"
-		
-		// Authenticate with correct endpoint
-		token, err := s4t_client.Authenticate(s4t_client, auth_req)
-		if err != nil {
-			return nil, errors.Wrap(err, errNewClient)
-		}
-		s4t_client.AuthToken = token
-		
-		iotronicHost := scheme + "iotronic-conductor.default.svc.cluster.local"
-		s4t_client.Endpoint = iotronicHost
-		
+		c, err := providerconfig.Connect(creds, keystoneEndpoint)
 		if err != nil {
 			return nil, errors.Wrap(err, errNewClient)
 		}
-		// Extract base URL and token from client if available
-		// For now, we'll use default conductor endpoint
-		return &S4TService{
-			S4tClient: s4t_client,
-			BaseURL:   "http://iotronic-conductor:8812", // Default conductor endpoint
-		}, nil
+		return &S4TService{S4tClient: c}, nil
 	}
 )
 
@@ -139,15 +85,19 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), apisv1alpha1.StoreConfigGroupVersionKind))
 	}
 
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
+
 	r := managed.NewReconciler(mgr,
 		resource.ManagedKind(v1alpha1.PortGroupVersionKind),
 		managed.WithExternalConnecter(&connector{
 			kube:         mgr.GetClient(),
-			usage:        resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
+			usage:        providerconfig.NewTracker(mgr.GetClient()),
+			recorder:     recorder,
 			newServiceFn: newS4TService}),
+		managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
 		managed.WithLogger(o.Logger.WithValues("controller", name)),
 		managed.WithPollInterval(o.PollInterval),
-		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		managed.WithRecorder(recorder),
 		managed.WithConnectionPublishers(cps...))
 
 	return ctrl.NewControllerManagedBy(mgr).
@@ -161,6 +111,7 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 type connector struct {
 	kube         client.Client
 	usage        resource.Tracker
+	recorder     event.Recorder
 	newServiceFn func(creds []byte, keystoneEndpoint string) (*S4TService, error)
 }
 
@@ -174,8 +125,8 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 		return nil, errors.Wrap(err, errTrackPCUsage)
 	}
 
-	pc_domain := &apisv1alpha1.ProviderConfig{}
-	if err := c.kube.Get(ctx, types.NamespacedName{Name: "s4t-provider-domain"}, pc_domain); err != nil {
+	pc_domain, err := providerconfig.Resolve(ctx, c.kube, mg)
+	if err != nil {
 		return nil, errors.Wrap(err, errGetPC)
 	}
 	cd_domain := pc_domain.Spec.Credentials
@@ -194,7 +145,10 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 	if err != nil {
 		return nil, errors.Wrap(err, errNewClient)
 	}
-	return &external{service: svc}, err
+	if err := providerconfig.Configure(svc.S4tClient, pc_domain); err != nil {
+		return nil, errors.Wrap(err, errNewClient)
+	}
+	return &external{kube: c.kube, service: svc, recorder: c.recorder}, err
 }
 
 // An ExternalClient observes, then either creates, updates, or deletes an
@@ -202,14 +156,16 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 type external struct {
 	// A 'client' used to connect to the external resource API. In practice this
 	// would be something like an AWS SDK client.
-	service *S4TService
+	service  *S4TService
+	kube     client.Client
+	recorder event.Recorder
 }
 
 // makeRESTCall makes a REST API call to the IoTronic service
 func (c *external) makeRESTCall(method, path string, data interface{}) (*http.Response, error) {
 	// Build URL using the service client's endpoint
 	// Default to Kubernetes service if host is not set
-	baseURL := fmt.Sprintf("http://iotronic-conductor.default.svc.cluster.local:%s", c.service.S4tClient.Port)
+	baseURL := fmt.Sprintf("%s:%s", c.service.S4tClient.Endpoint, c.service.S4tClient.Port)
 	url := fmt.Sprintf("%s/v1%s", baseURL, path)
 	
 	var reqBody io.Reader
@@ -250,36 +206,86 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 
 	// Try to get port via REST API
 	if cr.Spec.ForProvider.Uuid == "" {
-		return managed.ExternalObservation{ResourceExists: false}, nil
+		return managed.ExternalObservation{ResourceExists: false}, c.releaseIfDeleted(ctx, cr)
 	}
 
-	resp, err := c.makeRESTCall("GET", fmt.Sprintf("/ports/%s", cr.Spec.ForProvider.Uuid), nil)
+	port, err := c.getPort(cr.Spec.ForProvider.Uuid)
 	if err != nil {
-		log.Printf("Error getting port: %v", err)
 		return managed.ExternalObservation{}, err
 	}
+	if port == nil {
+		return managed.ExternalObservation{ResourceExists: false}, c.releaseIfDeleted(ctx, cr)
+	}
+	d := diff.Compute(portFields(cr.Spec.ForProvider), port)
+
+	cr.Status.AtProvider.Uuid = cr.Spec.ForProvider.Uuid
+	if ip, ok := port["ip"].(string); ok && ip != "" {
+		cr.Status.AtProvider.Ip = ip
+	}
+
+	cr.Status.SetConditions(xpv1.Available())
+
+	return managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: d.Empty(),
+		ConnectionDetails: managed.ConnectionDetails{},
+	}, nil
+}
+
+// releaseIfDeleted releases the address of a Port that is being deleted
+// before its IoTronic port was created, since Delete is not called for it.
+func (c *external) releaseIfDeleted(ctx context.Context, cr *v1alpha1.Port) error {
+	if !meta.WasDeleted(cr) {
+		return nil
+	}
+	return errors.Wrap(ipam.Release(ctx, c.kube, cr), errRelease)
+}
+
+// getPort returns the port as IoTronic reports it, or nil
+// if it does not exist.
+// API: GET /v1/ports/{uuid}
+func (c *external) getPort(uuid string) (map[string]interface{}, error) {
+	resp, err := c.makeRESTCall("GET", fmt.Sprintf("/ports/%s", uuid), nil)
+	if err != nil {
+		log.Printf("Error getting port: %v", err)
+		return nil, err
+	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusNotFound {
-		return managed.ExternalObservation{ResourceExists: false}, nil
+		return nil, nil
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return managed.ExternalObservation{}, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
 	}
 
 	var port map[string]interface{}
 	if err := json.NewDecoder(resp.Body).Decode(&port); err != nil {
-		return managed.ExternalObservation{}, errors.Wrap(err, "failed to decode response")
+		return nil, errors.Wrap(err, "failed to decode response")
 	}
+	return port, nil
+}
 
-	cr.Status.SetConditions(xpv1.Available())
+// portFields maps PortParameters to IoTronic port attributes.
+func portFields(p v1alpha1.PortParameters) []diff.Field {
+	return []diff.Field{
+		{Name: "network", Desired: p.Network, IgnoreZero: true},
+		{Name: "MAC_add", Desired: p.MacAdd, IgnoreZero: true},
+		{Name: "VIF_name", Desired: p.VifName, IgnoreZero: true},
+		{Name: "ip", Desired: p.Ip, IgnoreZero: true},
+	}
+}
 
-	return managed.ExternalObservation{
-		ResourceExists:   true,
-		ResourceUpToDate: true,
-		ConnectionDetails: managed.ConnectionDetails{},
-	}, nil
+// addressChanged reports whether d changes the port's address or the network
+// it is allocated from.
+func addressChanged(d diff.Diff) bool {
+	for _, c := range d {
+		if c.Field == "ip" || c.Field == "network" {
+			return true
+		}
+	}
+	return false
 }
 
 func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
@@ -290,6 +296,18 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 
 	fmt.Printf("Creating Port: %+v", cr)
 
+	// Allocate the address before creating the port so that it is never
+	// handed out twice. A failed create keeps the allocation for the retry.
+	ip, pool, err := ipam.Allocate(ctx, c.kube, cr)
+	if err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errAllocate)
+	}
+	if ip != "" {
+		cr.Spec.ForProvider.Ip = ip
+		cr.Status.AtProvider.Ip = ip
+		cr.Status.AtProvider.IPPool = pool
+	}
+
 	portData := map[string]interface{}{
 		"board_uuid": cr.Spec.ForProvider.BoardUuid,
 		"network":    cr.Spec.ForProvider.Network,
@@ -341,21 +359,35 @@ func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 
 	fmt.Printf("Updating Port: %+v", cr)
 
-	portData := map[string]interface{}{}
-	if cr.Spec.ForProvider.Network != "" {
-		portData["network"] = cr.Spec.ForProvider.Network
+	observed, err := c.getPort(cr.Spec.ForProvider.Uuid)
+	if err != nil {
+		return managed.ExternalUpdate{}, err
 	}
-	if cr.Spec.ForProvider.MacAdd != "" {
-		portData["MAC_add"] = cr.Spec.ForProvider.MacAdd
+	d := diff.Compute(portFields(cr.Spec.ForProvider), observed)
+	if d.Empty() {
+		return managed.ExternalUpdate{ConnectionDetails: managed.ConnectionDetails{}}, nil
 	}
-	if cr.Spec.ForProvider.VifName != "" {
-		portData["VIF_name"] = cr.Spec.ForProvider.VifName
+
+	// A new address or network must be reserved in the pool serving the
+	// network, and the old address released, before IoTronic is told. As in
+	// Create, a failed update keeps the allocation for the retry.
+	if addressChanged(d) {
+		ip, pool, err := ipam.Reallocate(ctx, c.kube, cr)
+		if err != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, errAllocate)
+		}
+		if ip != "" {
+			cr.Spec.ForProvider.Ip = ip
+			cr.Status.AtProvider.Ip = ip
+		}
+		cr.Status.AtProvider.IPPool = pool
+		d = diff.Compute(portFields(cr.Spec.ForProvider), observed)
 	}
-	if cr.Spec.ForProvider.Ip != "" {
-		portData["ip"] = cr.Spec.ForProvider.Ip
+	if c.recorder != nil {
+		c.recorder.Event(cr, event.Normal(reasonDrift, "Updating drifted fields: "+d.String()))
 	}
 
-	resp, err := c.makeRESTCall("PATCH", fmt.Sprintf("/ports/%s", cr.Spec.ForProvider.Uuid), portData)
+	resp, err := c.makeRESTCall("PATCH", fmt.Sprintf("/ports/%s", cr.Spec.ForProvider.Uuid), d.Patch())
 	if err != nil {
 		log.Printf("Error updating port: %v", err)
 		return managed.ExternalUpdate{}, err
@@ -390,6 +422,6 @@ func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
 		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
 	}
 
-	return nil
+	return errors.Wrap(ipam.Release(ctx, c.kube, cr), errRelease)
 }
 
@@ -0,0 +1,222 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package request
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlevent "sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/provider-s4t/apis/iot/v1alpha1"
+)
+
+const (
+	errListRequests = "cannot list Requests"
+	errGetResults   = "cannot get request results"
+
+	// IoTronic request statuses.
+	statusCompleted = "COMPLETED"
+
+	// IoTronic results that mean a board failed, and that it has not
+	// answered yet.
+	resultError   = "ERROR"
+	resultRunning = "RUNNING"
+
+	// maxResults bounds the results recorded in a Request's status.
+	maxResults = 100
+
+	// pendingPollInterval is how often unfinished Requests are observed.
+	pendingPollInterval = 5 * time.Second
+)
+
+// phase derives the lifecycle phase of a request from its IoTronic status,
+// its per-board results and its children, and explains it.
+func phase(status string, results []v1alpha1.RequestResult, children *v1alpha1.RequestChildren, timedOut bool) (v1alpha1.RequestPhase, string) {
+	failed, answered := 0, 0
+	for _, r := range results {
+		switch {
+		case strings.EqualFold(r.Result, resultError):
+			failed++
+			answered++
+		case !strings.EqualFold(r.Result, resultRunning):
+			answered++
+		}
+	}
+	ch := v1alpha1.RequestChildren{}
+	if children != nil {
+		ch = *children
+	}
+
+	switch {
+	case ch.Failed+ch.TimedOut > 0:
+		return v1alpha1.RequestFailed, fmt.Sprintf("%d of %d child requests failed or timed out", ch.Failed+ch.TimedOut, ch.Total)
+	case strings.EqualFold(status, statusCompleted) && failed > 0:
+		return v1alpha1.RequestFailed, fmt.Sprintf("%d of %d boards reported an error", failed, len(results))
+	case strings.EqualFold(status, statusCompleted) && ch.Succeeded == ch.Total:
+		return v1alpha1.RequestSucceeded, ""
+	case timedOut:
+		return v1alpha1.RequestTimedOut, "request did not finish within its deadline"
+	case strings.EqualFold(status, statusCompleted):
+		return v1alpha1.RequestRunning, fmt.Sprintf("waiting for %d of %d child requests", ch.Total-ch.Succeeded, ch.Total)
+	case answered > 0 || ch.Running+ch.Succeeded > 0:
+		return v1alpha1.RequestRunning, fmt.Sprintf("%d boards answered", answered)
+	}
+	return v1alpha1.RequestPending, "waiting for the first result"
+}
+
+// timedOut reports whether the request ran past its deadline.
+func timedOut(cr *v1alpha1.Request, now time.Time) bool {
+	d := cr.Spec.ForProvider.ActiveDeadlineSeconds
+	start := cr.Status.AtProvider.StartTime
+	return d != nil && start != nil && now.After(start.Add(time.Duration(*d)*time.Second))
+}
+
+// getResults returns the per-board results of a request.
+// API: GET /v1/requests/{uuid}/results
+func (c *external) getResults(uuid string) ([]v1alpha1.RequestResult, error) {
+	resp, err := c.makeRESTCall("GET", fmt.Sprintf("/requests/%s/results", uuid), nil)
+	if err != nil {
+		log.Printf("Error getting request results: %v", err)
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	// IoTronic wraps collections in an object named after them.
+	var body json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, errors.Wrap(err, "failed to decode response")
+	}
+	var items []map[string]interface{}
+	if err := json.Unmarshal(body, &items); err != nil {
+		var collection struct {
+			Results []map[string]interface{} `json:"results"`
+		}
+		if err := json.Unmarshal(body, &collection); err != nil {
+			return nil, errors.Wrap(err, "failed to decode response")
+		}
+		items = collection.Results
+	}
+
+	results := make([]v1alpha1.RequestResult, 0, len(items))
+	for _, i := range items {
+		r := v1alpha1.RequestResult{}
+		r.BoardUuid, _ = i["board_uuid"].(string)
+		r.Result, _ = i["result"].(string)
+		r.Message, _ = i["message"].(string)
+		results = append(results, r)
+	}
+	return results, nil
+}
+
+// children counts the Requests whose mainRequestUuid is uuid by phase.
+func (c *external) children(ctx context.Context, uuid string) (*v1alpha1.RequestChildren, error) {
+	l := &v1alpha1.RequestList{}
+	if err := c.kube.List(ctx, l); err != nil {
+		return nil, errors.Wrap(err, errListRequests)
+	}
+	ch := &v1alpha1.RequestChildren{}
+	for _, r := range l.Items {
+		if r.Spec.ForProvider.MainRequestUuid != uuid {
+			continue
+		}
+		ch.Total++
+		switch r.Status.AtProvider.Phase {
+		case v1alpha1.RequestRunning:
+			ch.Running++
+		case v1alpha1.RequestSucceeded:
+			ch.Succeeded++
+		case v1alpha1.RequestFailed:
+			ch.Failed++
+		case v1alpha1.RequestTimedOut:
+			ch.TimedOut++
+		default:
+			ch.Pending++
+		}
+	}
+	if ch.Total == 0 {
+		return nil, nil
+	}
+	return ch, nil
+}
+
+// pollInterval observes unfinished Requests more often than finished ones.
+func pollInterval(mg resource.Managed, d time.Duration) time.Duration {
+	if cr, ok := mg.(*v1alpha1.Request); ok && !cr.Status.AtProvider.Phase.Finished() && d > pendingPollInterval {
+		return pendingPollInterval
+	}
+	return d
+}
+
+// parentsForChild maps a Request to its parent, so that the parent's
+// children are rolled up as soon as a child changes phase.
+func parentsForChild(kube client.Client) handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		r, ok := obj.(*v1alpha1.Request)
+		if !ok || r.Spec.ForProvider.MainRequestUuid == "" {
+			return nil
+		}
+		l := &v1alpha1.RequestList{}
+		if err := kube.List(ctx, l); err != nil {
+			log.Printf("Error listing Requests for Request %s: %v", r.GetName(), err)
+			return nil
+		}
+		var reqs []reconcile.Request
+		for _, p := range l.Items {
+			if p.Spec.ForProvider.Uuid == r.Spec.ForProvider.MainRequestUuid {
+				reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{Name: p.GetName()}})
+			}
+		}
+		return reqs
+	}
+}
+
+// phaseChanged passes child Requests being created, deleted or changing
+// phase.
+var phaseChanged = predicate.Funcs{
+	GenericFunc: func(ctrlevent.GenericEvent) bool { return false },
+	UpdateFunc: func(e ctrlevent.UpdateEvent) bool {
+		o, ok := e.ObjectOld.(*v1alpha1.Request)
+		if !ok {
+			return false
+		}
+		n, ok := e.ObjectNew.(*v1alpha1.Request)
+		if !ok {
+			return false
+		}
+		return o.Status.AtProvider.Phase != n.Status.AtProvider.Phase
+	},
+}
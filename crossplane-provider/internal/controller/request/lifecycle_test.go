@@ -0,0 +1,55 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package request
+
+import (
+	"testing"
+
+	"github.com/crossplane/provider-s4t/apis/iot/v1alpha1"
+)
+
+func TestPhase(t *testing.T) {
+	ok := v1alpha1.RequestResult{BoardUuid: "a", Result: "SUCCESS"}
+	ko := v1alpha1.RequestResult{BoardUuid: "b", Result: "ERROR"}
+	running := v1alpha1.RequestResult{BoardUuid: "c", Result: "RUNNING"}
+
+	cases := map[string]struct {
+		status   string
+		results  []v1alpha1.RequestResult
+		children *v1alpha1.RequestChildren
+		timedOut bool
+		want     v1alpha1.RequestPhase
+	}{
+		"NoResults":         {status: "PENDING", want: v1alpha1.RequestPending},
+		"OnlyRunning":       {status: "PENDING", results: []v1alpha1.RequestResult{running}, want: v1alpha1.RequestPending},
+		"SomeAnswered":      {status: "PENDING", results: []v1alpha1.RequestResult{ok, running}, want: v1alpha1.RequestRunning},
+		"Completed":         {status: "COMPLETED", results: []v1alpha1.RequestResult{ok}, want: v1alpha1.RequestSucceeded},
+		"CompletedWithErr":  {status: "COMPLETED", results: []v1alpha1.RequestResult{ok, ko}, want: v1alpha1.RequestFailed},
+		"PastDeadline":      {status: "PENDING", results: []v1alpha1.RequestResult{ok}, timedOut: true, want: v1alpha1.RequestTimedOut},
+		"CompletedInTime":   {status: "COMPLETED", timedOut: true, want: v1alpha1.RequestSucceeded},
+		"ChildrenRunning":   {status: "COMPLETED", children: &v1alpha1.RequestChildren{Total: 2, Succeeded: 1, Running: 1}, want: v1alpha1.RequestRunning},
+		"ChildrenSucceeded": {status: "COMPLETED", children: &v1alpha1.RequestChildren{Total: 2, Succeeded: 2}, want: v1alpha1.RequestSucceeded},
+		"ChildFailed":       {status: "PENDING", children: &v1alpha1.RequestChildren{Total: 2, Running: 1, TimedOut: 1}, want: v1alpha1.RequestFailed},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got, _ := phase(tc.status, tc.results, tc.children, tc.timedOut); got != tc.want {
+				t.Errorf("phase(): want %s, got %s", tc.want, got)
+			}
+		})
+	}
+}
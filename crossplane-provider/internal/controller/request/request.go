@@ -24,28 +24,30 @@ import (
 	"io"
 	"log"
 	"net/http"
-	"os"
-	"strings"
 	"time"
 
 	s4t "github.com/MIKE9708/s4t-sdk-go/pkg/api"
-	read_config "github.com/MIKE9708/s4t-sdk-go/pkg/read_conf"
 
-	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/connection"
 	"github.com/crossplane/crossplane-runtime/pkg/controller"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 
 	"github.com/crossplane/provider-s4t/apis/iot/v1alpha1"
 	apisv1alpha1 "github.com/crossplane/provider-s4t/apis/v1alpha1"
+	"github.com/crossplane/provider-s4t/internal/diff"
 	"github.com/crossplane/provider-s4t/internal/features"
+	"github.com/crossplane/provider-s4t/internal/maintenance"
+	"github.com/crossplane/provider-s4t/internal/providerconfig"
 )
 
 const (
@@ -54,6 +56,8 @@ const (
 	errGetPC        = "cannot get ProviderConfig"
 	errGetCreds     = "cannot get credentials"
 	errNewClient    = "cannot create new Service"
+
+	reasonDrift event.Reason = "DriftDetected"
 )
 
 type S4TService struct {
@@ -64,69 +68,11 @@ type S4TService struct {
 
 var (
 	newS4TService = func(creds []byte, keystoneEndpoint string) (*S4TService, error) {
-		var result map[string]string
-		err := json.Unmarshal(creds, &result)
+		c, err := providerconfig.Connect(creds, keystoneEndpoint)
 		if err != nil {
 			return nil, errors.Wrap(err, errNewClient)
 		}
-		auth_req := read_config.FormatAuthRequ(
-			result["username"],
-			result["password"],
-			result["domain"],
-		)
-		
-		// Set Keystone endpoint via environment variable (OS_AUTH_URL)
-		if keystoneEndpoint != "" {
-			os.Setenv("OS_AUTH_URL", keystoneEndpoint)
-		} else {
-			os.Setenv("OS_AUTH_URL", "http://keystone.default.svc.cluster.local:5000/v3")
-		}
-		// Extract host from keystoneEndpoint BEFORE creating client
-		// SDK uses hardcoded 127.0.0.1, we need to use Kubernetes service
-		endpoint := keystoneEndpoint
-		if endpoint == "" {
-			endpoint = "http://keystone.default.svc.cluster.local:5000/v3"
-		}
-		if strings.HasSuffix(endpoint, "/v3") {
-			endpoint = strings.TrimSuffix(endpoint, "/v3")
-		}
-		scheme := "http://"
-		if strings.HasPrefix(endpoint, "https://") {
-			scheme = "https://"
-			endpoint = strings.TrimPrefix(endpoint, "https://")
-		} else if strings.HasPrefix(endpoint, "http://") {
-			endpoint = strings.TrimPrefix(endpoint, "http://")
-		}
-		if idx := strings.Index(endpoint, ":"); idx != -1 {
-			endpoint = endpoint[:idx]
-		}
-		keystoneHost := scheme + endpoint
-		
-		// Create client manually with correct host (without port) instead of using GetClientConnection
-		// SDK will derive final URL using AuthPort
-		s4t_client := s4t.NewClient(keystoneHost)
-		s4t_client.Port = "8812"
-		s4t_client.AuthPort = "5000"
-		
-		// Authenticate with correct endpoint
-		token, err := s4t_client.Authenticate(s4t_client, auth_req)
-		if err != nil {
-			return nil, errors.Wrap(err, errNewClient)
-		}
-		s4t_client.AuthToken = token
-		
-		iotronicHost := scheme + "iotronic-conductor.default.svc.cluster.local"
-		s4t_client.Endpoint = iotronicHost
-		
-		if err != nil {
-			return nil, errors.Wrap(err, errNewClient)
-		}
-		// Extract base URL and token from client if available
-		// For now, we'll use default conductor endpoint
-		return &S4TService{
-			S4tClient: s4t_client,
-			BaseURL:   "http://iotronic-conductor:8812", // Default conductor endpoint
-		}, nil
+		return &S4TService{S4tClient: c}, nil
 	}
 )
 
@@ -139,28 +85,38 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), apisv1alpha1.StoreConfigGroupVersionKind))
 	}
 
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
+
 	r := managed.NewReconciler(mgr,
 		resource.ManagedKind(v1alpha1.RequestGroupVersionKind),
 		managed.WithExternalConnecter(&connector{
 			kube:         mgr.GetClient(),
-			usage:        resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
+			usage:        providerconfig.NewTracker(mgr.GetClient()),
+			recorder:     recorder,
 			newServiceFn: newS4TService}),
 		managed.WithLogger(o.Logger.WithValues("controller", name)),
 		managed.WithPollInterval(o.PollInterval),
-		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		managed.WithPollIntervalHook(pollInterval),
+		managed.WithRecorder(recorder),
 		managed.WithConnectionPublishers(cps...))
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
 		WithOptions(o.ForControllerRuntime()).
-		WithEventFilter(resource.DesiredStateChanged()).
-		For(&v1alpha1.Request{}).
+		For(&v1alpha1.Request{}, builder.WithPredicates(resource.DesiredStateChanged())).
+		Watches(&v1alpha1.Request{},
+			handler.EnqueueRequestsFromMapFunc(parentsForChild(mgr.GetClient())),
+			builder.WithPredicates(phaseChanged)).
+		Watches(&v1alpha1.MaintenanceWindow{},
+			handler.EnqueueRequestsFromMapFunc(maintenance.Waiting(mgr.GetClient(), func() resource.ManagedList { return &v1alpha1.RequestList{} })),
+			builder.WithPredicates(maintenance.Opened)).
 		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
 }
 
 type connector struct {
 	kube         client.Client
 	usage        resource.Tracker
+	recorder     event.Recorder
 	newServiceFn func(creds []byte, keystoneEndpoint string) (*S4TService, error)
 }
 
@@ -174,8 +130,8 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 		return nil, errors.Wrap(err, errTrackPCUsage)
 	}
 
-	pc_domain := &apisv1alpha1.ProviderConfig{}
-	if err := c.kube.Get(ctx, types.NamespacedName{Name: "s4t-provider-domain"}, pc_domain); err != nil {
+	pc_domain, err := providerconfig.Resolve(ctx, c.kube, mg)
+	if err != nil {
 		return nil, errors.Wrap(err, errGetPC)
 	}
 	cd_domain := pc_domain.Spec.Credentials
@@ -194,7 +150,10 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 	if err != nil {
 		return nil, errors.Wrap(err, errNewClient)
 	}
-	return &external{service: svc}, err
+	if err := providerconfig.Configure(svc.S4tClient, pc_domain); err != nil {
+		return nil, errors.Wrap(err, errNewClient)
+	}
+	return &external{service: svc, kube: c.kube, recorder: c.recorder}, err
 }
 
 // An ExternalClient observes, then either creates, updates, or deletes an
@@ -202,13 +161,15 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 type external struct {
 	// A 'client' used to connect to the external resource API. In practice this
 	// would be something like an AWS SDK client.
-	service *S4TService
+	service  *S4TService
+	kube     client.Client
+	recorder event.Recorder
 }
 
 // makeRESTCall makes a REST API call to the IoTronic service
 func (c *external) makeRESTCall(method, path string, data interface{}) (*http.Response, error) {
 	// Build URL using the service client's endpoint
-	baseURL := fmt.Sprintf("http://iotronic-conductor.default.svc.cluster.local:%s", c.service.S4tClient.Port)
+	baseURL := fmt.Sprintf("%s:%s", c.service.S4tClient.Endpoint, c.service.S4tClient.Port)
 	url := fmt.Sprintf("%s/v1%s", baseURL, path)
 	
 	var reqBody io.Reader
@@ -249,36 +210,116 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 
 	// Try to get request via REST API
 	if cr.Spec.ForProvider.Uuid == "" {
+		// A request acts on its destination board, so it is only sent once
+		// the board's maintenance window opens.
+		if !meta.WasDeleted(cr) {
+			deferred, err := maintenance.Check(ctx, c.kube, cr, cr.Spec.ForProvider.DestinationUuid)
+			if err != nil {
+				return managed.ExternalObservation{}, err
+			}
+			if deferred != nil {
+				cr.Status.SetConditions(deferred.Condition())
+				return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, nil
+			}
+		}
 		return managed.ExternalObservation{ResourceExists: false}, nil
 	}
 
-	resp, err := c.makeRESTCall("GET", fmt.Sprintf("/requests/%s", cr.Spec.ForProvider.Uuid), nil)
+	request, err := c.getRequest(cr.Spec.ForProvider.Uuid)
 	if err != nil {
-		log.Printf("Error getting request: %v", err)
 		return managed.ExternalObservation{}, err
 	}
+	if request == nil {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	d := diff.Compute(requestFields(cr.Spec.ForProvider), request)
+
+	obs := &cr.Status.AtProvider
+	obs.Uuid = cr.Spec.ForProvider.Uuid
+	obs.Status, _ = request["status"].(string)
+	if n, ok := request["pending_requests"].(float64); ok {
+		obs.PendingRequests = int(n)
+	}
+	if obs.StartTime == nil {
+		start := cr.GetCreationTimestamp()
+		obs.StartTime = &start
+	}
+
+	// Finished phases are final. Until then the phase follows the request's
+	// results and children, whose results are recorded once it finishes.
+	if !obs.Phase.Finished() {
+		results, err := c.getResults(obs.Uuid)
+		if err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, errGetResults)
+		}
+		children, err := c.children(ctx, obs.Uuid)
+		if err != nil {
+			return managed.ExternalObservation{}, err
+		}
+		obs.Children = children
+
+		p, msg := phase(obs.Status, results, children, timedOut(cr, time.Now()))
+		obs.Phase = p
+		cr.Status.SetConditions(v1alpha1.RequestCondition(p, msg))
+		if p.Finished() {
+			now := metav1.Now()
+			obs.CompletionTime = &now
+			if len(results) > maxResults {
+				results = results[:maxResults]
+			}
+			obs.Results = results
+			c.finished(cr, msg)
+		}
+	}
+
+	return managed.ExternalObservation{
+		ResourceExists:    true,
+		ResourceUpToDate:  d.Empty(),
+		ConnectionDetails: managed.ConnectionDetails{},
+	}, nil
+}
+
+// finished records that the request reached a terminal phase.
+func (c *external) finished(cr *v1alpha1.Request, msg string) {
+	p := cr.Status.AtProvider.Phase
+	if p == v1alpha1.RequestSucceeded {
+		c.recorder.Event(cr, event.Normal(event.Reason(p), "Request completed"))
+		return
+	}
+	c.recorder.Event(cr, event.Warning(event.Reason(p), errors.New(msg)))
+}
+
+// getRequest returns the request as IoTronic reports it, or nil
+// if it does not exist.
+// API: GET /v1/requests/{uuid}
+func (c *external) getRequest(uuid string) (map[string]interface{}, error) {
+	resp, err := c.makeRESTCall("GET", fmt.Sprintf("/requests/%s", uuid), nil)
+	if err != nil {
+		log.Printf("Error getting request: %v", err)
+		return nil, err
+	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusNotFound {
-		return managed.ExternalObservation{ResourceExists: false}, nil
+		return nil, nil
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return managed.ExternalObservation{}, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
 	}
 
 	var request map[string]interface{}
 	if err := json.NewDecoder(resp.Body).Decode(&request); err != nil {
-		return managed.ExternalObservation{}, errors.Wrap(err, "failed to decode response")
+		return nil, errors.Wrap(err, "failed to decode response")
 	}
+	return request, nil
+}
 
-	cr.Status.SetConditions(xpv1.Available())
-
-	return managed.ExternalObservation{
-		ResourceExists:   true,
-		ResourceUpToDate: true,
-		ConnectionDetails: managed.ConnectionDetails{},
-	}, nil
+// requestFields maps RequestParameters to IoTronic request attributes.
+func requestFields(p v1alpha1.RequestParameters) []diff.Field {
+	return []diff.Field{
+		{Name: "action", Desired: p.Action, IgnoreZero: true},
+	}
 }
 
 func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
@@ -322,6 +363,9 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 		cr.Spec.ForProvider.Uuid = uuid
 		cr.Status.AtProvider.Uuid = uuid
 	}
+	now := metav1.Now()
+	cr.Status.AtProvider.StartTime = &now
+	cr.Status.AtProvider.Phase = v1alpha1.RequestPending
 
 	return managed.ExternalCreation{
 		ConnectionDetails: managed.ConnectionDetails{},
@@ -336,15 +380,19 @@ func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 
 	fmt.Printf("Updating Request: %+v", cr)
 
-	requestData := map[string]interface{}{}
-	if cr.Spec.ForProvider.Action != "" {
-		requestData["action"] = cr.Spec.ForProvider.Action
+	observed, err := c.getRequest(cr.Spec.ForProvider.Uuid)
+	if err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+	d := diff.Compute(requestFields(cr.Spec.ForProvider), observed)
+	if d.Empty() {
+		return managed.ExternalUpdate{ConnectionDetails: managed.ConnectionDetails{}}, nil
 	}
-	if cr.Spec.ForProvider.Status != "" {
-		requestData["status"] = cr.Spec.ForProvider.Status
+	if c.recorder != nil {
+		c.recorder.Event(cr, event.Normal(reasonDrift, "Updating drifted fields: "+d.String()))
 	}
 
-	resp, err := c.makeRESTCall("PATCH", fmt.Sprintf("/requests/%s", cr.Spec.ForProvider.Uuid), requestData)
+	resp, err := c.makeRESTCall("PATCH", fmt.Sprintf("/requests/%s", cr.Spec.ForProvider.Uuid), d.Patch())
 	if err != nil {
 		log.Printf("Error updating request: %v", err)
 		return managed.ExternalUpdate{}, err
@@ -0,0 +1,152 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package result
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/provider-s4t/apis/iot/v1alpha1"
+)
+
+// IoTronic result outcomes.
+const (
+	resultSuccess = "SUCCESS"
+	resultError   = "ERROR"
+	resultWarning = "WARNING"
+	resultRunning = "RUNNING"
+
+	defaultMaxResults = 100
+)
+
+// timestampLayouts are the formats IoTronic reports timestamps in.
+var timestampLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999", "2006-01-02 15:04:05"}
+
+// decodeResults decodes a single result, a list of results, or a list
+// wrapped in an object named after it, as IoTronic wraps collections.
+func decodeResults(body []byte) ([]map[string]interface{}, error) {
+	var items []map[string]interface{}
+	if err := json.Unmarshal(body, &items); err == nil {
+		return items, nil
+	}
+	var obj map[string]interface{}
+	if err := json.Unmarshal(body, &obj); err != nil {
+		return nil, errors.Wrap(err, "failed to decode response")
+	}
+	if l, ok := obj["results"].([]interface{}); ok {
+		for _, i := range l {
+			if m, ok := i.(map[string]interface{}); ok {
+				items = append(items, m)
+			}
+		}
+		return items, nil
+	}
+	return []map[string]interface{}{obj}, nil
+}
+
+// entry converts an IoTronic result.
+func entry(i map[string]interface{}) v1alpha1.ResultEntry {
+	e := v1alpha1.ResultEntry{}
+	e.BoardUuid, _ = i["board_uuid"].(string)
+	e.Result, _ = i["result"].(string)
+	e.Message, _ = i["message"].(string)
+	for _, k := range []string{"updated_at", "created_at"} {
+		s, _ := i[k].(string)
+		for _, l := range timestampLayouts {
+			if t, err := time.Parse(l, s); err == nil {
+				e.Timestamp = &metav1.Time{Time: t}
+				return e
+			}
+		}
+	}
+	return e
+}
+
+// aggregate summarises results and lists at most limit of them, failed ones
+// first, then those with a warning, those still running and those that
+// succeeded, each by board.
+func aggregate(results []v1alpha1.ResultEntry, limit int) (v1alpha1.ResultSummary, []v1alpha1.ResultEntry, int) {
+	s := v1alpha1.ResultSummary{Total: len(results)}
+	for _, r := range results {
+		switch strings.ToUpper(r.Result) {
+		case resultSuccess:
+			s.Succeeded++
+		case resultError:
+			s.Failed++
+		case resultWarning:
+			s.Warning++
+		case resultRunning:
+			s.Running++
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		ri, rj := rank(results[i].Result), rank(results[j].Result)
+		if ri != rj {
+			return ri < rj
+		}
+		return results[i].BoardUuid < results[j].BoardUuid
+	})
+	if len(results) > limit {
+		return s, results[:limit], len(results) - limit
+	}
+	return s, results, 0
+}
+
+func rank(result string) int {
+	switch strings.ToUpper(result) {
+	case resultError:
+		return 0
+	case resultWarning:
+		return 1
+	case resultRunning:
+		return 2
+	}
+	return 3
+}
+
+// expiry returns when a finished Result is due for deletion, or the zero
+// time if it is not.
+func expiry(cr *v1alpha1.Result) time.Time {
+	ttl := cr.Spec.ForProvider.TTLSecondsAfterFinished
+	done := cr.Status.AtProvider.FinishedTime
+	if ttl == nil || done == nil {
+		return time.Time{}
+	}
+	return done.Add(time.Duration(*ttl) * time.Second)
+}
+
+// pollInterval observes finished Results again when they expire, if that is
+// before their next poll.
+func pollInterval(mg resource.Managed, d time.Duration) time.Duration {
+	cr, ok := mg.(*v1alpha1.Result)
+	if !ok {
+		return d
+	}
+	if e := expiry(cr); !e.IsZero() {
+		if until := time.Until(e); until < d {
+			return max(until, 0) + time.Second
+		}
+	}
+	return d
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package result
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/provider-s4t/apis/iot/v1alpha1"
+)
+
+func TestDecodeResults(t *testing.T) {
+	cases := map[string]struct {
+		body string
+		want int
+	}{
+		"List":       {body: `[{"board_uuid": "a"}, {"board_uuid": "b"}]`, want: 2},
+		"Collection": {body: `{"results": [{"board_uuid": "a"}]}`, want: 1},
+		"Single":     {body: `{"board_uuid": "a", "result": "SUCCESS"}`, want: 1},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := decodeResults([]byte(tc.body))
+			if err != nil {
+				t.Fatalf("decodeResults: %v", err)
+			}
+			if len(got) != tc.want {
+				t.Errorf("decodeResults: want %d results, got %d", tc.want, len(got))
+			}
+		})
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	results := []v1alpha1.ResultEntry{
+		{BoardUuid: "d", Result: "SUCCESS"},
+		{BoardUuid: "c", Result: "RUNNING"},
+		{BoardUuid: "b", Result: "ERROR"},
+		{BoardUuid: "a", Result: "SUCCESS"},
+		{BoardUuid: "e", Result: "WARNING"},
+	}
+	summary, listed, omitted := aggregate(results, 3)
+
+	want := v1alpha1.ResultSummary{Total: 5, Succeeded: 2, Failed: 1, Warning: 1, Running: 1}
+	if diff := cmp.Diff(want, summary); diff != "" {
+		t.Errorf("aggregate summary: -want, +got:\n%s", diff)
+	}
+	var boards []string
+	for _, r := range listed {
+		boards = append(boards, r.BoardUuid)
+	}
+	if diff := cmp.Diff([]string{"b", "e", "c"}, boards); diff != "" {
+		t.Errorf("aggregate listed: -want, +got:\n%s", diff)
+	}
+	if omitted != 2 {
+		t.Errorf("aggregate omitted: want 2, got %d", omitted)
+	}
+}
+
+func TestEntryTimestamp(t *testing.T) {
+	e := entry(map[string]interface{}{"board_uuid": "a", "updated_at": "2024-05-01T10:00:00.123456"})
+	if e.Timestamp == nil || e.Timestamp.Year() != 2024 {
+		t.Errorf("entry: want the updated_at timestamp, got %v", e.Timestamp)
+	}
+}
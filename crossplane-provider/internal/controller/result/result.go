@@ -24,12 +24,9 @@ import (
 	"io"
 	"log"
 	"net/http"
-	"os"
-	"strings"
 	"time"
 
 	s4t "github.com/MIKE9708/s4t-sdk-go/pkg/api"
-	read_config "github.com/MIKE9708/s4t-sdk-go/pkg/read_conf"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/connection"
@@ -38,14 +35,16 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/crossplane/provider-s4t/apis/iot/v1alpha1"
 	apisv1alpha1 "github.com/crossplane/provider-s4t/apis/v1alpha1"
 	"github.com/crossplane/provider-s4t/internal/features"
+	"github.com/crossplane/provider-s4t/internal/providerconfig"
 )
 
 const (
@@ -54,6 +53,9 @@ const (
 	errGetPC        = "cannot get ProviderConfig"
 	errGetCreds     = "cannot get credentials"
 	errNewClient    = "cannot create new Service"
+	errDeleteExpired = "cannot delete expired Result"
+
+	reasonExpired event.Reason = "Expired"
 )
 
 type S4TService struct {
@@ -64,69 +66,11 @@ type S4TService struct {
 
 var (
 	newS4TService = func(creds []byte, keystoneEndpoint string) (*S4TService, error) {
-		var result map[string]string
-		err := json.Unmarshal(creds, &result)
-		if err != nil {
-			return nil, errors.Wrap(err, errNewClient)
-		}
-		auth_req := read_config.FormatAuthRequ(
-			result["username"],
-			result["password"],
-			result["domain"],
-		)
-		
-		// Set Keystone endpoint via environment variable (OS_AUTH_URL)
-		if keystoneEndpoint != "" {
-			os.Setenv("OS_AUTH_URL", keystoneEndpoint)
-		} else {
-			os.Setenv("OS_AUTH_URL", "http://keystone.default.svc.cluster.local:5000/v3")
-		}
-		// Extract host from keystoneEndpoint BEFORE creating client
-		// SDK uses hardcoded 127.0.0.1, we need to use Kubernetes service
-		endpoint := keystoneEndpoint
-		if endpoint == "" {
-			endpoint = "http://keystone.default.svc.cluster.local:5000/v3"
-		}
-		if strings.HasSuffix(endpoint, "/v3") {
-			endpoint = strings.TrimSuffix(endpoint, "/v3")
-		}
-		scheme := "http://"
-		if strings.HasPrefix(endpoint, "https://") {
-			scheme = "https://"
-			endpoint = strings.TrimPrefix(endpoint, "https://")
-		} else if strings.HasPrefix(endpoint, "http://") {
-			endpoint = strings.TrimPrefix(endpoint, "http://")
-		}
-		if idx := strings.Index(endpoint, ":"); idx != -1 {
-			endpoint = endpoint[:idx]
-		}
-		keystoneHost := scheme + endpoint
-		
-		// Create client manually with correct host (without port) instead of using GetClientConnection
-		// SDK will derive final URL using AuthPort
-		s4t_client := s4t.NewClient(keystoneHost)
-		s4t_client.Port = "8812"
-		s4t_client.AuthPort = "5000"
-		
-		// Authenticate with correct endpoint
-		token, err := s4t_client.Authenticate(s4t_client, auth_req)
-		if err != nil {
-			return nil, errors.Wrap(err, errNewClient)
-		}
-		s4t_client.AuthToken = token
-		
-		iotronicHost := scheme + "iotronic-conductor.default.svc.cluster.local"
-		s4t_client.Endpoint = iotronicHost
-		
+		c, err := providerconfig.Connect(creds, keystoneEndpoint)
 		if err != nil {
 			return nil, errors.Wrap(err, errNewClient)
 		}
-		// Extract base URL and token from client if available
-		// For now, we'll use default conductor endpoint
-		return &S4TService{
-			S4tClient: s4t_client,
-			BaseURL:   "http://iotronic-conductor:8812", // Default conductor endpoint
-		}, nil
+		return &S4TService{S4tClient: c}, nil
 	}
 )
 
@@ -139,15 +83,19 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), apisv1alpha1.StoreConfigGroupVersionKind))
 	}
 
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
+
 	r := managed.NewReconciler(mgr,
 		resource.ManagedKind(v1alpha1.ResultGroupVersionKind),
 		managed.WithExternalConnecter(&connector{
 			kube:         mgr.GetClient(),
-			usage:        resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
+			usage:        providerconfig.NewTracker(mgr.GetClient()),
+			recorder:     recorder,
 			newServiceFn: newS4TService}),
 		managed.WithLogger(o.Logger.WithValues("controller", name)),
 		managed.WithPollInterval(o.PollInterval),
-		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		managed.WithPollIntervalHook(pollInterval),
+		managed.WithRecorder(recorder),
 		managed.WithConnectionPublishers(cps...))
 
 	return ctrl.NewControllerManagedBy(mgr).
@@ -161,6 +109,7 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 type connector struct {
 	kube         client.Client
 	usage        resource.Tracker
+	recorder     event.Recorder
 	newServiceFn func(creds []byte, keystoneEndpoint string) (*S4TService, error)
 }
 
@@ -174,8 +123,8 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 		return nil, errors.Wrap(err, errTrackPCUsage)
 	}
 
-	pc_domain := &apisv1alpha1.ProviderConfig{}
-	if err := c.kube.Get(ctx, types.NamespacedName{Name: "s4t-provider-domain"}, pc_domain); err != nil {
+	pc_domain, err := providerconfig.Resolve(ctx, c.kube, mg)
+	if err != nil {
 		return nil, errors.Wrap(err, errGetPC)
 	}
 	cd_domain := pc_domain.Spec.Credentials
@@ -194,7 +143,10 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 	if err != nil {
 		return nil, errors.Wrap(err, errNewClient)
 	}
-	return &external{service: svc}, err
+	if err := providerconfig.Configure(svc.S4tClient, pc_domain); err != nil {
+		return nil, errors.Wrap(err, errNewClient)
+	}
+	return &external{service: svc, kube: c.kube, recorder: c.recorder}, err
 }
 
 // An ExternalClient observes, then either creates, updates, or deletes an
@@ -202,13 +154,15 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 type external struct {
 	// A 'client' used to connect to the external resource API. In practice this
 	// would be something like an AWS SDK client.
-	service *S4TService
+	service  *S4TService
+	kube     client.Client
+	recorder event.Recorder
 }
 
 // makeRESTCall makes a REST API call to the IoTronic service
 func (c *external) makeRESTCall(method, path string, data interface{}) (*http.Response, error) {
 	// Build URL using the service client's endpoint
-	baseURL := fmt.Sprintf("http://iotronic-conductor.default.svc.cluster.local:%s", c.service.S4tClient.Port)
+	baseURL := fmt.Sprintf("%s:%s", c.service.S4tClient.Endpoint, c.service.S4tClient.Port)
 	url := fmt.Sprintf("%s/v1%s", baseURL, path)
 	
 	var reqBody io.Reader
@@ -247,6 +201,12 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 
 	fmt.Printf("Observing Result: %+v", cr)
 
+	// A Result whose records stay in IoTronic has nothing to delete, so it
+	// is reported gone as soon as it is deleted and Delete is not called.
+	if meta.WasDeleted(cr) && !deletesRecords(cr) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
 	// Results require request_uuid to fetch
 	if cr.Spec.ForProvider.RequestUuid == "" && cr.Spec.ForProvider.Uuid == "" {
 		return managed.ExternalObservation{ResourceExists: false}, nil
@@ -274,16 +234,52 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		return managed.ExternalObservation{}, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
 	}
 
-	var result map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return managed.ExternalObservation{}, errors.Wrap(err, "failed to decode response")
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, "failed to read response")
+	}
+	items, err := decodeResults(body)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+	entries := make([]v1alpha1.ResultEntry, 0, len(items))
+	for _, i := range items {
+		e := entry(i)
+		if b := cr.Spec.ForProvider.BoardUuid; b != "" && e.BoardUuid != b {
+			continue
+		}
+		entries = append(entries, e)
+	}
+
+	limit := defaultMaxResults
+	if m := cr.Spec.ForProvider.MaxResults; m != nil {
+		limit = *m
+	}
+	obs := &cr.Status.AtProvider
+	obs.Uuid = cr.Spec.ForProvider.Uuid
+	obs.BoardUuid = cr.Spec.ForProvider.BoardUuid
+	obs.RequestUuid = cr.Spec.ForProvider.RequestUuid
+	obs.Summary, obs.Results, obs.Omitted = aggregate(entries, limit)
+	switch {
+	case obs.Summary.Total == 0 || obs.Summary.Running > 0:
+		obs.FinishedTime = nil
+	case obs.FinishedTime == nil:
+		now := metav1.Now()
+		obs.FinishedTime = &now
 	}
 
 	cr.Status.SetConditions(xpv1.Available())
 
+	if e := expiry(cr); !e.IsZero() && !time.Now().Before(e) && !meta.WasDeleted(cr) {
+		c.recorder.Event(cr, event.Normal(reasonExpired, "Deleting Result after ttlSecondsAfterFinished"))
+		if err := c.kube.Delete(ctx, cr); resource.IgnoreNotFound(err) != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, errDeleteExpired)
+		}
+	}
+
 	return managed.ExternalObservation{
-		ResourceExists:   true,
-		ResourceUpToDate: true,
+		ResourceExists:    true,
+		ResourceUpToDate:  true,
 		ConnectionDetails: managed.ConnectionDetails{},
 	}, nil
 }
@@ -309,8 +305,41 @@ func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 	}, nil
 }
 
+// deletesRecords reports whether deleting cr deletes its results from
+// IoTronic. Results restricted to one board of a request are never deleted,
+// since IoTronic would delete those of every board.
+func deletesRecords(cr *v1alpha1.Result) bool {
+	p := cr.Spec.ForProvider
+	return p.DeleteRecords && (p.BoardUuid == "" || p.Uuid != "")
+}
+
+// Delete removes the results from IoTronic when deleteRecords is set. They
+// are otherwise left in place and only the Result is deleted.
+// API: DELETE /v1/results/{uuid} or DELETE /v1/requests/{uuid}/results
 func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
-	// Results are read-only, cannot be deleted
+	cr, ok := mg.(*v1alpha1.Result)
+	if !ok {
+		return errors.New(errNotResult)
+	}
+	if !deletesRecords(cr) {
+		return nil
+	}
+
+	fmt.Printf("Deleting Result: %+v", cr)
+
+	path := fmt.Sprintf("/requests/%s/results", cr.Spec.ForProvider.RequestUuid)
+	if cr.Spec.ForProvider.Uuid != "" {
+		path = fmt.Sprintf("/results/%s", cr.Spec.ForProvider.Uuid)
+	}
+	resp, err := c.makeRESTCall("DELETE", path, nil)
+	if err != nil {
+		log.Printf("Error deleting result: %v", err)
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
 	return nil
 }
-
@@ -0,0 +1,69 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package result
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/provider-s4t/apis/iot/v1alpha1"
+)
+
+func result(uuid, board string, deleteRecords bool) *v1alpha1.Result {
+	cr := &v1alpha1.Result{ObjectMeta: metav1.ObjectMeta{Name: "r"}}
+	cr.Spec.ForProvider = v1alpha1.ResultParameters{Uuid: uuid, BoardUuid: board, RequestUuid: "req", DeleteRecords: deleteRecords}
+	return cr
+}
+
+func TestDeletesRecords(t *testing.T) {
+	cases := map[string]struct {
+		cr   *v1alpha1.Result
+		want bool
+	}{
+		"Kept":         {cr: result("", "", false), want: false},
+		"Request":      {cr: result("", "", true), want: true},
+		"OneResult":    {cr: result("res", "board", true), want: true},
+		"OneBoard":     {cr: result("", "board", true), want: false},
+		"OneBoardKept": {cr: result("", "board", false), want: false},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, deletesRecords(tc.cr)); diff != "" {
+				t.Errorf("deletesRecords(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestObserveDeleted(t *testing.T) {
+	cr := result("", "", false)
+	cr.SetDeletionTimestamp(&metav1.Time{Time: time.Now()})
+
+	// A deleted Result keeping its records is gone without asking IoTronic.
+	got, err := (&external{}).Observe(context.Background(), cr)
+	if err != nil {
+		t.Fatalf("Observe(...): %v", err)
+	}
+	if diff := cmp.Diff(managed.ExternalObservation{ResourceExists: false}, got); diff != "" {
+		t.Errorf("Observe(...): -want, +got:\n%s", diff)
+	}
+}
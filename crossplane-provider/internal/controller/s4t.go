@@ -26,10 +26,15 @@ import (
 	"github.com/crossplane/provider-s4t/internal/controller/service"
 	"github.com/crossplane/provider-s4t/internal/controller/site"
 	"github.com/crossplane/provider-s4t/internal/controller/fleet"
+	"github.com/crossplane/provider-s4t/internal/controller/fleetinjection"
 	"github.com/crossplane/provider-s4t/internal/controller/webservice"
+	"github.com/crossplane/provider-s4t/internal/controller/network"
 	"github.com/crossplane/provider-s4t/internal/controller/port"
 	"github.com/crossplane/provider-s4t/internal/controller/result"
 	"github.com/crossplane/provider-s4t/internal/controller/request"
+	"github.com/crossplane/provider-s4t/internal/controller/pluginschedule"
+	"github.com/crossplane/provider-s4t/internal/controller/maintenancewindow"
+	"github.com/crossplane/provider-s4t/internal/controller/boardprofile"
 	ctrl "sigs.k8s.io/controller-runtime"
 )
 
@@ -45,10 +50,15 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		boardserviceinjection.Setup,
 		site.Setup,
 		fleet.Setup,
+		fleetinjection.Setup,
 		webservice.Setup,
+		network.Setup,
 		port.Setup,
 		result.Setup,
 		request.Setup,
+		pluginschedule.Setup,
+		maintenancewindow.Setup,
+		boardprofile.Setup,
 	} {
 		if err := setup(mgr, o); err != nil {
 			return err
@@ -17,20 +17,15 @@ limitations under the License.
 package service
 
 import (
-	"strings"
 	"context"
-	"encoding/json"
 	"fmt"
 	"log"
-	"os"
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/pkg/errors"
-	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	s4t "github.com/MIKE9708/s4t-sdk-go/pkg/api"
-	read_config "github.com/MIKE9708/s4t-sdk-go/pkg/read_conf"
 
 	services "github.com/MIKE9708/s4t-sdk-go/pkg/api/data/service"
 
@@ -43,7 +38,9 @@ import (
 
 	"github.com/crossplane/provider-s4t/apis/iot/v1alpha1"
 	apisv1alpha1 "github.com/crossplane/provider-s4t/apis/v1alpha1"
+	"github.com/crossplane/provider-s4t/internal/diff"
 	"github.com/crossplane/provider-s4t/internal/features"
+	"github.com/crossplane/provider-s4t/internal/providerconfig"
 )
 
 const (
@@ -53,6 +50,8 @@ const (
 	errGetCreds     = "cannot get credentials"
 
 	errNewClient = "cannot create new Service"
+
+	reasonDrift event.Reason = "DriftDetected"
 )
 
 type S4TService struct {
@@ -61,74 +60,11 @@ type S4TService struct {
 
 var (
 	newS4TService = func(creds []byte, keystoneEndpoint string) (*S4TService, error) {
-		var result map[string]string
-		err := json.Unmarshal(creds, &result)
+		c, err := providerconfig.Connect(creds, keystoneEndpoint)
 		if err != nil {
 			return nil, errors.Wrap(err, errNewClient)
 		}
-		
-		// Set Keystone endpoint via environment variable (OS_AUTH_URL)
-		if keystoneEndpoint != "" {
-			os.Setenv("OS_AUTH_URL", keystoneEndpoint)
-		} else {
-			os.Setenv("OS_AUTH_URL", "http://keystone.default.svc.cluster.local:5000/v3")
-		}
-		
-		// Also set other OpenStack env vars that SDK might need
-		os.Setenv("OS_IDENTITY_API_VERSION", "3")
-		
-		auth_req := read_config.FormatAuthRequ(
-			result["username"],
-			result["password"],
-			result["domain"],
-		)
-		// Ensure OS_AUTH_URL is still set (in case FormatAuthRequ cleared it)
-		if keystoneEndpoint != "" {
-			os.Setenv("OS_AUTH_URL", keystoneEndpoint)
-		} else {
-			os.Setenv("OS_AUTH_URL", "http://keystone.default.svc.cluster.local:5000/v3")
-		}
-		
-		// Extract host from keystoneEndpoint BEFORE creating client
-		// SDK uses hardcoded 127.0.0.1, we need to use Kubernetes service
-		endpoint := keystoneEndpoint
-		if endpoint == "" {
-			endpoint = "http://keystone.default.svc.cluster.local:5000/v3"
-		}
-		if strings.HasSuffix(endpoint, "/v3") {
-			endpoint = strings.TrimSuffix(endpoint, "/v3")
-		}
-		scheme := "http://"
-		if strings.HasPrefix(endpoint, "https://") {
-			scheme = "https://"
-			endpoint = strings.TrimPrefix(endpoint, "https://")
-		} else if strings.HasPrefix(endpoint, "http://") {
-			endpoint = strings.TrimPrefix(endpoint, "http://")
-		}
-		if idx := strings.Index(endpoint, ":"); idx != -1 {
-			endpoint = endpoint[:idx]
-		}
-		keystoneHost := scheme + endpoint
-		
-		// Create client manually with correct host (without port) instead of using GetClientConnection
-		// SDK will derive final URL using AuthPort
-		s4t_client := s4t.NewClient(keystoneHost)
-		s4t_client.Port = "8812"
-		s4t_client.AuthPort = "5000"
-		
-		// Authenticate with correct endpoint
-		token, err := s4t_client.Authenticate(s4t_client, auth_req)
-		if err != nil {
-			return nil, errors.Wrap(err, errNewClient)
-		}
-		s4t_client.AuthToken = token
-		
-		iotronicHost := scheme + "iotronic-conductor.default.svc.cluster.local"
-		s4t_client.Endpoint = iotronicHost
-		
-		return &S4TService{
-			S4tClient: s4t_client,
-		}, err
+		return &S4TService{S4tClient: c}, nil
 	}
 )
 
@@ -140,15 +76,18 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), apisv1alpha1.StoreConfigGroupVersionKind))
 	}
 
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
+
 	r := managed.NewReconciler(mgr,
 		resource.ManagedKind(v1alpha1.ServiceGroupVersionKind),
 		managed.WithExternalConnecter(&connector{
 			kube:         mgr.GetClient(),
-			usage:        resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
+			usage:        providerconfig.NewTracker(mgr.GetClient()),
+			recorder:     recorder,
 			newServiceFn: newS4TService}),
 		managed.WithLogger(o.Logger.WithValues("controller", name)),
 		managed.WithPollInterval(o.PollInterval),
-		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		managed.WithRecorder(recorder),
 		managed.WithConnectionPublishers(cps...))
 
 	return ctrl.NewControllerManagedBy(mgr).
@@ -162,6 +101,7 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 type connector struct {
 	kube         client.Client
 	usage        resource.Tracker
+	recorder     event.Recorder
 	newServiceFn func(creds []byte, keystoneEndpoint string) (*S4TService, error)
 }
 
@@ -174,9 +114,8 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 	if err := c.usage.Track(ctx, mg); err != nil {
 		return nil, errors.Wrap(err, errTrackPCUsage)
 	}
-	// cr.GetProviderConfigReference().Name
-	pc_domain := &apisv1alpha1.ProviderConfig{}
-	if err := c.kube.Get(ctx, types.NamespacedName{Name: "s4t-provider-domain"}, pc_domain); err != nil {
+	pc_domain, err := providerconfig.Resolve(ctx, c.kube, mg)
+	if err != nil {
 		return nil, errors.Wrap(err, errGetPC)
 	}
 	cd_domain := pc_domain.Spec.Credentials
@@ -195,7 +134,10 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 	if err != nil {
 		return nil, errors.Wrap(err, errNewClient)
 	}
-	return &external{service: svc}, err
+	if err := providerconfig.Configure(svc.S4tClient, pc_domain); err != nil {
+		return nil, errors.Wrap(err, errNewClient)
+	}
+	return &external{service: svc, recorder: c.recorder}, err
 }
 
 // An ExternalClient observes, then either creates, updates, or deletes an
@@ -203,7 +145,8 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 type external struct {
 	// A 'client' used to connect to the external resource API. In practice this
 	// would be something like an AWS SDK client.
-	service *S4TService
+	service  *S4TService
+	recorder event.Recorder
 }
 
 func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
@@ -221,16 +164,37 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	if service.Uuid == "" {
 		return managed.ExternalObservation{ResourceExists: false}, nil
 	}
+	d := diff.Compute(serviceFields(cr.Spec.ForProvider), observedService(service))
 
 	cr.Status.SetConditions(xpv1.Available())
 
 	return managed.ExternalObservation{
 		ResourceExists:    true,
-		ResourceUpToDate:  true,
+		ResourceUpToDate:  d.Empty(),
 		ConnectionDetails: managed.ConnectionDetails{},
 	}, nil
 }
 
+// serviceFields maps ServiceParameters to IoTronic service attributes.
+func serviceFields(p v1alpha1.ServiceParameters) []diff.Field {
+	return []diff.Field{
+		{Name: "name", Desired: p.Name},
+		{Name: "port", Desired: p.Port},
+		{Name: "protocol", Desired: p.Protocol},
+		{Name: "project", Desired: p.Project, IgnoreZero: true},
+	}
+}
+
+// observedService returns the attributes of a service as the SDK reports it.
+func observedService(s *services.Service) map[string]interface{} {
+	return map[string]interface{}{
+		"name":     s.Name,
+		"port":     s.Port,
+		"protocol": s.Protocol,
+		"project":  s.Project,
+	}
+}
+
 func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
 	cr, ok := mg.(*v1alpha1.Service)
 	if !ok {
@@ -266,13 +230,20 @@ func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 	}
 
 	fmt.Printf("Updating: %+v", cr)
-	req := map[string]interface{}{
-		"name":     cr.Spec.ForProvider.Name,
-		"port":     cr.Spec.ForProvider.Port,
-		"protocol": cr.Spec.ForProvider.Protocol,
+	service, err := c.service.S4tClient.GetService(cr.Spec.ForProvider.Uuid)
+	if err != nil {
+		log.Printf("####ERROR-LOG#### Error s4t client Service Get %q", err)
+		return managed.ExternalUpdate{}, err
 	}
-	log.Printf("\n\n####ERROR-LOG########## \n\n%s\n\n", cr.Spec.ForProvider.Uuid)
-	_, err := c.service.S4tClient.PatchService(cr.Spec.ForProvider.Uuid, req)
+	d := diff.Compute(serviceFields(cr.Spec.ForProvider), observedService(service))
+	if d.Empty() {
+		return managed.ExternalUpdate{ConnectionDetails: managed.ConnectionDetails{}}, nil
+	}
+	if c.recorder != nil {
+		c.recorder.Event(cr, event.Normal(reasonDrift, "Updating drifted fields: "+d.String()))
+	}
+
+	_, err = c.service.S4tClient.PatchService(cr.Spec.ForProvider.Uuid, d.Patch())
 	if err != nil {
 		log.Printf("####ERROR-LOG#### Error s4t client Plugin Update %q", err)
 		return managed.ExternalUpdate{}, errors.New(errNewClient)
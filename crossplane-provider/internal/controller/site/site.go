@@ -17,25 +17,28 @@ limitations under the License.
 package site
 
 import (
-	"strings"
-	"os"
 	"context"
 	"fmt"
 	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
 
-	"encoding/json"
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-
-	s4t "github.com/MIKE9708/s4t-sdk-go/pkg/api"
-	read_config "github.com/MIKE9708/s4t-sdk-go/pkg/read_conf"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/crossplane/crossplane-runtime/pkg/connection"
 	"github.com/crossplane/crossplane-runtime/pkg/controller"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
@@ -43,83 +46,33 @@ import (
 	"github.com/crossplane/provider-s4t/apis/iot/v1alpha1"
 	apisv1alpha1 "github.com/crossplane/provider-s4t/apis/v1alpha1"
 	"github.com/crossplane/provider-s4t/internal/features"
+	"github.com/crossplane/provider-s4t/internal/providerconfig"
 )
 
 const (
 	errNotSite      = "managed resource is not a Site custom resource"
 	errTrackPCUsage = "cannot track ProviderConfig usage"
-	errGetPC        = "cannot get ProviderConfig"
-	errGetCreds     = "cannot get credentials"
-	errNewClient    = "cannot create new Service"
-)
+	errListSites    = "cannot list Sites"
+	errListDevices  = "cannot list Devices"
+	errNoParent     = "parent site %q does not exist"
+	errCycle        = "site hierarchy contains a cycle: %s"
+	errInUse        = "site is still referenced by %d devices and %d child sites"
 
-type S4TService struct {
-	S4tClient *s4t.Client
-}
+	// siteStatusActive is reported for a Site whose hierarchy is valid.
+	siteStatusActive = "active"
 
-var (
-	newS4TService = func(creds []byte, keystoneEndpoint string) (*S4TService, error) {
-		var result map[string]string
-		err := json.Unmarshal(creds, &result)
-		if err != nil {
-			return nil, errors.Wrap(err, errNewClient)
-		}
-		auth_req := read_config.FormatAuthRequ(
-			result["username"],
-			result["password"],
-			result["domain"],
-		)
-		
-		// Set Keystone endpoint via environment variable (OS_AUTH_URL)
-		if keystoneEndpoint != "" {
-			os.Setenv("OS_AUTH_URL", keystoneEndpoint)
-		} else {
-			os.Setenv("OS_AUTH_URL", "http://keystone.default.svc.cluster.local:5000/v3")
-		}
-		// Extract host from keystoneEndpoint BEFORE creating client
-		// SDK uses hardcoded 127.0.0.1, we need to use Kubernetes service
-		endpoint := keystoneEndpoint
-		if endpoint == "" {
-			endpoint = "http://keystone.default.svc.cluster.local:5000/v3"
-		}
-		if strings.HasSuffix(endpoint, "/v3") {
-			endpoint = strings.TrimSuffix(endpoint, "/v3")
-		}
-		scheme := "http://"
-		if strings.HasPrefix(endpoint, "https://") {
-			scheme = "https://"
-			endpoint = strings.TrimPrefix(endpoint, "https://")
-		} else if strings.HasPrefix(endpoint, "http://") {
-			endpoint = strings.TrimPrefix(endpoint, "http://")
-		}
-		if idx := strings.Index(endpoint, ":"); idx != -1 {
-			endpoint = endpoint[:idx]
-		}
-		keystoneHost := scheme + endpoint
-		
-		// Create client manually with correct host (without port) instead of using GetClientConnection
-		// SDK will derive final URL using AuthPort
-		s4t_client := s4t.NewClient(keystoneHost)
-		s4t_client.Port = "8812"
-		s4t_client.AuthPort = "5000"
-		
-		// Authenticate with correct endpoint
-		token, err := s4t_client.Authenticate(s4t_client, auth_req)
-		if err != nil {
-			return nil, errors.Wrap(err, errNewClient)
-		}
-		s4t_client.AuthToken = token
-		
-		iotronicHost := scheme + "iotronic-conductor.default.svc.cluster.local"
-		s4t_client.Endpoint = iotronicHost
-		
-		return &S4TService{
-			S4tClient: s4t_client,
-		}, err
-	}
+	// boardStatusOnline is the IoTronic status of a board whose Lightning Rod
+	// is connected.
+	boardStatusOnline = "online"
+
+	// probeTimeout bounds each request made to check a site's endpoints.
+	probeTimeout = 5 * time.Second
 )
 
-// Setup adds a controller that reconciles Site managed resources.
+// Setup adds a controller that reconciles Site managed resources. Sites have
+// no IoTronic counterpart; they are reconciled natively from the Sites and
+// the site-labelled Devices in the cluster. A Site's providerConfigRef selects
+// the Stack4Things deployment its Devices and their injections use.
 func Setup(mgr ctrl.Manager, o controller.Options) error {
 	name := managed.ControllerName(v1alpha1.SiteGroupKind)
 
@@ -131,9 +84,8 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 	r := managed.NewReconciler(mgr,
 		resource.ManagedKind(v1alpha1.SiteGroupVersionKind),
 		managed.WithExternalConnecter(&connector{
-			kube:         mgr.GetClient(),
-			usage:        resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
-			newServiceFn: newS4TService}),
+			kube:  mgr.GetClient(),
+			usage: providerconfig.NewTracker(mgr.GetClient())}),
 		managed.WithLogger(o.Logger.WithValues("controller", name)),
 		managed.WithPollInterval(o.PollInterval),
 		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
@@ -142,24 +94,70 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
 		WithOptions(o.ForControllerRuntime()).
-		WithEventFilter(resource.DesiredStateChanged()).
-		For(&v1alpha1.Site{}).
+		For(&v1alpha1.Site{}, builder.WithPredicates(resource.DesiredStateChanged())).
+		Watches(&v1alpha1.Site{}, handler.EnqueueRequestsFromMapFunc(ancestorsOfSite(mgr.GetClient()))).
+		Watches(&v1alpha1.Device{}, handler.EnqueueRequestsFromMapFunc(ancestorsOfDevice(mgr.GetClient()))).
 		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
 }
 
+// ancestorsOfSite maps a Site to its ancestors, whose counts and child lists
+// roll it up.
+func ancestorsOfSite(kube client.Client) handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		s, ok := obj.(*v1alpha1.Site)
+		if !ok {
+			return nil
+		}
+		return ancestors(ctx, kube, s.Spec.ForProvider.ParentSite)
+	}
+}
+
+// ancestorsOfDevice maps a Device to the site it is labelled with, the site
+// whose geofence contains it, and their ancestors. Updates map both the old
+// and the new Device, so a site a device leaves refreshes its counts too.
+func ancestorsOfDevice(kube client.Client) handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		reqs := ancestors(ctx, kube, obj.GetLabels()[v1alpha1.LabelSite])
+		if g := obj.GetLabels()[v1alpha1.LabelGeofenceSite]; g != "" {
+			reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{Name: g}})
+		}
+		return reqs
+	}
+}
+
+// ancestors returns requests for the named site and its ancestors.
+func ancestors(ctx context.Context, kube client.Client, name string) []reconcile.Request {
+	if name == "" {
+		return nil
+	}
+	l := &v1alpha1.SiteList{}
+	if err := kube.List(ctx, l); err != nil {
+		log.Printf("Error listing Sites: %v", err)
+		return nil
+	}
+	parents := map[string]string{}
+	for _, s := range l.Items {
+		parents[s.GetName()] = s.Spec.ForProvider.ParentSite
+	}
+
+	var reqs []reconcile.Request
+	seen := map[string]bool{}
+	for n := name; n != "" && !seen[n]; n = parents[n] {
+		seen[n] = true
+		reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{Name: n}})
+	}
+	return reqs
+}
+
 // A connector is expected to produce an ExternalClient when its Connect method
 // is called.
 type connector struct {
-	kube         client.Client
-	usage        resource.Tracker
-	newServiceFn func(creds []byte, keystoneEndpoint string) (*S4TService, error)
+	kube  client.Client
+	usage resource.Tracker
 }
 
-// Connect typically produces an ExternalClient by:
-// 1. Tracking that the managed resource is using a ProviderConfig.
-// 2. Getting the managed resource's ProviderConfig.
-// 3. Getting the credentials specified by the ProviderConfig.
-// 4. Using the credentials to form a client.
+// Connect only tracks ProviderConfig usage; a Site is reconciled against the
+// Kubernetes API.
 func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
 	_, ok := mg.(*v1alpha1.Site)
 	if !ok {
@@ -170,35 +168,142 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 		return nil, errors.Wrap(err, errTrackPCUsage)
 	}
 
-	pc_domain := &apisv1alpha1.ProviderConfig{}
-	if err := c.kube.Get(ctx, types.NamespacedName{Name: "s4t-provider-domain"}, pc_domain); err != nil {
-		return nil, errors.Wrap(err, errGetPC)
+	return &external{kube: c.kube}, nil
+}
+
+// An ExternalClient observes, then either creates, updates, or deletes an
+// external resource to ensure it reflects the managed resource's desired state.
+type external struct {
+	kube client.Client
+}
+
+// hierarchy is a snapshot of all Sites and site-labelled Devices.
+type hierarchy struct {
+	parents   map[string]string
+	children  map[string][]string
+	devices   map[string][]v1alpha1.Device
+	geofenced map[string]int
+}
+
+func (c *external) hierarchy(ctx context.Context) (*hierarchy, error) {
+	sl := &v1alpha1.SiteList{}
+	if err := c.kube.List(ctx, sl); err != nil {
+		return nil, errors.Wrap(err, errListSites)
 	}
-	cd_domain := pc_domain.Spec.Credentials
-	data_domain, err := resource.CommonCredentialExtractor(ctx, cd_domain.Source, c.kube, cd_domain.CommonCredentialSelectors)
-	if err != nil {
-		return nil, errors.Wrap(err, errGetCreds)
+	dl := &v1alpha1.DeviceList{}
+	if err := c.kube.List(ctx, dl); err != nil {
+		return nil, errors.Wrap(err, errListDevices)
+	}
+
+	h := &hierarchy{
+		parents:   map[string]string{},
+		children:  map[string][]string{},
+		devices:   map[string][]v1alpha1.Device{},
+		geofenced: map[string]int{},
+	}
+	for _, s := range sl.Items {
+		if p := s.Spec.ForProvider.ParentSite; p != "" {
+			h.parents[s.GetName()] = p
+			h.children[p] = append(h.children[p], s.GetName())
+		}
+	}
+	for _, d := range dl.Items {
+		if meta.WasDeleted(&d) {
+			continue
+		}
+		if g := d.GetLabels()[v1alpha1.LabelGeofenceSite]; g != "" {
+			h.geofenced[g]++
+		}
+		if site := d.GetLabels()[v1alpha1.LabelSite]; site != "" {
+			h.devices[site] = append(h.devices[site], d)
+		}
+	}
+	return h, nil
+}
+
+// validate checks that the site's parent exists and that following parents
+// from the site reaches a root, never a site seen before.
+func (h *hierarchy) validate(ctx context.Context, kube client.Client, cr *v1alpha1.Site) error {
+	parent := cr.Spec.ForProvider.ParentSite
+	if parent == "" {
+		return nil
 	}
-	
-	// Get Keystone endpoint from ProviderConfig, default to Kubernetes service
-	keystoneEndpoint := pc_domain.Spec.KeystoneEndpoint
-	if keystoneEndpoint == "" {
-		keystoneEndpoint = "http://keystone.default.svc.cluster.local:5000/v3"
+	if err := kube.Get(ctx, types.NamespacedName{Name: parent}, &v1alpha1.Site{}); err != nil {
+		if resource.IgnoreNotFound(err) == nil {
+			return errors.Errorf(errNoParent, parent)
+		}
+		return errors.Wrap(err, errListSites)
 	}
-	
-	svc, err := c.newServiceFn(data_domain, keystoneEndpoint)
+
+	path := []string{cr.GetName()}
+	seen := map[string]bool{cr.GetName(): true}
+	for n := parent; n != ""; n = h.parents[n] {
+		path = append(path, n)
+		if seen[n] {
+			return errors.Errorf(errCycle, strings.Join(path, " -> "))
+		}
+		seen[n] = true
+	}
+	return nil
+}
+
+// probe checks that the Keystone and IoTronic endpoints of the
+// ProviderConfig serving the site answer. Any response below 500 counts, since
+// an unauthenticated request is expected to be refused.
+func (c *external) probe(ctx context.Context, cr *v1alpha1.Site) *v1alpha1.SiteConnectivity {
+	now := metav1.Now()
+	conn := &v1alpha1.SiteConnectivity{LastProbeTime: &now}
+
+	pc, err := providerconfig.Resolve(ctx, c.kube, cr)
 	if err != nil {
-		return nil, errors.Wrap(err, errNewClient)
+		conn.Message = err.Error()
+		return conn
 	}
-	return &external{service: svc}, err
+	conn.ProviderConfig = pc.GetName()
+	conn.KeystoneEndpoint = providerconfig.KeystoneEndpoint(pc)
+	conn.IotronicEndpoint = providerconfig.IotronicEndpoint(pc)
+
+	hc := &http.Client{Timeout: probeTimeout}
+	for _, endpoint := range []string{conn.KeystoneEndpoint, conn.IotronicEndpoint} {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+		if err != nil {
+			conn.Message = err.Error()
+			return conn
+		}
+		resp, err := hc.Do(req)
+		if err != nil {
+			conn.Message = err.Error()
+			return conn
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= http.StatusInternalServerError {
+			conn.Message = fmt.Sprintf("%s: unexpected status code: %d", endpoint, resp.StatusCode)
+			return conn
+		}
+	}
+	conn.Reachable = true
+	return conn
 }
 
-// An ExternalClient observes, then either creates, updates, or deletes an
-// external resource to ensure it reflects the managed resource's desired state.
-type external struct {
-	// A 'client' used to connect to the external resource API. In practice this
-	// would be something like an AWS SDK client.
-	service *S4TService
+// count returns the number of devices and online devices labelled with the
+// site itself and, recursively, with any of its descendants.
+func (h *hierarchy) count(site string, seen map[string]bool) (devices, online int) {
+	if seen[site] {
+		return 0, 0
+	}
+	seen[site] = true
+	for _, d := range h.devices[site] {
+		devices++
+		if d.Status.Status == boardStatusOnline {
+			online++
+		}
+	}
+	for _, child := range h.children[site] {
+		d, o := h.count(child, seen)
+		devices += d
+		online += o
+	}
+	return devices, online
 }
 
 func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
@@ -207,19 +312,57 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		return managed.ExternalObservation{}, errors.New(errNotSite)
 	}
 	fmt.Printf("Observing Site: %+v", cr)
-	// TODO: Implement actual site observation via S4T API
-	// For now, assume resource exists if UUID is set
+
 	if cr.Spec.ForProvider.Uuid == "" {
 		return managed.ExternalObservation{ResourceExists: false}, nil
 	}
+
+	h, err := c.hierarchy(ctx)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+
+	// A Site being deleted is kept, and Delete is called to report why, for
+	// as long as devices or child sites still reference it.
+	if meta.WasDeleted(cr) {
+		return managed.ExternalObservation{
+			ResourceExists: len(h.devices[cr.GetName()]) > 0 || len(h.children[cr.GetName()]) > 0,
+		}, nil
+	}
+
+	if err := h.validate(ctx, c.kube, cr); err != nil {
+		return managed.ExternalObservation{}, err
+	}
+
+	obs := &cr.Status.AtProvider
+	obs.Uuid = cr.Spec.ForProvider.Uuid
+	obs.Name = cr.Spec.ForProvider.Name
+	obs.Status = siteStatusActive
+	obs.DeviceCount = len(h.devices[cr.GetName()])
+	obs.OnlineCount = 0
+	for _, d := range h.devices[cr.GetName()] {
+		if d.Status.Status == boardStatusOnline {
+			obs.OnlineCount++
+		}
+	}
+	obs.GeofenceDeviceCount = h.geofenced[cr.GetName()]
+	obs.TotalDeviceCount, obs.TotalOnlineCount = h.count(cr.GetName(), map[string]bool{})
+	obs.ChildSites = append([]string(nil), h.children[cr.GetName()]...)
+	sort.Strings(obs.ChildSites)
+	obs.Connectivity = c.probe(ctx, cr)
+	cr.Status.Uuid = obs.Uuid
+	cr.Status.Status = obs.Status
+
 	cr.Status.SetConditions(xpv1.Available())
 	return managed.ExternalObservation{
-		ResourceExists:   true,
-		ResourceUpToDate: true,
+		ResourceExists:    true,
+		ResourceUpToDate:  true,
 		ConnectionDetails: managed.ConnectionDetails{},
 	}, nil
 }
 
+// Create validates the site's place in the hierarchy and assigns it a UUID,
+// the UID of the Site object.
 func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
 	cr, ok := mg.(*v1alpha1.Site)
 	if !ok {
@@ -228,18 +371,15 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 
 	fmt.Printf("Creating Site: %+v", cr)
 
-	// TODO: Implement actual site creation via S4T API
-	// For now, this is a placeholder that generates a UUID
-	// In a real implementation, you would call:
-	// site, err := c.service.S4tClient.CreateSite(siteData)
-	
-	// Placeholder: Generate a UUID for the site
-	// In production, this should come from the S4T API response
-	if cr.Spec.ForProvider.Uuid == "" {
-		// Generate a temporary UUID - in production this comes from API
-		cr.Spec.ForProvider.Uuid = fmt.Sprintf("site-%s", cr.Spec.ForProvider.Name)
+	h, err := c.hierarchy(ctx)
+	if err != nil {
+		return managed.ExternalCreation{}, err
+	}
+	if err := h.validate(ctx, c.kube, cr); err != nil {
+		return managed.ExternalCreation{}, err
 	}
 
+	cr.Spec.ForProvider.Uuid = string(cr.GetUID())
 	log.Printf("Site created with UUID: %s", cr.Spec.ForProvider.Uuid)
 
 	return managed.ExternalCreation{
@@ -247,25 +387,15 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 	}, nil
 }
 
+// Update is a no-op; Observe validates and recomputes the site on every poll.
 func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
-	cr, ok := mg.(*v1alpha1.Site)
-	if !ok {
-		return managed.ExternalUpdate{}, errors.New(errNotSite)
-	}
-
-	fmt.Printf("Updating Site: %+v", cr)
-
-	// TODO: Implement actual site update via S4T API
-	// In a real implementation, you would call:
-	// _, err := c.service.S4tClient.PatchSite(cr.Spec.ForProvider.Uuid, updateData)
-	
-	log.Printf("Site updated: %s", cr.Spec.ForProvider.Uuid)
-
 	return managed.ExternalUpdate{
 		ConnectionDetails: managed.ConnectionDetails{},
 	}, nil
 }
 
+// Delete refuses to remove a site that devices or child sites still
+// reference.
 func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
 	cr, ok := mg.(*v1alpha1.Site)
 	if !ok {
@@ -274,11 +404,14 @@ func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
 
 	fmt.Printf("Deleting Site: %+v", cr)
 
-	// TODO: Implement actual site deletion via S4T API
-	// In a real implementation, you would call:
-	// err := c.service.S4tClient.DeleteSite(cr.Spec.ForProvider.Uuid)
-	
+	h, err := c.hierarchy(ctx)
+	if err != nil {
+		return err
+	}
+	if d, s := len(h.devices[cr.GetName()]), len(h.children[cr.GetName()]); d > 0 || s > 0 {
+		return errors.Errorf(errInUse, d, s)
+	}
+
 	log.Printf("Site deleted: %s", cr.Spec.ForProvider.Uuid)
 	return nil
 }
-
@@ -0,0 +1,158 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package site
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/crossplane/provider-s4t/apis/iot/v1alpha1"
+)
+
+func site(name, parent string) *v1alpha1.Site {
+	s := &v1alpha1.Site{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	s.Spec.ForProvider.ParentSite = parent
+	return s
+}
+
+func device(name, site, status string) *v1alpha1.Device {
+	d := &v1alpha1.Device{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	if site != "" {
+		d.SetLabels(map[string]string{v1alpha1.LabelSite: site})
+	}
+	d.Status.Status = status
+	return d
+}
+
+func newKube(t *testing.T, objs ...client.Object) client.Client {
+	t.Helper()
+	s := runtime.NewScheme()
+	if err := v1alpha1.SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatalf("AddToScheme: %v", err)
+	}
+	return fake.NewClientBuilder().WithScheme(s).WithObjects(objs...).Build()
+}
+
+func TestValidate(t *testing.T) {
+	cases := map[string]struct {
+		sites   []client.Object
+		site    string
+		wantErr bool
+	}{
+		"Root": {
+			sites: []client.Object{site("italy", "")},
+			site:  "italy",
+		},
+		"Chain": {
+			sites: []client.Object{site("italy", ""), site("sicily", "italy"), site("messina", "sicily")},
+			site:  "messina",
+		},
+		"MissingParent": {
+			sites:   []client.Object{site("messina", "sicily")},
+			site:    "messina",
+			wantErr: true,
+		},
+		"SelfParent": {
+			sites:   []client.Object{site("messina", "messina")},
+			site:    "messina",
+			wantErr: true,
+		},
+		"Cycle": {
+			sites:   []client.Object{site("a", "c"), site("b", "a"), site("c", "b")},
+			site:    "a",
+			wantErr: true,
+		},
+		"CycleAbove": {
+			// A site beneath a cycle has no root either.
+			sites:   []client.Object{site("a", "b"), site("b", "a"), site("leaf", "a")},
+			site:    "leaf",
+			wantErr: true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			kube := newKube(t, tc.sites...)
+			e := &external{kube: kube}
+			h, err := e.hierarchy(context.Background())
+			if err != nil {
+				t.Fatalf("hierarchy: %v", err)
+			}
+			cr := &v1alpha1.Site{}
+			if err := kube.Get(context.Background(), client.ObjectKey{Name: tc.site}, cr); err != nil {
+				t.Fatalf("Get: %v", err)
+			}
+			if err := h.validate(context.Background(), kube, cr); (err != nil) != tc.wantErr {
+				t.Errorf("validate: want error %t, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestCount(t *testing.T) {
+	deleted := device("gone", "messina", boardStatusOnline)
+	deleted.SetDeletionTimestamp(&metav1.Time{Time: time.Now()})
+	deleted.SetFinalizers([]string{"finalizer.managedresource.crossplane.io"})
+	// Devices located in a site's geofence are counted separately, whatever
+	// site they belong to.
+	visiting := device("m2", "messina", "offline")
+	visiting.Labels[v1alpha1.LabelGeofenceSite] = "catania"
+	roaming := device("unlabelled", "", boardStatusOnline)
+	roaming.SetLabels(map[string]string{v1alpha1.LabelGeofenceSite: "catania"})
+	objs := []client.Object{
+		site("italy", ""), site("sicily", "italy"), site("messina", "sicily"), site("catania", "sicily"),
+		site("loop-a", "loop-b"), site("loop-b", "loop-a"),
+		device("m1", "messina", boardStatusOnline),
+		visiting,
+		device("c1", "catania", boardStatusOnline),
+		device("s1", "sicily", "registered"),
+		device("l1", "loop-a", boardStatusOnline),
+		roaming,
+		deleted,
+	}
+	e := &external{kube: newKube(t, objs...)}
+	h, err := e.hierarchy(context.Background())
+	if err != nil {
+		t.Fatalf("hierarchy: %v", err)
+	}
+	if diff := cmp.Diff(map[string]int{"catania": 2}, h.geofenced); diff != "" {
+		t.Errorf("hierarchy: geofenced: -want, +got:\n%s", diff)
+	}
+
+	type counts struct{ Devices, Online int }
+	cases := map[string]counts{
+		"messina": {Devices: 2, Online: 1},
+		"sicily":  {Devices: 4, Online: 2},
+		"italy":   {Devices: 4, Online: 2},
+		"loop-a":  {Devices: 1, Online: 1},
+		"empty":   {},
+	}
+	for name, want := range cases {
+		t.Run(name, func(t *testing.T) {
+			d, o := h.count(name, map[string]bool{})
+			if diff := cmp.Diff(want, counts{Devices: d, Online: o}); diff != "" {
+				t.Errorf("count(%q): -want, +got:\n%s", name, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webservice
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/crossplane/provider-s4t/apis/iot/v1alpha1"
+	"github.com/crossplane/provider-s4t/internal/route"
+)
+
+const (
+	// webProxyService is IoTronic's web proxy, the nginx instance the
+	// wagent configures for every webservice and which dispatches on the
+	// Host header.
+	webProxyService = "iotronic-wagent"
+
+	webProxyHTTPPort  = 80
+	webProxyHTTPSPort = 443
+)
+
+// route generates the VirtualService or Ingress for spec.forProvider.route,
+// or removes the one generated before the route was dropped. Secure
+// webservices keep their TLS end to end: the route passes it through to the
+// proxy rather than terminating it.
+func (c *external) route(ctx context.Context, cr *v1alpha1.Webservice) error {
+	r := cr.Spec.ForProvider.Route
+	if r == nil {
+		if err := route.Remove(ctx, c.kube, cr.Status.AtProvider.Route); err != nil {
+			return err
+		}
+		cr.Status.AtProvider.Route = nil
+		return nil
+	}
+
+	t := route.Target{Service: webProxyService, Port: webProxyHTTPPort, TLS: cr.Spec.ForProvider.Secure}
+	if t.TLS {
+		t.Port = webProxyHTTPSPort
+	}
+	if u, err := url.Parse(cr.Status.AtProvider.URL); err == nil && u.Hostname() != "" {
+		t.Hosts = []string{u.Hostname()}
+	}
+	obs, err := route.Apply(ctx, c.kube, cr, v1alpha1.WebserviceGroupVersionKind, r, t, cr.Status.AtProvider.Route)
+	if err != nil {
+		return err
+	}
+	cr.Status.AtProvider.Route = obs
+	return nil
+}
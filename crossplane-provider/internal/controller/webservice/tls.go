@@ -0,0 +1,218 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webservice
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/crossplane/provider-s4t/apis/iot/v1alpha1"
+)
+
+const (
+	errGetTLSSecret   = "cannot get TLS Secret"
+	errApplyTLSSecret = "cannot write self-signed TLS Secret"
+	errParseCert      = "cannot parse certificate in TLS Secret"
+	errGenerateCert   = "cannot generate self-signed certificate"
+
+	// selfSignedValidity is how long a generated certificate is valid, and
+	// selfSignedRenewBefore how long before expiry it is replaced.
+	selfSignedValidity    = 365 * 24 * time.Hour
+	selfSignedRenewBefore = 30 * 24 * time.Hour
+)
+
+// A certificate is the TLS certificate configured for a webservice.
+type certificate struct {
+	cert        []byte
+	key         []byte
+	fingerprint string
+	notAfter    time.Time
+}
+
+// loadCertificate returns the certificate stored in the webservice's TLS
+// Secret without writing anything, so that it may be called from Observe. It
+// returns nil when a self-signed certificate is yet to be generated or is due
+// for renewal.
+func (c *external) loadCertificate(ctx context.Context, cr *v1alpha1.Webservice) (*certificate, error) {
+	tls := cr.Spec.ForProvider.TLS
+	s := &corev1.Secret{}
+	err := c.kube.Get(ctx, types.NamespacedName{Namespace: tls.SecretRef.Namespace, Name: tls.SecretRef.Name}, s)
+	if tls.SelfSigned && kerrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, errGetTLSSecret)
+	}
+
+	crt, err := parseCertificate(s.Data[corev1.TLSCertKey], s.Data[corev1.TLSPrivateKeyKey])
+	if !tls.SelfSigned {
+		return crt, err
+	}
+	if err != nil || time.Until(crt.notAfter) <= selfSignedRenewBefore {
+		return nil, nil
+	}
+	return crt, nil
+}
+
+// certificate returns the certificate configured for the webservice,
+// generating or renewing it first when it is self-signed. It writes the TLS
+// Secret, so it is only called from Create and Update.
+func (c *external) certificate(ctx context.Context, cr *v1alpha1.Webservice) (*certificate, error) {
+	crt, err := c.loadCertificate(ctx, cr)
+	if err != nil || crt != nil {
+		return crt, err
+	}
+
+	tls := cr.Spec.ForProvider.TLS
+	s := &corev1.Secret{}
+	err = c.kube.Get(ctx, types.NamespacedName{Namespace: tls.SecretRef.Namespace, Name: tls.SecretRef.Name}, s)
+	if err != nil && !kerrors.IsNotFound(err) {
+		return nil, errors.Wrap(err, errGetTLSSecret)
+	}
+
+	crt, err = selfSigned(hostname(cr))
+	if err != nil {
+		return nil, errors.Wrap(err, errGenerateCert)
+	}
+	s.SetName(tls.SecretRef.Name)
+	s.SetNamespace(tls.SecretRef.Namespace)
+	meta.AddOwnerReference(s, meta.AsController(meta.TypedReferenceTo(cr, v1alpha1.WebserviceGroupVersionKind)))
+	s.Type = corev1.SecretTypeTLS
+	s.Data = map[string][]byte{corev1.TLSCertKey: crt.cert, corev1.TLSPrivateKeyKey: crt.key}
+	if s.GetResourceVersion() == "" {
+		err = c.kube.Create(ctx, s)
+	} else {
+		err = c.kube.Update(ctx, s)
+	}
+	return crt, errors.Wrap(err, errApplyTLSSecret)
+}
+
+// parseCertificate reads the leaf certificate of a PEM bundle.
+func parseCertificate(cert, key []byte) (*certificate, error) {
+	block, _ := pem.Decode(cert)
+	if block == nil || len(key) == 0 {
+		return nil, errors.New(errParseCert)
+	}
+	leaf, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, errParseCert)
+	}
+	sum := sha256.Sum256(leaf.Raw)
+	return &certificate{cert: cert, key: key, fingerprint: hex.EncodeToString(sum[:]), notAfter: leaf.NotAfter}, nil
+}
+
+// selfSigned generates a self-signed ECDSA certificate for host.
+func selfSigned(host string) (*certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	tmpl := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: host},
+		DNSNames:     []string{host},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(selfSignedValidity),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		return nil, err
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return parseCertificate(
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+}
+
+// hostname returns the host the webservice is served on, falling back to
+// its name before IoTronic reports a URL.
+func hostname(cr *v1alpha1.Webservice) string {
+	if u, err := url.Parse(cr.Status.AtProvider.URL); err == nil && u.Hostname() != "" {
+		return u.Hostname()
+	}
+	return cr.Spec.ForProvider.Name
+}
+
+// publicURL returns the URL IoTronic serves the webservice on: its url
+// attribute if reported, otherwise <name>.<dns>.<zone> from the dns and zone
+// of the webservice's extra attributes.
+func publicURL(cr *v1alpha1.Webservice, ws map[string]interface{}) string {
+	if u, ok := ws["url"].(string); ok && u != "" {
+		return u
+	}
+	extra := rawObject(cr.Spec.ForProvider.Extra)
+	dns, _ := extra["dns"].(string)
+	zone, _ := extra["zone"].(string)
+	if dns == "" || zone == "" {
+		return ""
+	}
+	scheme := "http"
+	if cr.Spec.ForProvider.Secure {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://%s.%s.%s", scheme, cr.Spec.ForProvider.Name, dns, zone)
+}
+
+// pushCertificate uploads the certificate and its key to IoTronic and
+// records what was pushed.
+// API: PUT /v1/webservices/{uuid}/certificate
+func (c *external) pushCertificate(cr *v1alpha1.Webservice, crt *certificate) error {
+	resp, err := c.makeRESTCall("PUT", fmt.Sprintf("/webservices/%s/certificate", cr.Spec.ForProvider.Uuid), map[string]interface{}{
+		"certificate": string(crt.cert),
+		"private_key": string(crt.key),
+	})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	cr.Status.AtProvider.CertificateFingerprint = crt.fingerprint
+	cr.Status.AtProvider.CertificateExpiry = &metav1.Time{Time: crt.notAfter}
+	return nil
+}
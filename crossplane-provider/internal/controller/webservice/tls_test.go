@@ -0,0 +1,204 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webservice
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/crossplane/provider-s4t/apis/iot/v1alpha1"
+)
+
+func TestParseCertificate(t *testing.T) {
+	crt, err := selfSigned("dashboard.example.org")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]struct {
+		reason  string
+		cert    []byte
+		key     []byte
+		wantErr bool
+	}{
+		"Valid": {
+			reason: "A PEM certificate with a key should be parsed.",
+			cert:   crt.cert,
+			key:    crt.key,
+		},
+		"NotPEM": {
+			reason:  "A certificate that is not PEM should be rejected.",
+			cert:    []byte("not a certificate"),
+			key:     crt.key,
+			wantErr: true,
+		},
+		"NotCertificate": {
+			reason:  "A PEM block that is not a certificate should be rejected.",
+			cert:    pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("garbage")}),
+			key:     crt.key,
+			wantErr: true,
+		},
+		"NoKey": {
+			reason:  "A certificate without a private key should be rejected.",
+			cert:    crt.cert,
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := parseCertificate(tc.cert, tc.key)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("\n%s\nparseCertificate(...): want error %t, got %v", tc.reason, tc.wantErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(crt.fingerprint, got.fingerprint); diff != "" {
+				t.Errorf("\n%s\nparseCertificate(...): -want fingerprint, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSelfSigned(t *testing.T) {
+	crt, err := selfSigned("dashboard.example.org")
+	if err != nil {
+		t.Fatalf("selfSigned(...): %v", err)
+	}
+	block, _ := pem.Decode(crt.cert)
+	leaf, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatalf("selfSigned(...): cannot parse certificate: %v", err)
+	}
+	if diff := cmp.Diff([]string{"dashboard.example.org"}, leaf.DNSNames); diff != "" {
+		t.Errorf("selfSigned(...): -want DNS names, +got:\n%s", diff)
+	}
+	if err := leaf.CheckSignature(leaf.SignatureAlgorithm, leaf.RawTBSCertificate, leaf.Signature); err != nil {
+		t.Errorf("selfSigned(...): certificate is not self-signed: %v", err)
+	}
+	if d := time.Until(crt.notAfter); d < selfSignedValidity-time.Minute || d > selfSignedValidity {
+		t.Errorf("selfSigned(...): want a certificate valid for %s, got %s", selfSignedValidity, d)
+	}
+}
+
+func TestPublicURL(t *testing.T) {
+	ws := func(secure bool, extra string) *v1alpha1.Webservice {
+		cr := &v1alpha1.Webservice{}
+		cr.Spec.ForProvider.Name = "dashboard"
+		cr.Spec.ForProvider.Secure = secure
+		cr.Spec.ForProvider.Extra = runtime.RawExtension{Raw: []byte(extra)}
+		return cr
+	}
+
+	cases := map[string]struct {
+		reason   string
+		cr       *v1alpha1.Webservice
+		observed map[string]interface{}
+		want     string
+	}{
+		"Reported": {
+			reason:   "The url IoTronic reports should be used as is.",
+			cr:       ws(true, `{"dns":"board1","zone":"example.org"}`),
+			observed: map[string]interface{}{"url": "https://custom.example.org"},
+			want:     "https://custom.example.org",
+		},
+		"Secure": {
+			reason: "A secure webservice should be served over https on <name>.<dns>.<zone>.",
+			cr:     ws(true, `{"dns":"board1","zone":"example.org"}`),
+			want:   "https://dashboard.board1.example.org",
+		},
+		"Insecure": {
+			reason: "An insecure webservice should be served over http.",
+			cr:     ws(false, `{"dns":"board1","zone":"example.org"}`),
+			want:   "http://dashboard.board1.example.org",
+		},
+		"NoZone": {
+			reason: "Without dns and zone the URL is unknown.",
+			cr:     ws(true, `{"dns":"board1"}`),
+		},
+		"NoExtra": {
+			reason: "Without extra attributes the URL is unknown.",
+			cr:     ws(true, ""),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, publicURL(tc.cr, tc.observed)); diff != "" {
+				t.Errorf("\n%s\npublicURL(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCertificate(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := v1alpha1.SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	kube := fake.NewClientBuilder().WithScheme(s).Build()
+	e := &external{kube: kube}
+
+	cr := &v1alpha1.Webservice{ObjectMeta: metav1.ObjectMeta{Name: "dashboard", UID: "dashboard"}}
+	cr.Spec.ForProvider.TLS = &v1alpha1.WebserviceTLS{
+		SecretRef:  xpv1.SecretReference{Namespace: "iot", Name: "dashboard-tls"},
+		SelfSigned: true,
+	}
+	ctx := context.Background()
+	key := types.NamespacedName{Namespace: "iot", Name: "dashboard-tls"}
+
+	crt, err := e.loadCertificate(ctx, cr)
+	if err != nil || crt != nil {
+		t.Fatalf("loadCertificate(...): want no certificate before one is generated, got %v, %v", crt, err)
+	}
+	if err := kube.Get(ctx, key, &corev1.Secret{}); !kerrors.IsNotFound(err) {
+		t.Fatalf("loadCertificate(...): must not write the TLS Secret, got %v", err)
+	}
+
+	generated, err := e.certificate(ctx, cr)
+	if err != nil {
+		t.Fatalf("certificate(...): %v", err)
+	}
+	if err := kube.Get(ctx, key, &corev1.Secret{}); err != nil {
+		t.Fatalf("certificate(...): want the TLS Secret written, got %v", err)
+	}
+
+	loaded, err := e.loadCertificate(ctx, cr)
+	if err != nil || loaded == nil {
+		t.Fatalf("loadCertificate(...): want the generated certificate, got %v, %v", loaded, err)
+	}
+	if diff := cmp.Diff(generated.fingerprint, loaded.fingerprint); diff != "" {
+		t.Errorf("loadCertificate(...): -want fingerprint, +got:\n%s", diff)
+	}
+}
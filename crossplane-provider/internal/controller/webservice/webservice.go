@@ -24,12 +24,9 @@ import (
 	"io"
 	"log"
 	"net/http"
-	"os"
-	"strings"
 	"time"
 
 	s4t "github.com/MIKE9708/s4t-sdk-go/pkg/api"
-	read_config "github.com/MIKE9708/s4t-sdk-go/pkg/read_conf"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/connection"
@@ -39,13 +36,20 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/crossplane/provider-s4t/apis/iot/v1alpha1"
 	apisv1alpha1 "github.com/crossplane/provider-s4t/apis/v1alpha1"
+	"github.com/crossplane/provider-s4t/internal/diff"
 	"github.com/crossplane/provider-s4t/internal/features"
+	"github.com/crossplane/provider-s4t/internal/providerconfig"
 )
 
 const (
@@ -54,6 +58,11 @@ const (
 	errGetPC        = "cannot get ProviderConfig"
 	errGetCreds     = "cannot get credentials"
 	errNewClient    = "cannot create new Service"
+	errPushCert     = "cannot push certificate to IoTronic"
+	errRoute        = "cannot route webservice"
+
+	reasonDrift       event.Reason = "DriftDetected"
+	reasonCertificate event.Reason = "CertificatePushed"
 )
 
 type S4TService struct {
@@ -64,69 +73,11 @@ type S4TService struct {
 
 var (
 	newS4TService = func(creds []byte, keystoneEndpoint string) (*S4TService, error) {
-		var result map[string]string
-		err := json.Unmarshal(creds, &result)
-		if err != nil {
-			return nil, errors.Wrap(err, errNewClient)
-		}
-		auth_req := read_config.FormatAuthRequ(
-			result["username"],
-			result["password"],
-			result["domain"],
-		)
-		
-		// Set Keystone endpoint via environment variable (OS_AUTH_URL)
-		if keystoneEndpoint != "" {
-			os.Setenv("OS_AUTH_URL", keystoneEndpoint)
-		} else {
-			os.Setenv("OS_AUTH_URL", "http://keystone.default.svc.cluster.local:5000/v3")
-		}
-		// Extract host from keystoneEndpoint BEFORE creating client
-		// SDK uses hardcoded 127.0.0.1, we need to use Kubernetes service
-		endpoint := keystoneEndpoint
-		if endpoint == "" {
-			endpoint = "http://keystone.default.svc.cluster.local:5000/v3"
-		}
-		if strings.HasSuffix(endpoint, "/v3") {
-			endpoint = strings.TrimSuffix(endpoint, "/v3")
-		}
-		scheme := "http://"
-		if strings.HasPrefix(endpoint, "https://") {
-			scheme = "https://"
-			endpoint = strings.TrimPrefix(endpoint, "https://")
-		} else if strings.HasPrefix(endpoint, "http://") {
-			endpoint = strings.TrimPrefix(endpoint, "http://")
-		}
-		if idx := strings.Index(endpoint, ":"); idx != -1 {
-			endpoint = endpoint[:idx]
-		}
-		keystoneHost := scheme + endpoint
-		
-		// Create client manually with correct host (without port) instead of using GetClientConnection
-		// SDK will derive final URL using AuthPort
-		s4t_client := s4t.NewClient(keystoneHost)
-		s4t_client.Port = "8812"
-		s4t_client.AuthPort = "5000"
-		
-		// Authenticate with correct endpoint
-		token, err := s4t_client.Authenticate(s4t_client, auth_req)
+		c, err := providerconfig.Connect(creds, keystoneEndpoint)
 		if err != nil {
 			return nil, errors.Wrap(err, errNewClient)
 		}
-		s4t_client.AuthToken = token
-		
-		iotronicHost := scheme + "iotronic-conductor.default.svc.cluster.local"
-		s4t_client.Endpoint = iotronicHost
-		
-		if err != nil {
-			return nil, errors.Wrap(err, errNewClient)
-		}
-		// Extract base URL and token from client if available
-		// For now, we'll use default conductor endpoint
-		return &S4TService{
-			S4tClient: s4t_client,
-			BaseURL:   "http://iotronic-conductor:8812", // Default conductor endpoint
-		}, nil
+		return &S4TService{S4tClient: c}, nil
 	}
 )
 
@@ -139,28 +90,56 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), apisv1alpha1.StoreConfigGroupVersionKind))
 	}
 
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
+
 	r := managed.NewReconciler(mgr,
 		resource.ManagedKind(v1alpha1.WebserviceGroupVersionKind),
 		managed.WithExternalConnecter(&connector{
 			kube:         mgr.GetClient(),
-			usage:        resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
+			usage:        providerconfig.NewTracker(mgr.GetClient()),
+			recorder:     recorder,
 			newServiceFn: newS4TService}),
+		managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
 		managed.WithLogger(o.Logger.WithValues("controller", name)),
 		managed.WithPollInterval(o.PollInterval),
-		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		managed.WithRecorder(recorder),
 		managed.WithConnectionPublishers(cps...))
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
 		WithOptions(o.ForControllerRuntime()).
-		WithEventFilter(resource.DesiredStateChanged()).
-		For(&v1alpha1.Webservice{}).
+		For(&v1alpha1.Webservice{}, builder.WithPredicates(resource.DesiredStateChanged())).
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(webservicesForSecret(mgr.GetClient()))).
 		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
 }
 
+// webservicesForSecret maps a TLS Secret to the Webservices using it, so a
+// rotated certificate is pushed without waiting for the next poll.
+func webservicesForSecret(kube client.Client) handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		if s, ok := obj.(*corev1.Secret); !ok || s.Type != corev1.SecretTypeTLS {
+			return nil
+		}
+		l := &v1alpha1.WebserviceList{}
+		if err := kube.List(ctx, l); err != nil {
+			log.Printf("Error listing Webservices: %v", err)
+			return nil
+		}
+		var reqs []reconcile.Request
+		for _, ws := range l.Items {
+			tls := ws.Spec.ForProvider.TLS
+			if tls != nil && tls.SecretRef.Name == obj.GetName() && tls.SecretRef.Namespace == obj.GetNamespace() {
+				reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{Name: ws.GetName()}})
+			}
+		}
+		return reqs
+	}
+}
+
 type connector struct {
 	kube         client.Client
 	usage        resource.Tracker
+	recorder     event.Recorder
 	newServiceFn func(creds []byte, keystoneEndpoint string) (*S4TService, error)
 }
 
@@ -174,8 +153,8 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 		return nil, errors.Wrap(err, errTrackPCUsage)
 	}
 
-	pc_domain := &apisv1alpha1.ProviderConfig{}
-	if err := c.kube.Get(ctx, types.NamespacedName{Name: "s4t-provider-domain"}, pc_domain); err != nil {
+	pc_domain, err := providerconfig.Resolve(ctx, c.kube, mg)
+	if err != nil {
 		return nil, errors.Wrap(err, errGetPC)
 	}
 	cd_domain := pc_domain.Spec.Credentials
@@ -194,7 +173,10 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 	if err != nil {
 		return nil, errors.Wrap(err, errNewClient)
 	}
-	return &external{service: svc}, err
+	if err := providerconfig.Configure(svc.S4tClient, pc_domain); err != nil {
+		return nil, errors.Wrap(err, errNewClient)
+	}
+	return &external{kube: c.kube, service: svc, recorder: c.recorder}, err
 }
 
 // An ExternalClient observes, then either creates, updates, or deletes an
@@ -202,14 +184,16 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 type external struct {
 	// A 'client' used to connect to the external resource API. In practice this
 	// would be something like an AWS SDK client.
-	service *S4TService
+	service  *S4TService
+	kube     client.Client
+	recorder event.Recorder
 }
 
 
 // makeRESTCall makes a REST API call to the IoTronic service
 func (c *external) makeRESTCall(method, path string, data interface{}) (*http.Response, error) {
 	// Build URL using the service client's endpoint
-	baseURL := fmt.Sprintf("http://iotronic-conductor.default.svc.cluster.local:%s", c.service.S4tClient.Port)
+	baseURL := fmt.Sprintf("%s:%s", c.service.S4tClient.Endpoint, c.service.S4tClient.Port)
 	url := fmt.Sprintf("%s/v1%s", baseURL, path)
 	
 	var reqBody io.Reader
@@ -253,33 +237,98 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		return managed.ExternalObservation{ResourceExists: false}, nil
 	}
 
-	resp, err := c.makeRESTCall("GET", fmt.Sprintf("/webservices/%s", cr.Spec.ForProvider.Uuid), nil)
+	webservice, err := c.getWebservice(cr.Spec.ForProvider.Uuid)
 	if err != nil {
-		log.Printf("Error getting webservice: %v", err)
 		return managed.ExternalObservation{}, err
 	}
+	if webservice == nil {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	d := diff.Compute(webserviceFields(cr.Spec.ForProvider), webservice)
+
+	cr.Status.AtProvider.Uuid = cr.Spec.ForProvider.Uuid
+	cr.Status.AtProvider.Name = cr.Spec.ForProvider.Name
+	cr.Status.AtProvider.URL = publicURL(cr, webservice)
+	details := managed.ConnectionDetails{}
+	if cr.Status.AtProvider.URL != "" {
+		details["url"] = []byte(cr.Status.AtProvider.URL)
+	}
+
+	// The certificate is up to date once the one configured has been pushed.
+	// A self-signed certificate still to be generated or renewed is not, and
+	// is written by Update.
+	certUpToDate := true
+	if cr.Spec.ForProvider.TLS != nil {
+		crt, err := c.loadCertificate(ctx, cr)
+		if err != nil {
+			return managed.ExternalObservation{}, err
+		}
+		certUpToDate = crt != nil && crt.fingerprint == cr.Status.AtProvider.CertificateFingerprint
+		if exp := cr.Status.AtProvider.CertificateExpiry; exp != nil {
+			details["certificateExpiry"] = []byte(exp.UTC().Format(time.RFC3339))
+		}
+	}
+
+	cr.Status.SetConditions(xpv1.Available())
+
+	if err := c.route(ctx, cr); err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errRoute)
+	}
+
+	return managed.ExternalObservation{
+		ResourceExists:    true,
+		ResourceUpToDate:  d.Empty() && certUpToDate,
+		ConnectionDetails: details,
+	}, nil
+}
+
+// getWebservice returns the webservice as IoTronic reports it, or nil
+// if it does not exist.
+// API: GET /v1/webservices/{uuid}
+func (c *external) getWebservice(uuid string) (map[string]interface{}, error) {
+	resp, err := c.makeRESTCall("GET", fmt.Sprintf("/webservices/%s", uuid), nil)
+	if err != nil {
+		log.Printf("Error getting webservice: %v", err)
+		return nil, err
+	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusNotFound {
-		return managed.ExternalObservation{ResourceExists: false}, nil
+		return nil, nil
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return managed.ExternalObservation{}, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
 	}
 
 	var webservice map[string]interface{}
 	if err := json.NewDecoder(resp.Body).Decode(&webservice); err != nil {
-		return managed.ExternalObservation{}, errors.Wrap(err, "failed to decode response")
+		return nil, errors.Wrap(err, "failed to decode response")
 	}
+	return webservice, nil
+}
 
-	cr.Status.SetConditions(xpv1.Available())
+// webserviceFields maps WebserviceParameters to IoTronic webservice
+// attributes.
+func webserviceFields(p v1alpha1.WebserviceParameters) []diff.Field {
+	return []diff.Field{
+		{Name: "name", Desired: p.Name},
+		{Name: "port", Desired: p.Port, IgnoreZero: true},
+		{Name: "secure", Desired: p.Secure, IgnoreZero: true},
+		{Name: "extra", Desired: rawObject(p.Extra), IgnoreZero: true},
+	}
+}
 
-	return managed.ExternalObservation{
-		ResourceExists:   true,
-		ResourceUpToDate: true,
-		ConnectionDetails: managed.ConnectionDetails{},
-	}, nil
+// rawObject decodes a RawExtension holding a JSON object, or returns nil.
+func rawObject(r runtime.RawExtension) map[string]interface{} {
+	if len(r.Raw) == 0 {
+		return nil
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(r.Raw, &m); err != nil {
+		return nil
+	}
+	return m
 }
 
 func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
@@ -325,6 +374,17 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 		cr.Spec.ForProvider.Uuid = uuid
 		cr.Status.AtProvider.Uuid = uuid
 	}
+	cr.Status.AtProvider.URL = publicURL(cr, webservice)
+
+	if cr.Spec.ForProvider.TLS != nil {
+		crt, err := c.certificate(ctx, cr)
+		if err != nil {
+			return managed.ExternalCreation{}, err
+		}
+		if err := c.pushCertificate(cr, crt); err != nil {
+			return managed.ExternalCreation{}, errors.Wrap(err, errPushCert)
+		}
+	}
 
 	return managed.ExternalCreation{
 		ConnectionDetails: managed.ConnectionDetails{},
@@ -339,32 +399,41 @@ func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 
 	fmt.Printf("Updating Webservice: %+v", cr)
 
-	webserviceData := map[string]interface{}{}
-	if cr.Spec.ForProvider.Name != "" {
-		webserviceData["name"] = cr.Spec.ForProvider.Name
-	}
-	if cr.Spec.ForProvider.Port != 0 {
-		webserviceData["port"] = cr.Spec.ForProvider.Port
-	}
-	if cr.Spec.ForProvider.Secure {
-		webserviceData["secure"] = cr.Spec.ForProvider.Secure
+	observed, err := c.getWebservice(cr.Spec.ForProvider.Uuid)
+	if err != nil {
+		return managed.ExternalUpdate{}, err
 	}
-	if len(cr.Spec.ForProvider.Extra.Raw) > 0 {
-		var extra map[string]interface{}
-		if err := json.Unmarshal(cr.Spec.ForProvider.Extra.Raw, &extra); err == nil {
-			webserviceData["extra"] = extra
+	d := diff.Compute(webserviceFields(cr.Spec.ForProvider), observed)
+	if !d.Empty() {
+		if c.recorder != nil {
+			c.recorder.Event(cr, event.Normal(reasonDrift, "Updating drifted fields: "+d.String()))
 		}
-	}
 
-	resp, err := c.makeRESTCall("PATCH", fmt.Sprintf("/webservices/%s", cr.Spec.ForProvider.Uuid), webserviceData)
-	if err != nil {
-		log.Printf("Error updating webservice: %v", err)
-		return managed.ExternalUpdate{}, err
+		resp, err := c.makeRESTCall("PATCH", fmt.Sprintf("/webservices/%s", cr.Spec.ForProvider.Uuid), d.Patch())
+		if err != nil {
+			log.Printf("Error updating webservice: %v", err)
+			return managed.ExternalUpdate{}, err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return managed.ExternalUpdate{}, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		}
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return managed.ExternalUpdate{}, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	if cr.Spec.ForProvider.TLS != nil {
+		crt, err := c.certificate(ctx, cr)
+		if err != nil {
+			return managed.ExternalUpdate{}, err
+		}
+		if crt.fingerprint != cr.Status.AtProvider.CertificateFingerprint {
+			if err := c.pushCertificate(cr, crt); err != nil {
+				return managed.ExternalUpdate{}, errors.Wrap(err, errPushCert)
+			}
+			if c.recorder != nil {
+				c.recorder.Event(cr, event.Normal(reasonCertificate, fmt.Sprintf("Pushed certificate %s, expiring %s", crt.fingerprint, crt.notAfter.UTC().Format(time.RFC3339))))
+			}
+		}
 	}
 
 	return managed.ExternalUpdate{
@@ -0,0 +1,64 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webservice
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"github.com/crossplane/provider-s4t/apis/iot/v1alpha1"
+	"github.com/crossplane/provider-s4t/internal/diff"
+)
+
+func TestWebserviceFields(t *testing.T) {
+	cases := map[string]struct {
+		params   v1alpha1.WebserviceParameters
+		observed map[string]interface{}
+		want     []string
+	}{
+		"UpToDate": {
+			params:   v1alpha1.WebserviceParameters{Name: "dashboard", Port: 8080, Secure: true},
+			observed: map[string]interface{}{"name": "dashboard", "port": float64(8080), "secure": true},
+		},
+		"InsecureUnset": {
+			params:   v1alpha1.WebserviceParameters{Name: "dashboard", Port: 8080},
+			observed: map[string]interface{}{"name": "dashboard", "port": float64(8080)},
+		},
+		"PortAsString": {
+			params:   v1alpha1.WebserviceParameters{Name: "dashboard", Port: 8080, Secure: true},
+			observed: map[string]interface{}{"name": "dashboard", "port": "8080", "secure": "True"},
+		},
+		"Drifted": {
+			params:   v1alpha1.WebserviceParameters{Name: "dashboard", Port: 8443, Secure: true},
+			observed: map[string]interface{}{"name": "dashboard", "port": float64(8080)},
+			want:     []string{"port", "secure"},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got []string
+			for _, c := range diff.Compute(webserviceFields(tc.params), tc.observed) {
+				got = append(got, c.Field)
+			}
+			if diff := cmp.Diff(tc.want, got, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("webserviceFields: -want, +got drifted fields:\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+/*
+ Copyright 2022 The Crossplane Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package diff compares the desired state of a managed resource with its
+// IoTronic representation field by field, so controllers only update what
+// drifted and only send the fields that changed.
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// A Field maps one ForProvider field to an IoTronic attribute.
+type Field struct {
+	// Name is the IoTronic attribute, as it appears in request and response
+	// bodies.
+	Name string
+
+	// Desired is the ForProvider value, in its IoTronic representation.
+	Desired interface{}
+
+	// IgnoreZero skips the field when Desired is its zero value, for
+	// optional fields whose absence means "leave IoTronic's value alone".
+	IgnoreZero bool
+}
+
+// A Change is a field whose observed value differs from the desired one.
+type Change struct {
+	Field    string
+	Observed interface{}
+	Desired  interface{}
+}
+
+// A Diff is the set of changed fields, sorted by field name.
+type Diff []Change
+
+// Compute compares fields against observed, an IoTronic object as decoded
+// from JSON. Values are compared in their JSON form, so an int in ForProvider
+// equals the float64 it decodes to.
+func Compute(fields []Field, observed map[string]interface{}) Diff {
+	var d Diff
+	for _, f := range fields {
+		if f.IgnoreZero && isZero(f.Desired) {
+			continue
+		}
+		want := normalize(f.Desired)
+		got := coerce(want, normalize(observed[f.Name]))
+		if reflect.DeepEqual(want, got) {
+			continue
+		}
+		d = append(d, Change{Field: f.Name, Observed: got, Desired: f.Desired})
+	}
+	sort.Slice(d, func(i, j int) bool { return d[i].Field < d[j].Field })
+	return d
+}
+
+// Empty reports whether nothing drifted.
+func (d Diff) Empty() bool {
+	return len(d) == 0
+}
+
+// Patch returns a request body setting only the changed fields.
+func (d Diff) Patch() map[string]interface{} {
+	p := make(map[string]interface{}, len(d))
+	for _, c := range d {
+		p[c.Field] = c.Desired
+	}
+	return p
+}
+
+// String summarises the diff as "field: observed -> desired" pairs.
+func (d Diff) String() string {
+	s := make([]string, 0, len(d))
+	for _, c := range d {
+		s = append(s, fmt.Sprintf("%s: %s -> %s", c.Field, format(c.Observed), format(normalize(c.Desired))))
+	}
+	return strings.Join(s, ", ")
+}
+
+// normalize returns v as it would be decoded from JSON.
+func normalize(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var n interface{}
+	if err := json.Unmarshal(b, &n); err != nil {
+		return v
+	}
+	return n
+}
+
+// coerce converts got to the JSON type of want when IoTronic reports a
+// scalar in another representation, such as a port as "8080" or a flag as
+// "true". Values that do not convert are returned as they are.
+func coerce(want, got interface{}) interface{} {
+	s, ok := got.(string)
+	if !ok {
+		return got
+	}
+	switch want.(type) {
+	case float64:
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f
+		}
+	case bool:
+		if b, err := strconv.ParseBool(s); err == nil {
+			return b
+		}
+	}
+	return got
+}
+
+func isZero(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Map, reflect.Slice:
+		return rv.Len() == 0
+	}
+	return rv.IsZero()
+}
+
+func format(v interface{}) string {
+	if v == nil {
+		return "<unset>"
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(b)
+}
@@ -0,0 +1,111 @@
+/*
+ Copyright 2022 The Crossplane Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package diff
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCompute(t *testing.T) {
+	type args struct {
+		fields   []Field
+		observed map[string]interface{}
+	}
+
+	type want struct {
+		diff  Diff
+		patch map[string]interface{}
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"UpToDate": {
+			reason: "Numbers should compare equal to the float64 they decode to.",
+			args: args{
+				fields:   []Field{{Name: "name", Desired: "ssh"}, {Name: "port", Desired: uint(22)}},
+				observed: map[string]interface{}{"name": "ssh", "port": float64(22)},
+			},
+			want: want{patch: map[string]interface{}{}},
+		},
+		"Drifted": {
+			reason: "Only drifted fields should be patched.",
+			args: args{
+				fields:   []Field{{Name: "name", Desired: "ssh"}, {Name: "port", Desired: 2222}},
+				observed: map[string]interface{}{"name": "ssh", "port": float64(22)},
+			},
+			want: want{
+				diff:  Diff{{Field: "port", Observed: float64(22), Desired: 2222}},
+				patch: map[string]interface{}{"port": 2222},
+			},
+		},
+		"IgnoreZero": {
+			reason: "Unset optional fields should not be compared.",
+			args: args{
+				fields:   []Field{{Name: "ip", Desired: "", IgnoreZero: true}, {Name: "extra", Desired: map[string]interface{}{}, IgnoreZero: true}},
+				observed: map[string]interface{}{"ip": "10.0.0.2"},
+			},
+			want: want{patch: map[string]interface{}{}},
+		},
+		"Coerced": {
+			reason: "Scalars IoTronic reports as strings should compare equal to the value they hold.",
+			args: args{
+				fields:   []Field{{Name: "port", Desired: 8080}, {Name: "secure", Desired: true}, {Name: "name", Desired: "dashboard"}},
+				observed: map[string]interface{}{"port": "8080", "secure": "true", "name": "dashboard"},
+			},
+			want: want{patch: map[string]interface{}{}},
+		},
+		"WrongType": {
+			reason: "Values that do not convert to the desired type should be patched.",
+			args: args{
+				fields:   []Field{{Name: "port", Desired: 8080}},
+				observed: map[string]interface{}{"port": "http"},
+			},
+			want: want{
+				diff:  Diff{{Field: "port", Observed: "http", Desired: 8080}},
+				patch: map[string]interface{}{"port": 8080},
+			},
+		},
+		"Missing": {
+			reason: "A desired field absent from IoTronic should be patched.",
+			args: args{
+				fields:   []Field{{Name: "secure", Desired: false}},
+				observed: map[string]interface{}{},
+			},
+			want: want{
+				diff:  Diff{{Field: "secure", Observed: nil, Desired: false}},
+				patch: map[string]interface{}{"secure": false},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := Compute(tc.args.fields, tc.args.observed)
+			if diff := cmp.Diff(tc.want.diff, got); diff != "" {
+				t.Errorf("\n%s\nCompute(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.patch, got.Patch()); diff != "" {
+				t.Errorf("\n%s\nPatch(): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
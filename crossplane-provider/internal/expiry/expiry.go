@@ -0,0 +1,140 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package expiry deletes time-limited resources once they expire.
+package expiry
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// AnnotationExpired marks a resource Check deleted because it expired.
+	// Removing what it manages from its board is not held off for a
+	// maintenance window, as it is due now.
+	AnnotationExpired = "iot.s4t.crossplane.io/expired"
+	// AnnotationExpiring is the expiry Check last reported as imminent, so
+	// that it is reported once.
+	AnnotationExpiring = "iot.s4t.crossplane.io/expiring"
+
+	errDelete   = "cannot delete expired resource"
+	errAnnotate = "cannot annotate time-limited resource"
+
+	// Warning is how long before a resource expires that it reports so,
+	// unless that is more than a quarter of its lifetime.
+	Warning = 15 * time.Minute
+
+	reasonExpiring event.Reason = "Expiring"
+	reasonExpired  event.Reason = "Expired"
+)
+
+// At returns when o expires, given its ttl or expiresAt, or nil if it does
+// not.
+func At(o metav1.Object, ttl *metav1.Duration, expiresAt *metav1.Time) *metav1.Time {
+	switch {
+	case expiresAt != nil:
+		return expiresAt.DeepCopy()
+	case ttl != nil:
+		t := metav1.NewTime(o.GetCreationTimestamp().Add(ttl.Duration))
+		return &t
+	}
+	return nil
+}
+
+// Lead returns how long before at o reports that it expires.
+func Lead(o metav1.Object, at time.Time) time.Duration {
+	return min(Warning, at.Sub(o.GetCreationTimestamp().Time)/4)
+}
+
+// Check deletes mg, recording why, if it expired at at. The deletion is
+// handled like any other, so the external resource is removed by the
+// controller's Delete, which need not wait for a maintenance window if mg is
+// Expired. Shortly before at, Check records once that mg is about to expire.
+// It reports whether mg expired.
+func Check(ctx context.Context, kube client.Client, recorder event.Recorder, mg resource.Managed, at *metav1.Time) (bool, error) {
+	if at == nil || meta.WasDeleted(mg) {
+		return false, nil
+	}
+	stamp := at.UTC().Format(time.RFC3339)
+	until := time.Until(at.Time)
+	if until > 0 {
+		if until <= Lead(mg, at.Time) && mg.GetAnnotations()[AnnotationExpiring] != stamp {
+			if err := annotate(ctx, kube, mg, AnnotationExpiring, stamp); err != nil {
+				return false, err
+			}
+			recorder.Event(mg, event.Normal(reasonExpiring, fmt.Sprintf("Expires at %s", stamp)))
+		}
+		return false, nil
+	}
+	if err := annotate(ctx, kube, mg, AnnotationExpired, stamp); err != nil {
+		return false, err
+	}
+	recorder.Event(mg, event.Normal(reasonExpired, fmt.Sprintf("Expired at %s, deleting", stamp)))
+	if err := kube.Delete(ctx, mg); err != nil && !kerrors.IsNotFound(err) {
+		return false, errors.Wrap(err, errDelete)
+	}
+	return true, nil
+}
+
+// Expired reports whether Check deleted o because it expired.
+func Expired(o metav1.Object) bool {
+	return o.GetAnnotations()[AnnotationExpired] != ""
+}
+
+// annotate sets an annotation of mg. A copy is patched so that the status of
+// mg, which the caller may have changed, is not reset to the stored one.
+func annotate(ctx context.Context, kube client.Client, mg resource.Managed, key, value string) error {
+	o, ok := mg.DeepCopyObject().(client.Object)
+	if !ok {
+		return errors.New(errAnnotate)
+	}
+	patch := client.MergeFrom(o.DeepCopyObject().(client.Object))
+	meta.AddAnnotations(o, map[string]string{key: value})
+	if err := kube.Patch(ctx, o, patch); err != nil {
+		return errors.Wrap(err, errAnnotate)
+	}
+	mg.SetAnnotations(o.GetAnnotations())
+	mg.SetResourceVersion(o.GetResourceVersion())
+	return nil
+}
+
+// PollInterval returns a poll interval hook that observes resources when
+// they are about to expire and when they expire, if that is before their next
+// poll. expiresAt returns when a resource expires.
+func PollInterval(expiresAt func(resource.Managed) *metav1.Time) func(resource.Managed, time.Duration) time.Duration {
+	return func(mg resource.Managed, d time.Duration) time.Duration {
+		at := expiresAt(mg)
+		if at == nil || meta.WasDeleted(mg) {
+			return d
+		}
+		for _, t := range []time.Time{at.Add(-Lead(mg, at.Time)), at.Time} {
+			if until := time.Until(t); until > 0 && until < d {
+				return until + time.Second
+			}
+		}
+		return d
+	}
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package expiry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/crossplane/provider-s4t/apis/iot/v1alpha1"
+)
+
+func TestAt(t *testing.T) {
+	created := metav1.NewTime(time.Date(2026, 8, 1, 12, 0, 0, 0, time.UTC))
+	fixed := metav1.NewTime(time.Date(2026, 8, 2, 0, 0, 0, 0, time.UTC))
+	hour := created.Add(time.Hour)
+	o := &metav1.ObjectMeta{CreationTimestamp: created}
+
+	cases := map[string]struct {
+		ttl       *metav1.Duration
+		expiresAt *metav1.Time
+		want      *time.Time
+	}{
+		"Never":     {},
+		"TTL":       {ttl: &metav1.Duration{Duration: time.Hour}, want: &hour},
+		"ExpiresAt": {expiresAt: &fixed, want: &fixed.Time},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := At(o, tc.ttl, tc.expiresAt)
+			switch {
+			case tc.want == nil && got != nil:
+				t.Errorf("At: want nil, got %s", got)
+			case tc.want != nil && (got == nil || !got.Time.Equal(*tc.want)):
+				t.Errorf("At: want %s, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestPollInterval(t *testing.T) {
+	now := time.Now()
+	injection := func(age, left time.Duration) *v1alpha1.BoardPluginInjection {
+		i := &v1alpha1.BoardPluginInjection{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(now.Add(-age))}}
+		at := metav1.NewTime(now.Add(left))
+		i.Status.AtProvider.ExpiresAt = &at
+		return i
+	}
+	hook := PollInterval(func(mg resource.Managed) *metav1.Time {
+		return mg.(*v1alpha1.BoardPluginInjection).Status.AtProvider.ExpiresAt
+	})
+
+	cases := map[string]struct {
+		mg   *v1alpha1.BoardPluginInjection
+		want time.Duration
+	}{
+		"Never":        {mg: &v1alpha1.BoardPluginInjection{}, want: time.Minute},
+		"Later":        {mg: injection(time.Hour, 10*time.Hour), want: time.Minute},
+		"BeforeWarn":   {mg: injection(time.Hour, Warning+30*time.Second), want: 30 * time.Second},
+		"BeforeExpiry": {mg: injection(time.Hour, 30*time.Second), want: 30 * time.Second},
+		// A two minute injection reports half a minute before it expires.
+		"Short": {mg: injection(time.Minute, time.Minute), want: 30 * time.Second},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := hook(tc.mg, time.Minute)
+			// The hook rounds up by a second, and time passes while it runs.
+			if got > tc.want+time.Second || got < tc.want-time.Second {
+				t.Errorf("PollInterval: want about %s, got %s", tc.want, got)
+			}
+		})
+	}
+}
+
+// recorder records the reasons of the events it is sent.
+type recorder struct{ reasons []event.Reason }
+
+func (r *recorder) Event(_ runtime.Object, e event.Event) { r.reasons = append(r.reasons, e.Reason) }
+
+func (r *recorder) WithAnnotations(...string) event.Recorder { return r }
+
+func TestCheck(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := v1alpha1.SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatalf("AddToScheme: %v", err)
+	}
+	past := metav1.NewTime(time.Now().Add(-time.Minute))
+	soon := metav1.NewTime(time.Now().Add(time.Minute))
+	future := metav1.NewTime(time.Now().Add(time.Hour))
+	// The finalizer keeps the deleted injection around, as the managed
+	// reconciler's does.
+	i := &v1alpha1.BoardPluginInjection{ObjectMeta: metav1.ObjectMeta{Name: "i", Finalizers: []string{"test"}, CreationTimestamp: metav1.NewTime(time.Now().Add(-time.Hour))}}
+	kube := fake.NewClientBuilder().WithScheme(s).WithObjects(i).Build()
+	ctx := context.Background()
+	r := &recorder{}
+
+	if expired, err := Check(ctx, kube, r, i, &future); err != nil || expired {
+		t.Errorf("Check before expiry: want false, nil, got %t, %v", expired, err)
+	}
+	// Imminent expiry is reported once, however often it is checked.
+	for n := 0; n < 2; n++ {
+		if expired, err := Check(ctx, kube, r, i, &soon); err != nil || expired {
+			t.Errorf("Check shortly before expiry: want false, nil, got %t, %v", expired, err)
+		}
+	}
+	if expired, err := Check(ctx, kube, r, i, &past); err != nil || !expired {
+		t.Errorf("Check after expiry: want true, nil, got %t, %v", expired, err)
+	}
+	if diff := cmp.Diff([]event.Reason{reasonExpiring, reasonExpired}, r.reasons); diff != "" {
+		t.Errorf("Check: -want, +got events:\n%s", diff)
+	}
+
+	got := &v1alpha1.BoardPluginInjection{}
+	if err := kube.Get(ctx, types.NamespacedName{Name: "i"}, got); err != nil {
+		t.Fatalf("Get after expiry: %v", err)
+	}
+	if got.GetDeletionTimestamp() == nil || !Expired(got) {
+		t.Errorf("Check after expiry: want an injection being deleted and marked expired, got %+v", got.ObjectMeta)
+	}
+}
@@ -0,0 +1,174 @@
+/*
+ Copyright 2022 The Crossplane Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package geofence decides which Site a board belongs to from the location it
+// reports.
+package geofence
+
+import (
+	"encoding/json"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/provider-s4t/apis/iot/v1alpha1"
+)
+
+// earthRadius is the mean radius of the Earth in meters.
+const earthRadius = 6371008.8
+
+const (
+	errPoint   = "invalid position %q, %q"
+	errPolygon = "invalid GeoJSON polygon"
+	errNoRings = "GeoJSON polygon has no rings"
+)
+
+// A Point is a position in decimal degrees.
+type Point struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// ParsePoint parses a latitude and longitude written as decimal strings.
+func ParsePoint(latitude, longitude string) (Point, error) {
+	lat, err := strconv.ParseFloat(latitude, 64)
+	if err != nil {
+		return Point{}, errors.Errorf(errPoint, latitude, longitude)
+	}
+	lon, err := strconv.ParseFloat(longitude, 64)
+	if err != nil {
+		return Point{}, errors.Errorf(errPoint, latitude, longitude)
+	}
+	return Point{Latitude: lat, Longitude: lon}, nil
+}
+
+// polygon is the subset of a GeoJSON Polygon geometry we use.
+type polygon struct {
+	Type        string         `json:"type"`
+	Coordinates [][][2]float64 `json:"coordinates"`
+}
+
+// Contains reports whether p lies inside f.
+func Contains(f *v1alpha1.Geofence, p Point) (bool, error) {
+	if f.Center != nil {
+		c, err := ParsePoint(f.Center.Latitude, f.Center.Longitude)
+		if err != nil {
+			return false, err
+		}
+		return Distance(c, p) <= float64(f.RadiusMeters), nil
+	}
+
+	if f.Polygon == nil {
+		return false, nil
+	}
+	poly := polygon{}
+	if err := json.Unmarshal(f.Polygon.Raw, &poly); err != nil || poly.Type != "Polygon" {
+		return false, errors.New(errPolygon)
+	}
+	if len(poly.Coordinates) == 0 {
+		return false, errors.New(errNoRings)
+	}
+	if !inRing(poly.Coordinates[0], p) {
+		return false, nil
+	}
+	for _, hole := range poly.Coordinates[1:] {
+		if inRing(hole, p) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// Distance returns the great-circle distance between a and b in meters.
+func Distance(a, b Point) float64 {
+	rad := math.Pi / 180
+	dLat := (b.Latitude - a.Latitude) * rad
+	dLon := (b.Longitude - a.Longitude) * rad
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(a.Latitude*rad)*math.Cos(b.Latitude*rad)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(h))
+}
+
+// inRing casts a ray from p towards increasing longitude and counts how many
+// edges of ring it crosses.
+func inRing(ring [][2]float64, p Point) bool {
+	in := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		xi, yi := ring[i][0], ring[i][1]
+		xj, yj := ring[j][0], ring[j][1]
+		if (yi > p.Latitude) != (yj > p.Latitude) &&
+			p.Longitude < (xj-xi)*(p.Latitude-yi)/(yj-yi)+xi {
+			in = !in
+		}
+	}
+	return in
+}
+
+// Match returns the name of the Site whose geofence contains p. When
+// geofences overlap the deepest site in the hierarchy wins, so that a site
+// nested inside its parent's area takes precedence; remaining ties go to the
+// first name. It returns "" when no geofence contains p. Sites with invalid
+// geofences and Sites being deleted are skipped.
+func Match(sites []v1alpha1.Site, p Point) string {
+	parents := map[string]string{}
+	for _, s := range sites {
+		parents[s.GetName()] = s.Spec.ForProvider.ParentSite
+	}
+	depth := func(name string) int {
+		d := 0
+		seen := map[string]bool{}
+		for n := parents[name]; n != "" && !seen[n]; n = parents[n] {
+			seen[n] = true
+			d++
+		}
+		return d
+	}
+
+	var matches []string
+	for i := range sites {
+		f := sites[i].Spec.ForProvider.Geofence
+		if f == nil || meta.WasDeleted(&sites[i]) {
+			continue
+		}
+		if ok, err := Contains(f, p); err == nil && ok {
+			matches = append(matches, sites[i].GetName())
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if di, dj := depth(matches[i]), depth(matches[j]); di != dj {
+			return di > dj
+		}
+		return matches[i] < matches[j]
+	})
+	if len(matches) == 0 {
+		return ""
+	}
+	return matches[0]
+}
+
+// Any reports whether any of the sites declares a geofence. Sites being
+// deleted no longer claim boards.
+func Any(sites []v1alpha1.Site) bool {
+	for i := range sites {
+		if sites[i].Spec.ForProvider.Geofence != nil && !meta.WasDeleted(&sites[i]) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+/*
+ Copyright 2022 The Crossplane Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package geofence
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/provider-s4t/apis/iot/v1alpha1"
+)
+
+func polygonSite(name, parent, geojson string) v1alpha1.Site {
+	s := v1alpha1.Site{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	s.Spec.ForProvider.ParentSite = parent
+	s.Spec.ForProvider.Geofence = &v1alpha1.Geofence{Polygon: &runtime.RawExtension{Raw: []byte(geojson)}}
+	return s
+}
+
+func circleSite(name, lat, lon string, radius int64) v1alpha1.Site {
+	s := v1alpha1.Site{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	s.Spec.ForProvider.Geofence = &v1alpha1.Geofence{
+		Center:       &v1alpha1.GeoPoint{Latitude: lat, Longitude: lon},
+		RadiusMeters: radius,
+	}
+	return s
+}
+
+func TestMatch(t *testing.T) {
+	// A 10x10 degree square with a 2x2 degree hole in the middle, and a
+	// small square nested inside it near its south west corner.
+	region := `{"type":"Polygon","coordinates":[
+		[[0,0],[10,0],[10,10],[0,10],[0,0]],
+		[[4,4],[6,4],[6,6],[4,6],[4,4]]]}`
+	campus := `{"type":"Polygon","coordinates":[[[1,1],[2,1],[2,2],[1,2],[1,1]]]}`
+
+	sites := []v1alpha1.Site{
+		polygonSite("region", "", region),
+		polygonSite("campus", "region", campus),
+		circleSite("messina", "38.1938", "15.5540", 5000),
+	}
+
+	cases := map[string]struct {
+		reason string
+		p      Point
+		want   string
+	}{
+		"InsidePolygon": {
+			reason: "A point inside a polygon should match its site.",
+			p:      Point{Latitude: 8, Longitude: 8},
+			want:   "region",
+		},
+		"InsideHole": {
+			reason: "A point inside a hole should not match the polygon.",
+			p:      Point{Latitude: 5, Longitude: 5},
+			want:   "",
+		},
+		"Nested": {
+			reason: "The deepest site should win where geofences overlap.",
+			p:      Point{Latitude: 1.5, Longitude: 1.5},
+			want:   "campus",
+		},
+		"InsideCircle": {
+			reason: "A point within the radius should match the circle's site.",
+			p:      Point{Latitude: 38.2, Longitude: 15.56},
+			want:   "messina",
+		},
+		"Outside": {
+			reason: "A point outside every geofence should match nothing.",
+			p:      Point{Latitude: 45.46, Longitude: 9.19},
+			want:   "",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, Match(sites, tc.p)); diff != "" {
+				t.Errorf("\n%s\nMatch(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
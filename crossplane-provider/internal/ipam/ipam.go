@@ -0,0 +1,449 @@
+/*
+ Copyright 2022 The Crossplane Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package ipam allocates Port addresses from the IPPool serving their
+// network. Allocations are recorded in the pool's status, which is updated
+// with optimistic concurrency so that concurrent Ports never share an
+// address.
+package ipam
+
+import (
+	"context"
+	"math"
+	"math/big"
+	"net/netip"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/provider-s4t/apis/iot/v1alpha1"
+)
+
+const (
+	errListPools  = "cannot list IPPools"
+	errListPorts  = "cannot list Ports"
+	errUpdatePool = "cannot update IPPool status"
+	errManyPools  = "network %q is served by more than one IPPool: %s"
+	errCIDR       = "IPPool %s: invalid cidr %q"
+	errGateway    = "IPPool %s: invalid gateway %q"
+	errExclusion  = "IPPool %s: invalid exclusion %q"
+	errIP         = "invalid ip %q"
+	errOutside    = "ip %s is outside %s of IPPool %s"
+	errReserved   = "ip %s is reserved in IPPool %s"
+	errTaken      = "ip %s is already allocated to Port %s"
+	errExhausted  = "IPPool %s has no free addresses"
+	errGetPool    = "cannot get IPPool"
+)
+
+// A Pool is the parsed form of an IPPool.
+type Pool struct {
+	Name       string
+	Prefix     netip.Prefix
+	Gateway    netip.Addr
+	Exclusions []netip.Prefix
+}
+
+// Parse validates an IPPool.
+func Parse(p *v1alpha1.IPPool) (*Pool, error) {
+	prefix, err := netip.ParsePrefix(p.Spec.CIDR)
+	if err != nil {
+		return nil, errors.Errorf(errCIDR, p.GetName(), p.Spec.CIDR)
+	}
+	pool := &Pool{Name: p.GetName(), Prefix: prefix.Masked()}
+	if p.Spec.Gateway != "" {
+		if pool.Gateway, err = netip.ParseAddr(p.Spec.Gateway); err != nil || !pool.Prefix.Contains(pool.Gateway) {
+			return nil, errors.Errorf(errGateway, p.GetName(), p.Spec.Gateway)
+		}
+	}
+	for _, e := range p.Spec.Exclusions {
+		x, err := parsePrefix(e)
+		if err != nil {
+			return nil, errors.Errorf(errExclusion, p.GetName(), e)
+		}
+		pool.Exclusions = append(pool.Exclusions, x)
+	}
+	return pool, nil
+}
+
+// parsePrefix parses a CIDR or a single address.
+func parsePrefix(s string) (netip.Prefix, error) {
+	if strings.Contains(s, "/") {
+		p, err := netip.ParsePrefix(s)
+		return p.Masked(), err
+	}
+	a, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	return netip.PrefixFrom(a, a.BitLen()), nil
+}
+
+// Check returns an error if ip may not be allocated from the pool, ignoring
+// existing allocations.
+func (p *Pool) Check(ip netip.Addr) error {
+	if !p.Prefix.Contains(ip) {
+		return errors.Errorf(errOutside, ip, p.Prefix, p.Name)
+	}
+	if p.reserved(ip) {
+		return errors.Errorf(errReserved, ip, p.Name)
+	}
+	return nil
+}
+
+// reserved reports whether ip is the network or broadcast address, the
+// gateway or excluded.
+func (p *Pool) reserved(ip netip.Addr) bool {
+	if ip == p.Prefix.Addr() || ip == p.Gateway {
+		return true
+	}
+	if ip.Is4() && ip == p.last() {
+		return true
+	}
+	for _, x := range p.Exclusions {
+		if x.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// last returns the highest address of the pool.
+func (p *Pool) last() netip.Addr {
+	return lastOf(p.Prefix)
+}
+
+// lastOf returns the highest address of prefix.
+func lastOf(prefix netip.Prefix) netip.Addr {
+	b := prefix.Addr().AsSlice()
+	for i := prefix.Bits(); i < len(b)*8; i++ {
+		b[i/8] |= 1 << (7 - uint(i%8))
+	}
+	a, _ := netip.AddrFromSlice(b)
+	return a
+}
+
+// Next returns the lowest address of the pool that is neither reserved nor
+// in use. Excluded prefixes are skipped whole, so that the addresses looked
+// at are bounded by the number in use and excluded rather than by the size
+// of the pool.
+func (p *Pool) Next(inUse map[netip.Addr]bool) (netip.Addr, error) {
+	ip := p.Prefix.Addr()
+next:
+	for p.Prefix.Contains(ip) {
+		for _, x := range p.Exclusions {
+			if x.Contains(ip) {
+				ip = lastOf(x).Next()
+				continue next
+			}
+		}
+		if !p.reserved(ip) && !inUse[ip] {
+			return ip, nil
+		}
+		ip = ip.Next()
+	}
+	return netip.Addr{}, errors.Errorf(errExhausted, p.Name)
+}
+
+// Size returns the number of addresses the pool can allocate, capped at the
+// largest int64.
+func (p *Pool) Size() int64 {
+	hostBits := p.Prefix.Addr().BitLen() - p.Prefix.Bits()
+	n := new(big.Int).Lsh(big.NewInt(1), uint(hostBits))
+	excluded := p.excluded()
+	for _, x := range excluded {
+		n.Sub(n, new(big.Int).Lsh(big.NewInt(1), uint(x.Addr().BitLen()-x.Bits())))
+	}
+	single := []netip.Addr{p.Prefix.Addr(), p.Gateway}
+	if p.Prefix.Addr().Is4() {
+		single = append(single, p.last())
+	}
+	seen := map[netip.Addr]bool{}
+addrs:
+	for _, ip := range single {
+		if !ip.IsValid() || seen[ip] {
+			continue
+		}
+		seen[ip] = true
+		for _, x := range excluded {
+			if x.Contains(ip) {
+				continue addrs
+			}
+		}
+		n.Sub(n, big.NewInt(1))
+	}
+	if n.Sign() < 0 {
+		return 0
+	}
+	if !n.IsInt64() {
+		return math.MaxInt64
+	}
+	return n.Int64()
+}
+
+// excluded returns the exclusions of the pool clipped to it, without those
+// another one covers, so that no address is in more than one.
+func (p *Pool) excluded() []netip.Prefix {
+	var in []netip.Prefix
+	for _, x := range p.Exclusions {
+		switch {
+		case !x.Overlaps(p.Prefix):
+		case x.Bits() <= p.Prefix.Bits():
+			return []netip.Prefix{p.Prefix}
+		default:
+			in = append(in, x)
+		}
+	}
+	// Prefixes either nest or are disjoint: sorted by size, each one is
+	// kept unless one kept before covers it.
+	sort.Slice(in, func(i, j int) bool { return in[i].Bits() < in[j].Bits() })
+	var out []netip.Prefix
+prefixes:
+	for _, x := range in {
+		for _, y := range out {
+			if y.Contains(x.Addr()) {
+				continue prefixes
+			}
+		}
+		out = append(out, x)
+	}
+	return out
+}
+
+// For returns the IPPool serving the network of port, or nil if there is
+// none. A pool serves a Port if their networks are equal or if both
+// reference the same Network.
+func For(ctx context.Context, kube client.Client, port *v1alpha1.Port) (*v1alpha1.IPPool, error) {
+	p := port.Spec.ForProvider
+	if p.Network == "" && p.NetworkRef == nil {
+		return nil, nil
+	}
+	l := &v1alpha1.IPPoolList{}
+	if err := kube.List(ctx, l); err != nil {
+		return nil, errors.Wrap(err, errListPools)
+	}
+	var found []*v1alpha1.IPPool
+	var names []string
+	for i := range l.Items {
+		spec := l.Items[i].Spec
+		byName := spec.Network != "" && spec.Network == p.Network
+		byRef := spec.NetworkRef != nil && p.NetworkRef != nil && spec.NetworkRef.Name == p.NetworkRef.Name
+		if byName || byRef {
+			found = append(found, &l.Items[i])
+			names = append(names, l.Items[i].GetName())
+		}
+	}
+	switch len(found) {
+	case 0:
+		return nil, nil
+	case 1:
+		return found[0], nil
+	default:
+		sort.Strings(names)
+		return nil, errors.Errorf(errManyPools, network(p), strings.Join(names, ", "))
+	}
+}
+
+// network names the network of a Port for error messages.
+func network(p v1alpha1.PortParameters) string {
+	if p.NetworkRef != nil {
+		return p.NetworkRef.Name
+	}
+	return p.Network
+}
+
+// Validate returns an error if the address requested by port conflicts with
+// the IPPool serving its network or with another Port. Ports without an
+// address, or on a network without a pool, are always valid.
+func Validate(ctx context.Context, kube client.Client, port *v1alpha1.Port) error {
+	pp, err := For(ctx, kube, port)
+	if err != nil || pp == nil {
+		return err
+	}
+	pool, err := Parse(pp)
+	if err != nil {
+		return err
+	}
+	if port.Spec.ForProvider.Ip == "" {
+		return nil
+	}
+	ip, err := netip.ParseAddr(port.Spec.ForProvider.Ip)
+	if err != nil {
+		return errors.Errorf(errIP, port.Spec.ForProvider.Ip)
+	}
+	if err := pool.Check(ip); err != nil {
+		return err
+	}
+	for _, a := range pp.Status.Allocations {
+		if a.IP == ip.String() && a.Port != port.GetName() {
+			return errors.Errorf(errTaken, ip, a.Port)
+		}
+	}
+
+	l := &v1alpha1.PortList{}
+	if err := kube.List(ctx, l); err != nil {
+		return errors.Wrap(err, errListPorts)
+	}
+	for _, other := range l.Items {
+		if other.GetName() == port.GetName() || !sameNetwork(other.Spec.ForProvider, port.Spec.ForProvider) {
+			continue
+		}
+		if o, err := netip.ParseAddr(other.Spec.ForProvider.Ip); err == nil && o == ip {
+			return errors.Errorf(errTaken, ip, other.GetName())
+		}
+	}
+	return nil
+}
+
+// Allocate records the address of port in the IPPool serving its network
+// and returns it together with the pool's name. The requested address is
+// reserved if set, otherwise the address already allocated to the Port or
+// the next free one is used. It returns empty strings when no pool serves
+// the network.
+func Allocate(ctx context.Context, kube client.Client, port *v1alpha1.Port) (string, string, error) {
+	pp, err := For(ctx, kube, port)
+	if err != nil || pp == nil {
+		return "", "", err
+	}
+	pool, err := Parse(pp)
+	if err != nil {
+		return "", "", err
+	}
+
+	inUse := map[netip.Addr]bool{}
+	var held netip.Addr
+	for _, a := range pp.Status.Allocations {
+		ip, err := netip.ParseAddr(a.IP)
+		if err != nil {
+			continue
+		}
+		if a.Port == port.GetName() {
+			held = ip
+			continue
+		}
+		inUse[ip] = true
+	}
+
+	var ip netip.Addr
+	switch {
+	case port.Spec.ForProvider.Ip != "":
+		if ip, err = netip.ParseAddr(port.Spec.ForProvider.Ip); err != nil {
+			return "", "", errors.Errorf(errIP, port.Spec.ForProvider.Ip)
+		}
+		if err := pool.Check(ip); err != nil {
+			return "", "", err
+		}
+		if inUse[ip] {
+			return "", "", errors.Errorf(errTaken, ip, holder(pp, ip))
+		}
+	case held.IsValid():
+		ip = held
+	default:
+		if ip, err = pool.Next(inUse); err != nil {
+			return "", "", err
+		}
+	}
+
+	if ip != held {
+		setAllocation(pp, port.GetName(), ip.String())
+		pp.Status.Available = pool.Size() - int64(pp.Status.Allocated)
+		if err := kube.Status().Update(ctx, pp); err != nil {
+			return "", "", errors.Wrap(err, errUpdatePool)
+		}
+	}
+	return ip.String(), pp.GetName(), nil
+}
+
+// Reallocate is Allocate for a Port whose address or network changed: the
+// new address is recorded in the IPPool now serving its network, replacing
+// the one held there, and allocations held in any other pool are released.
+func Reallocate(ctx context.Context, kube client.Client, port *v1alpha1.Port) (string, string, error) {
+	ip, pool, err := Allocate(ctx, kube, port)
+	if err != nil {
+		return "", "", err
+	}
+	return ip, pool, release(ctx, kube, port, pool)
+}
+
+// Release removes the allocations held by port from every IPPool.
+func Release(ctx context.Context, kube client.Client, port *v1alpha1.Port) error {
+	return release(ctx, kube, port, "")
+}
+
+// release removes the allocations held by port from every IPPool but keep.
+func release(ctx context.Context, kube client.Client, port *v1alpha1.Port, keep string) error {
+	l := &v1alpha1.IPPoolList{}
+	if err := kube.List(ctx, l); err != nil {
+		return errors.Wrap(err, errListPools)
+	}
+	for i := range l.Items {
+		pp := &l.Items[i]
+		if pp.GetName() == keep || !setAllocation(pp, port.GetName(), "") {
+			continue
+		}
+		if p, err := Parse(pp); err == nil {
+			pp.Status.Available = p.Size() - int64(pp.Status.Allocated)
+		}
+		if err := kube.Status().Update(ctx, pp); err != nil {
+			return errors.Wrap(err, errUpdatePool)
+		}
+	}
+	return nil
+}
+
+// setAllocation sets the address held by port, removing it if ip is empty,
+// and reports whether the allocations changed.
+func setAllocation(pp *v1alpha1.IPPool, port, ip string) bool {
+	changed := false
+	allocations := pp.Status.Allocations[:0]
+	for _, a := range pp.Status.Allocations {
+		if a.Port == port {
+			changed = true
+			continue
+		}
+		allocations = append(allocations, a)
+	}
+	if ip != "" {
+		allocations = append(allocations, v1alpha1.IPAllocation{IP: ip, Port: port})
+		changed = true
+	}
+	sort.Slice(allocations, func(i, j int) bool {
+		a, _ := netip.ParseAddr(allocations[i].IP)
+		b, _ := netip.ParseAddr(allocations[j].IP)
+		return a.Less(b)
+	})
+	pp.Status.Allocations = allocations
+	pp.Status.Allocated = len(allocations)
+	return changed
+}
+
+// sameNetwork reports whether two Ports attach to the same network.
+func sameNetwork(a, b v1alpha1.PortParameters) bool {
+	if a.NetworkRef != nil && b.NetworkRef != nil {
+		return a.NetworkRef.Name == b.NetworkRef.Name
+	}
+	return a.Network != "" && a.Network == b.Network
+}
+
+func holder(pp *v1alpha1.IPPool, ip netip.Addr) string {
+	for _, a := range pp.Status.Allocations {
+		if a.IP == ip.String() {
+			return a.Port
+		}
+	}
+	return ""
+}
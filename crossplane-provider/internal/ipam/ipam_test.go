@@ -0,0 +1,267 @@
+/*
+ Copyright 2022 The Crossplane Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package ipam
+
+import (
+	"context"
+	"math"
+	"net/netip"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/crossplane/provider-s4t/apis"
+	"github.com/crossplane/provider-s4t/apis/iot/v1alpha1"
+)
+
+func port(name, ip string) *v1alpha1.Port {
+	p := &v1alpha1.Port{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	p.Spec.ForProvider.Network = "net0"
+	p.Spec.ForProvider.Ip = ip
+	return p
+}
+
+func TestAllocate(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := apis.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	pool := &v1alpha1.IPPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "pool"},
+		Spec: v1alpha1.IPPoolSpec{
+			Network:    "net0",
+			CIDR:       "10.0.0.0/29",
+			Gateway:    "10.0.0.1",
+			Exclusions: []string{"10.0.0.2"},
+		},
+	}
+	kube := fake.NewClientBuilder().WithScheme(s).WithObjects(pool).WithStatusSubresource(pool).Build()
+	ctx := context.Background()
+
+	// 10.0.0.0 is the network, .1 the gateway, .2 excluded and .7 the
+	// broadcast address, leaving .3 to .6.
+	want := []string{"10.0.0.3", "10.0.0.4", "10.0.0.5"}
+	for i, name := range []string{"a", "b", "c"} {
+		got, _, err := Allocate(ctx, kube, port(name, ""))
+		if err != nil {
+			t.Fatalf("Allocate(%s): %v", name, err)
+		}
+		if diff := cmp.Diff(want[i], got); diff != "" {
+			t.Errorf("Allocate(%s): -want, +got:\n%s", name, diff)
+		}
+	}
+
+	if got, _, _ := Allocate(ctx, kube, port("b", "")); got != "10.0.0.4" {
+		t.Errorf("Allocate(b) again: want the address already held, got %s", got)
+	}
+	if _, _, err := Allocate(ctx, kube, port("d", "10.0.0.3")); err == nil {
+		t.Error("Allocate(d, 10.0.0.3): want error for an address held by another Port")
+	}
+	if _, _, err := Allocate(ctx, kube, port("d", "10.0.0.1")); err == nil {
+		t.Error("Allocate(d, 10.0.0.1): want error for the gateway")
+	}
+
+	if err := Release(ctx, kube, port("a", "")); err != nil {
+		t.Fatalf("Release(a): %v", err)
+	}
+	if got, _, _ := Allocate(ctx, kube, port("d", "")); got != "10.0.0.3" {
+		t.Errorf("Allocate(d): want the released address 10.0.0.3, got %s", got)
+	}
+
+	got := &v1alpha1.IPPool{}
+	if err := kube.Get(ctx, client.ObjectKey{Name: "pool"}, got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Status.Allocated != 3 || got.Status.Available != 1 {
+		t.Errorf("status: want 3 allocated and 1 available, got %d and %d", got.Status.Allocated, got.Status.Available)
+	}
+}
+
+func TestReallocate(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := apis.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	net0 := &v1alpha1.IPPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "net0"},
+		Spec:       v1alpha1.IPPoolSpec{Network: "net0", CIDR: "10.0.0.0/29"},
+	}
+	net1 := &v1alpha1.IPPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "net1"},
+		Spec:       v1alpha1.IPPoolSpec{Network: "net1", CIDR: "10.1.0.0/29"},
+	}
+	kube := fake.NewClientBuilder().WithScheme(s).WithObjects(net0, net1).WithStatusSubresource(net0, net1).Build()
+	ctx := context.Background()
+
+	allocations := func(pool string) []v1alpha1.IPAllocation {
+		pp := &v1alpha1.IPPool{}
+		if err := kube.Get(ctx, client.ObjectKey{Name: pool}, pp); err != nil {
+			t.Fatal(err)
+		}
+		return pp.Status.Allocations
+	}
+
+	if _, _, err := Allocate(ctx, kube, port("a", "10.0.0.3")); err != nil {
+		t.Fatalf("Allocate(a): %v", err)
+	}
+
+	// A new address in the same pool replaces the old one.
+	if _, _, err := Reallocate(ctx, kube, port("a", "10.0.0.5")); err != nil {
+		t.Fatalf("Reallocate(a, 10.0.0.5): %v", err)
+	}
+	if diff := cmp.Diff([]v1alpha1.IPAllocation{{IP: "10.0.0.5", Port: "a"}}, allocations("net0")); diff != "" {
+		t.Errorf("Reallocate(a, 10.0.0.5): net0 allocations: -want, +got:\n%s", diff)
+	}
+
+	// Moving to another network releases the address held in the old pool.
+	moved := port("a", "")
+	moved.Spec.ForProvider.Network = "net1"
+	ip, pool, err := Reallocate(ctx, kube, moved)
+	if err != nil {
+		t.Fatalf("Reallocate(a, net1): %v", err)
+	}
+	if ip != "10.1.0.1" || pool != "net1" {
+		t.Errorf("Reallocate(a, net1): want 10.1.0.1 from net1, got %s from %s", ip, pool)
+	}
+	if diff := cmp.Diff([]v1alpha1.IPAllocation{}, allocations("net0"), cmpopts.EquateEmpty()); diff != "" {
+		t.Errorf("Reallocate(a, net1): net0 allocations: -want, +got:\n%s", diff)
+	}
+}
+
+func parse(t *testing.T, cidr, gateway string, exclusions ...string) *Pool {
+	t.Helper()
+	p, err := Parse(&v1alpha1.IPPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "pool"},
+		Spec:       v1alpha1.IPPoolSpec{CIDR: cidr, Gateway: gateway, Exclusions: exclusions},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestNext(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		pool   *Pool
+		inUse  []string
+		want   string
+	}{
+		"First": {
+			reason: "The network address and gateway should be skipped.",
+			pool:   parse(t, "10.0.0.0/24", "10.0.0.1"),
+			want:   "10.0.0.2",
+		},
+		"InUse": {
+			reason: "Addresses in use should be skipped.",
+			pool:   parse(t, "10.0.0.0/24", "10.0.0.1"),
+			inUse:  []string{"10.0.0.2", "10.0.0.3"},
+			want:   "10.0.0.4",
+		},
+		"Excluded": {
+			reason: "An excluded prefix should be skipped whole.",
+			pool:   parse(t, "10.0.0.0/24", "", "10.0.0.0/26", "10.0.0.64"),
+			want:   "10.0.0.65",
+		},
+		"LargeExclusion": {
+			reason: "An exclusion covering half of an IPv6 /64 should be jumped over, not walked.",
+			pool:   parse(t, "fd00::/64", "", "fd00::/65"),
+			want:   "fd00::8000:0:0:0",
+		},
+		"Exhausted": {
+			reason: "A fully excluded IPv6 /64 should be exhausted without being walked.",
+			pool:   parse(t, "fd00::/64", "", "fd00::/65", "fd00::8000:0:0:0/65"),
+		},
+		"Broadcast": {
+			reason: "The IPv4 broadcast address should never be allocated.",
+			pool:   parse(t, "10.0.0.0/30", "10.0.0.1"),
+			inUse:  []string{"10.0.0.2"},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			inUse := map[netip.Addr]bool{}
+			for _, ip := range tc.inUse {
+				inUse[netip.MustParseAddr(ip)] = true
+			}
+			got, err := tc.pool.Next(inUse)
+			if tc.want == "" {
+				if err == nil {
+					t.Errorf("\n%s\nNext: want error, got %s", tc.reason, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("\n%s\nNext: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, got.String()); diff != "" {
+				t.Errorf("\n%s\nNext: -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSize(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		pool   *Pool
+		want   int64
+	}{
+		"IPv4": {
+			reason: "The network, broadcast and gateway addresses should not be counted.",
+			pool:   parse(t, "10.0.0.0/24", "10.0.0.1"),
+			want:   253,
+		},
+		"Exclusions": {
+			reason: "Nested exclusions and reserved addresses they cover should be counted once.",
+			pool:   parse(t, "10.0.0.0/24", "10.0.0.1", "10.0.0.0/26", "10.0.0.0/28", "10.0.0.64", "192.168.0.0/16"),
+			want:   256 - 64 - 1 - 1,
+		},
+		"WholePool": {
+			reason: "An exclusion covering the pool should leave nothing.",
+			pool:   parse(t, "10.0.0.0/24", "", "10.0.0.0/8"),
+			want:   0,
+		},
+		"LargeIPv4": {
+			reason: "A /8 should be counted without walking it.",
+			pool:   parse(t, "10.0.0.0/8", "10.0.0.1"),
+			want:   1<<24 - 3,
+		},
+		"IPv6": {
+			reason: "An IPv6 pool with half of it excluded should be counted exactly.",
+			pool:   parse(t, "fd00::/96", "", "fd00::/97"),
+			want:   1 << 31,
+		},
+		"Capped": {
+			reason: "Sizes beyond int64 should be capped.",
+			pool:   parse(t, "fd00::/48", ""),
+			want:   math.MaxInt64,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, tc.pool.Size()); diff != "" {
+				t.Errorf("\n%s\nSize: -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
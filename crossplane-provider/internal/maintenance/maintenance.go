@@ -0,0 +1,274 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package maintenance decides whether disruptive operations may be made on a
+// board now, according to the MaintenanceWindows that apply to it.
+package maintenance
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	// Time zones are resolved from the binary, as the provider image has no
+	// zoneinfo.
+	_ "time/tzdata"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	"github.com/robfig/cron/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlevent "sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/provider-s4t/apis/iot/v1alpha1"
+)
+
+const (
+	errListDevices = "cannot list Devices"
+	errListFleets  = "cannot list Fleets"
+	errListSites   = "cannot list Sites"
+	errGetWindow   = "cannot get MaintenanceWindow %q"
+	errSchedule    = "cannot parse schedule %q"
+)
+
+// State returns whether a window with parameters p is open at now, until
+// when if it is, and when it next opens.
+func State(p v1alpha1.MaintenanceWindowParameters, now time.Time) (open bool, until, next time.Time, err error) {
+	tz := "UTC"
+	if p.TimeZone != nil && *p.TimeZone != "" {
+		tz = *p.TimeZone
+	}
+	for _, s := range p.Schedules {
+		sched, err := cron.ParseStandard("CRON_TZ=" + tz + " " + s.Start)
+		if err != nil {
+			return false, time.Time{}, time.Time{}, errors.Wrapf(err, errSchedule, s.Start)
+		}
+		// The latest opening within one duration of now, if any, keeps the
+		// window open.
+		var start time.Time
+		for t := sched.Next(now.Add(-s.Duration.Duration)); !t.IsZero() && !t.After(now); t = sched.Next(t) {
+			start = t
+		}
+		if !start.IsZero() {
+			open = true
+			if end := start.Add(s.Duration.Duration); end.After(until) {
+				until = end
+			}
+		}
+		if n := sched.Next(now); !n.IsZero() && (next.IsZero() || n.Before(next)) {
+			next = n
+		}
+	}
+	return open, until, next, nil
+}
+
+// Deferred is returned for a disruptive operation on a board outside its
+// maintenance window.
+type Deferred struct {
+	Window string
+	Opens  time.Time
+}
+
+func (d *Deferred) Error() string {
+	return fmt.Sprintf("board is outside maintenance window %q, which opens at %s", d.Window, d.Opens.UTC().Format(time.RFC3339))
+}
+
+// Condition is the condition of a resource holding off for d.
+func (d *Deferred) Condition() xpv1.Condition {
+	return v1alpha1.WaitingForMaintenanceWindow(d.Window, d.Opens)
+}
+
+// DeletionCondition is the condition of a resource being deleted that holds
+// off for d.
+func (d *Deferred) DeletionCondition() xpv1.Condition {
+	return v1alpha1.DeletionWaitingForMaintenanceWindow(d.Window, d.Opens)
+}
+
+// Overridden reports whether o carries the maintenance override annotation.
+func Overridden(o metav1.Object) bool {
+	return o.GetAnnotations()[v1alpha1.AnnotationMaintenanceOverride] == "true"
+}
+
+// Check returns a Deferred if board is outside a maintenance window that
+// applies to it, unless o, the resource acting on it, is overridden.
+func Check(ctx context.Context, kube client.Reader, o metav1.Object, board string) (*Deferred, error) {
+	if Overridden(o) {
+		return nil, nil
+	}
+	windows, err := Windows(ctx, kube, board)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	for _, w := range windows {
+		open, _, next, err := State(w.Spec.ForProvider, now)
+		if err != nil {
+			return nil, err
+		}
+		if !open {
+			return &Deferred{Window: w.GetName(), Opens: next}, nil
+		}
+	}
+	return nil, nil
+}
+
+// Windows returns the MaintenanceWindows that apply to board. The window of
+// the board's Device takes precedence over those of the Fleets it belongs
+// to, and those over the window of its Site or, failing that, of the
+// nearest ancestor site with one. A board no Device knows has none.
+func Windows(ctx context.Context, kube client.Reader, board string) ([]*v1alpha1.MaintenanceWindow, error) {
+	if board == "" {
+		return nil, nil
+	}
+	dl := &v1alpha1.DeviceList{}
+	if err := kube.List(ctx, dl); err != nil {
+		return nil, errors.Wrap(err, errListDevices)
+	}
+	var d *v1alpha1.Device
+	for i := range dl.Items {
+		if dl.Items[i].Spec.ForProvider.Uuid == board && !meta.WasDeleted(&dl.Items[i]) {
+			d = &dl.Items[i]
+			break
+		}
+	}
+	if d == nil {
+		return nil, nil
+	}
+
+	names, err := references(ctx, kube, d)
+	if err != nil {
+		return nil, err
+	}
+	windows := make([]*v1alpha1.MaintenanceWindow, 0, len(names))
+	for _, name := range names {
+		w := &v1alpha1.MaintenanceWindow{}
+		if err := kube.Get(ctx, types.NamespacedName{Name: name}, w); err != nil {
+			return nil, errors.Wrapf(err, errGetWindow, name)
+		}
+		windows = append(windows, w)
+	}
+	return windows, nil
+}
+
+// references returns the names of the MaintenanceWindows that apply to d,
+// sorted.
+func references(ctx context.Context, kube client.Reader, d *v1alpha1.Device) ([]string, error) {
+	if ref := d.Spec.ForProvider.MaintenanceWindowRef; ref != nil {
+		return []string{ref.Name}, nil
+	}
+
+	fl := &v1alpha1.FleetList{}
+	if err := kube.List(ctx, fl); err != nil {
+		return nil, errors.Wrap(err, errListFleets)
+	}
+	seen := map[string]bool{}
+	var names []string
+	for _, f := range fl.Items {
+		ref := f.Spec.ForProvider.MaintenanceWindowRef
+		if ref == nil || seen[ref.Name] || !Member(&f, d) {
+			continue
+		}
+		seen[ref.Name] = true
+		names = append(names, ref.Name)
+	}
+	if len(names) > 0 {
+		sort.Strings(names)
+		return names, nil
+	}
+
+	site := d.GetLabels()[v1alpha1.LabelSite]
+	if site == "" {
+		return nil, nil
+	}
+	sl := &v1alpha1.SiteList{}
+	if err := kube.List(ctx, sl); err != nil {
+		return nil, errors.Wrap(err, errListSites)
+	}
+	sites := make(map[string]*v1alpha1.Site, len(sl.Items))
+	for i := range sl.Items {
+		sites[sl.Items[i].GetName()] = &sl.Items[i]
+	}
+	visited := map[string]bool{}
+	for n := site; n != "" && !visited[n] && sites[n] != nil; n = sites[n].Spec.ForProvider.ParentSite {
+		visited[n] = true
+		if ref := sites[n].Spec.ForProvider.MaintenanceWindowRef; ref != nil {
+			return []string{ref.Name}, nil
+		}
+	}
+	return nil, nil
+}
+
+// Member reports whether f lists or selects d.
+func Member(f *v1alpha1.Fleet, d *v1alpha1.Device) bool {
+	for _, r := range f.Spec.ForProvider.DeviceRefs {
+		if r.Name == d.GetName() {
+			return true
+		}
+	}
+	if s := f.Spec.ForProvider.DeviceSelector; s != nil {
+		sel, err := metav1.LabelSelectorAsSelector(s)
+		return err == nil && sel.Matches(labels.Set(d.GetLabels()))
+	}
+	return false
+}
+
+// Waiting maps a MaintenanceWindow to the resources listed by newList that
+// are being deleted or holding off for a maintenance window, so that they
+// act as soon as it opens rather than on their next poll.
+func Waiting(kube client.Client, newList func() resource.ManagedList) handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		l := newList()
+		if err := kube.List(ctx, l); err != nil {
+			log.Printf("Error listing resources for MaintenanceWindow %s: %v", obj.GetName(), err)
+			return nil
+		}
+		var reqs []reconcile.Request
+		for _, mg := range l.GetItems() {
+			if meta.WasDeleted(mg) || mg.GetCondition(xpv1.TypeReady).Reason == v1alpha1.ReasonWaitingForMaintenanceWindow {
+				reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{Name: mg.GetName()}})
+			}
+		}
+		return reqs
+	}
+}
+
+// Opened passes MaintenanceWindow updates that open the window.
+var Opened = predicate.Funcs{
+	CreateFunc:  func(ctrlevent.CreateEvent) bool { return false },
+	DeleteFunc:  func(ctrlevent.DeleteEvent) bool { return false },
+	GenericFunc: func(ctrlevent.GenericEvent) bool { return false },
+	UpdateFunc: func(e ctrlevent.UpdateEvent) bool {
+		o, ok := e.ObjectOld.(*v1alpha1.MaintenanceWindow)
+		if !ok {
+			return false
+		}
+		n, ok := e.ObjectNew.(*v1alpha1.MaintenanceWindow)
+		if !ok {
+			return false
+		}
+		return !o.Status.AtProvider.Open && n.Status.AtProvider.Open
+	},
+}
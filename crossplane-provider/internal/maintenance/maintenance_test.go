@@ -0,0 +1,169 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maintenance
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/crossplane/provider-s4t/apis/iot/v1alpha1"
+)
+
+func TestState(t *testing.T) {
+	// Saturdays 02:00-06:00 in Rome, and the first of the month 22:00-23:00.
+	p := v1alpha1.MaintenanceWindowParameters{
+		TimeZone: ptr.To("Europe/Rome"),
+		Schedules: []v1alpha1.MaintenanceWindowSchedule{
+			{Start: "0 2 * * 6", Duration: metav1.Duration{Duration: 4 * time.Hour}},
+			{Start: "0 22 1 * *", Duration: metav1.Duration{Duration: time.Hour}},
+		},
+	}
+	rome, err := time.LoadLocation("Europe/Rome")
+	if err != nil {
+		t.Fatalf("LoadLocation: %v", err)
+	}
+	at := func(day, hour int) time.Time { return time.Date(2026, 8, day, hour, 0, 0, 0, rome) }
+
+	cases := map[string]struct {
+		now   time.Time
+		open  bool
+		until time.Time
+		next  time.Time
+	}{
+		"Closed":     {now: at(3, 12), next: at(8, 2)},
+		"Open":       {now: at(8, 3), open: true, until: at(8, 6), next: at(15, 2)},
+		"JustOpened": {now: at(8, 2), open: true, until: at(8, 6), next: at(15, 2)},
+		"JustClosed": {now: at(8, 6), next: at(15, 2)},
+		// The first of August 2026 is a Saturday, so at 22:30 only the
+		// monthly window is open.
+		"Monthly": {now: at(1, 22).Add(30 * time.Minute), open: true, until: at(1, 23), next: at(8, 2)},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			open, until, next, err := State(p, tc.now)
+			if err != nil {
+				t.Fatalf("State: %v", err)
+			}
+			if open != tc.open || !until.Equal(tc.until) || !next.Equal(tc.next) {
+				t.Errorf("State: want open=%t until=%s next=%s, got open=%t until=%s next=%s", tc.open, tc.until, tc.next, open, until, next)
+			}
+		})
+	}
+}
+
+func TestWindows(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := v1alpha1.SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatalf("AddToScheme: %v", err)
+	}
+	device := func(name, uuid, site string, ref *xpv1.Reference) *v1alpha1.Device {
+		d := &v1alpha1.Device{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"role": "sensor"}}}
+		if site != "" {
+			d.Labels[v1alpha1.LabelSite] = site
+		}
+		d.Spec.ForProvider.Uuid = uuid
+		d.Spec.ForProvider.MaintenanceWindowRef = ref
+		return d
+	}
+	window := func(name string) *v1alpha1.MaintenanceWindow {
+		return &v1alpha1.MaintenanceWindow{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	}
+	fleet := &v1alpha1.Fleet{ObjectMeta: metav1.ObjectMeta{Name: "sensors"}}
+	fleet.Spec.ForProvider.DeviceSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"role": "sensor"}}
+	fleet.Spec.ForProvider.MaintenanceWindowRef = &xpv1.Reference{Name: "fleet"}
+	parent := &v1alpha1.Site{ObjectMeta: metav1.ObjectMeta{Name: "italy"}}
+	parent.Spec.ForProvider.MaintenanceWindowRef = &xpv1.Reference{Name: "site"}
+	child := &v1alpha1.Site{ObjectMeta: metav1.ObjectMeta{Name: "messina"}}
+	child.Spec.ForProvider.ParentSite = "italy"
+
+	cases := map[string]struct {
+		objs []runtime.Object
+		want []string
+	}{
+		"Unknown": {
+			objs: []runtime.Object{window("device")},
+		},
+		"Device": {
+			objs: []runtime.Object{device("d", "b", "messina", &xpv1.Reference{Name: "device"}), fleet, parent, child, window("device")},
+			want: []string{"device"},
+		},
+		"Fleet": {
+			objs: []runtime.Object{device("d", "b", "messina", nil), fleet, parent, child, window("fleet")},
+			want: []string{"fleet"},
+		},
+		"InheritedSite": {
+			objs: []runtime.Object{device("d", "b", "messina", nil), parent, child, window("site")},
+			want: []string{"site"},
+		},
+		"None": {
+			objs: []runtime.Object{device("d", "b", "", nil)},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			kube := fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(tc.objs...).Build()
+			windows, err := Windows(context.Background(), kube, "b")
+			if err != nil {
+				t.Fatalf("Windows: %v", err)
+			}
+			var got []string
+			for _, w := range windows {
+				got = append(got, w.GetName())
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Windows: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCheckOverride(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := v1alpha1.SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatalf("AddToScheme: %v", err)
+	}
+	d := &v1alpha1.Device{ObjectMeta: metav1.ObjectMeta{Name: "d"}}
+	d.Spec.ForProvider.Uuid = "b"
+	d.Spec.ForProvider.MaintenanceWindowRef = &xpv1.Reference{Name: "never"}
+	// February 30th never comes.
+	w := &v1alpha1.MaintenanceWindow{ObjectMeta: metav1.ObjectMeta{Name: "never"}}
+	w.Spec.ForProvider.Schedules = []v1alpha1.MaintenanceWindowSchedule{{Start: "0 0 30 2 *", Duration: metav1.Duration{Duration: time.Hour}}}
+	kube := fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(d, w).Build()
+
+	i := &v1alpha1.BoardPluginInjection{ObjectMeta: metav1.ObjectMeta{Name: "i"}}
+	deferred, err := Check(context.Background(), kube, i, "b")
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	if deferred == nil || deferred.Window != "never" {
+		t.Errorf("Check: want deferred for window never, got %+v", deferred)
+	}
+
+	i.SetAnnotations(map[string]string{v1alpha1.AnnotationMaintenanceOverride: "true"})
+	if deferred, err := Check(context.Background(), kube, i, "b"); err != nil || deferred != nil {
+		t.Errorf("Check with override: want nil, nil, got %+v, %v", deferred, err)
+	}
+}
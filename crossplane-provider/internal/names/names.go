@@ -0,0 +1,48 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package names derives the names of the objects a resource generates.
+package names
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// MaxLength is the length of the names Child returns at most. It is that of
+// a DNS label, so that the names can also be used in those of the Services
+// and routes generated for them.
+const MaxLength = 63
+
+// hashLength is the length of the suffix that makes a name unique.
+const hashLength = 8
+
+// Child returns the name of the object generated for parts, typically the
+// name of the owner followed by those of what the object is generated for.
+// The parts are joined with dashes, truncated so that the name fits in
+// MaxLength, and suffixed with a hash of the parts, so that different parts
+// never map to the same name: "a-b" and "c" do not collide with "a" and
+// "b-c".
+func Child(parts ...string) string {
+	h := sha256.Sum256([]byte(strings.Join(parts, "/")))
+	prefix := strings.Join(parts, "-")
+	if max := MaxLength - hashLength - 1; len(prefix) > max {
+		prefix = prefix[:max]
+	}
+	prefix = strings.TrimRight(prefix, "-.")
+	return prefix + "-" + hex.EncodeToString(h[:])[:hashLength]
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package names
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestChild(t *testing.T) {
+	long := strings.Repeat("board", 20)
+
+	cases := map[string]struct {
+		parts      []string
+		wantPrefix string
+	}{
+		"Short": {parts: []string{"gateway", "rpi-01"}, wantPrefix: "gateway-rpi-01-"},
+		"Long":  {parts: []string{"gateway", long}, wantPrefix: ("gateway-" + long)[:MaxLength-hashLength-1] + "-"},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := Child(tc.parts...)
+			if !strings.HasPrefix(got, tc.wantPrefix) {
+				t.Errorf("Child(%q): want prefix %q, got %q", tc.parts, tc.wantPrefix, got)
+			}
+			if len(got) > MaxLength {
+				t.Errorf("Child(%q): %d characters, want at most %d", tc.parts, len(got), MaxLength)
+			}
+			if again := Child(tc.parts...); again != got {
+				t.Errorf("Child(%q): not stable, got %q and %q", tc.parts, got, again)
+			}
+		})
+	}
+
+	if a, b := Child("a-b", "c"), Child("a", "b-c"); a == b {
+		t.Errorf("Child: %q collides for different parts", a)
+	}
+}
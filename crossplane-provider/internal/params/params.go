@@ -0,0 +1,261 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package params renders plugin parameters for a board. String values of the
+// parameters may be Go templates, evaluated against the board's Device, its
+// Site and values read from ConfigMaps.
+package params
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"text/template"
+	tparse "text/template/parse"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/provider-s4t/apis/iot/v1alpha1"
+)
+
+const (
+	errDecode       = "cannot decode parameters"
+	errEncode       = "cannot encode rendered parameters"
+	errParse        = "cannot parse template at %s"
+	errExecute      = "cannot render template at %s"
+	errListDevices  = "cannot list Devices"
+	errNoDevice     = "no Device has board UUID %q"
+	errGetSite      = "cannot get Site %q"
+	errGetConfigMap = "cannot get ConfigMap %s/%s for value %q"
+	errNoKey        = "ConfigMap %s/%s has no key %q for value %q"
+)
+
+// Data is what templates are evaluated against.
+type Data struct {
+	Device Device
+
+	// Site is nil for a Device without a Site.
+	Site *Site
+
+	// Values are the TemplateValues, by name.
+	Values map[string]interface{}
+}
+
+// Device is the target Device, as templates see it.
+type Device struct {
+	Name        string
+	Uuid        string
+	Code        string
+	Type        string
+	Labels      map[string]string
+	Annotations map[string]string
+	Location    v1alpha1.Location
+}
+
+// Site is the Site of the target Device, as templates see it.
+type Site struct {
+	Name        string
+	Uuid        string
+	Location    string
+	Config      map[string]string
+	Labels      map[string]string
+	Annotations map[string]string
+}
+
+// Templated reports whether raw holds any template.
+func Templated(raw []byte) bool {
+	return bytes.Contains(raw, []byte("{{"))
+}
+
+// Validate decodes raw and parses every template in it, without rendering
+// them.
+func Validate(raw []byte) error {
+	if len(raw) == 0 {
+		return nil
+	}
+	var v interface{}
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return errors.Wrap(err, errDecode)
+	}
+	_, err := walk(v, "", func(path, s string) (interface{}, error) {
+		_, err := parse(path, s)
+		return s, err
+	})
+	return err
+}
+
+// Render returns raw with every template in its string values rendered
+// against d. A value that is a single template, such as
+// "{{ .Values.interval }}", takes the type of its output if that is JSON, so
+// that it can render a number, a boolean, a list or an object; toJson keeps
+// a string a string. Other rendered values are strings.
+func Render(raw []byte, d *Data) ([]byte, error) {
+	if !Templated(raw) {
+		return raw, nil
+	}
+	var v interface{}
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return nil, errors.Wrap(err, errDecode)
+	}
+	out, err := walk(v, "", func(path, s string) (interface{}, error) {
+		t, err := parse(path, s)
+		if err != nil {
+			return nil, err
+		}
+		var b strings.Builder
+		if err := t.Execute(&b, d); err != nil {
+			return nil, errors.Wrapf(err, errExecute, path)
+		}
+		var typed interface{}
+		if single(t) && json.Unmarshal([]byte(b.String()), &typed) == nil {
+			return typed, nil
+		}
+		return b.String(), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	rendered, err := json.Marshal(out)
+	return rendered, errors.Wrap(err, errEncode)
+}
+
+// funcs are the functions templates may call besides the builtin ones.
+var funcs = template.FuncMap{
+	"toJson": toJSON,
+}
+
+// toJSON encodes v as JSON, for a single template to render v with its type.
+func toJSON(v interface{}) (string, error) {
+	b, err := json.Marshal(v)
+	return string(b), err
+}
+
+func parse(path, s string) (*template.Template, error) {
+	t, err := template.New(path).Option("missingkey=error").Funcs(funcs).Parse(s)
+	return t, errors.Wrapf(err, errParse, path)
+}
+
+// single reports whether t is a single action, with no text around it.
+func single(t *template.Template) bool {
+	return t.Tree != nil && len(t.Tree.Root.Nodes) == 1 && t.Tree.Root.Nodes[0].Type() == tparse.NodeAction
+}
+
+// walk returns v with fn applied to every string value holding a template.
+// path is the JSON path of v.
+func walk(v interface{}, path string, fn func(path, s string) (interface{}, error)) (interface{}, error) {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, e := range t {
+			r, err := walk(e, path+"."+k, fn)
+			if err != nil {
+				return nil, err
+			}
+			t[k] = r
+		}
+	case []interface{}:
+		for i, e := range t {
+			r, err := walk(e, path+"["+strconv.Itoa(i)+"]", fn)
+			if err != nil {
+				return nil, err
+			}
+			t[i] = r
+		}
+	case string:
+		if strings.Contains(t, "{{") {
+			if path == "" {
+				path = "."
+			}
+			return fn(path, t)
+		}
+	}
+	return v, nil
+}
+
+// ForBoard returns the Data for templates rendered for board, the IoTronic
+// UUID of a Device, with values read from ConfigMaps.
+func ForBoard(ctx context.Context, kube client.Reader, board string, values []v1alpha1.TemplateValue) (*Data, error) {
+	l := &v1alpha1.DeviceList{}
+	if err := kube.List(ctx, l); err != nil {
+		return nil, errors.Wrap(err, errListDevices)
+	}
+	for i := range l.Items {
+		if d := &l.Items[i]; d.Spec.ForProvider.Uuid == board && !meta.WasDeleted(d) {
+			return ForDevice(ctx, kube, d, values)
+		}
+	}
+	return nil, errors.Errorf(errNoDevice, board)
+}
+
+// ForDevice returns the Data for templates rendered for Device dev, with
+// values read from ConfigMaps.
+func ForDevice(ctx context.Context, kube client.Reader, dev *v1alpha1.Device, values []v1alpha1.TemplateValue) (*Data, error) {
+	d := &Data{
+		Device: Device{
+			Name:        dev.GetName(),
+			Uuid:        dev.Spec.ForProvider.Uuid,
+			Code:        dev.Spec.ForProvider.Code,
+			Type:        dev.Spec.ForProvider.Type,
+			Labels:      dev.GetLabels(),
+			Annotations: dev.GetAnnotations(),
+		},
+		Values: make(map[string]interface{}, len(values)),
+	}
+	switch {
+	case dev.Status.AtProvider.Location != nil:
+		d.Device.Location = *dev.Status.AtProvider.Location
+	case len(dev.Spec.ForProvider.Location) > 0:
+		d.Device.Location = dev.Spec.ForProvider.Location[0]
+	}
+
+	if name := dev.GetLabels()[v1alpha1.LabelSite]; name != "" {
+		s := &v1alpha1.Site{}
+		if err := kube.Get(ctx, types.NamespacedName{Name: name}, s); err != nil {
+			return nil, errors.Wrapf(err, errGetSite, name)
+		}
+		d.Site = &Site{
+			Name:        s.GetName(),
+			Uuid:        s.Spec.ForProvider.Uuid,
+			Location:    s.Spec.ForProvider.Location,
+			Config:      s.Spec.ForProvider.Config,
+			Labels:      s.GetLabels(),
+			Annotations: s.GetAnnotations(),
+		}
+	}
+
+	for _, v := range values {
+		ref := v.ConfigMapKeyRef
+		cm := &corev1.ConfigMap{}
+		if err := kube.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, cm); err != nil {
+			return nil, errors.Wrapf(err, errGetConfigMap, ref.Namespace, ref.Name, v.Name)
+		}
+		if ref.Key == "" {
+			d.Values[v.Name] = cm.Data
+			continue
+		}
+		val, ok := cm.Data[ref.Key]
+		if !ok {
+			return nil, errors.Errorf(errNoKey, ref.Namespace, ref.Name, ref.Key, v.Name)
+		}
+		d.Values[v.Name] = val
+	}
+	return d, nil
+}
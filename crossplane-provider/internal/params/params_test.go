@@ -0,0 +1,135 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package params
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/crossplane/provider-s4t/apis/iot/v1alpha1"
+)
+
+func TestRender(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(s); err != nil {
+		t.Fatalf("AddToScheme: %v", err)
+	}
+	if err := v1alpha1.SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatalf("AddToScheme: %v", err)
+	}
+	d := &v1alpha1.Device{ObjectMeta: metav1.ObjectMeta{
+		Name:   "gw-01",
+		Labels: map[string]string{v1alpha1.LabelSite: "messina", "zone": "north"},
+	}}
+	d.Spec.ForProvider.Uuid = "b1"
+	d.Spec.ForProvider.Code = "GW01"
+	site := &v1alpha1.Site{ObjectMeta: metav1.ObjectMeta{Name: "messina"}}
+	site.Spec.ForProvider.Config = map[string]string{"broker": "mqtt.messina.example.org"}
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "iot", Name: "endpoints"},
+		Data:       map[string]string{"GW01": "https://api.example.org/gw01", "default": "https://api.example.org", "interval": "5"},
+	}
+	kube := fake.NewClientBuilder().WithScheme(s).WithObjects(d, site, cm).Build()
+
+	values := []v1alpha1.TemplateValue{
+		{Name: "endpoints", ConfigMapKeyRef: v1alpha1.ConfigMapKeySelector{Namespace: "iot", Name: "endpoints"}},
+		{Name: "fallback", ConfigMapKeyRef: v1alpha1.ConfigMapKeySelector{Namespace: "iot", Name: "endpoints", Key: "default"}},
+		{Name: "interval", ConfigMapKeyRef: v1alpha1.ConfigMapKeySelector{Namespace: "iot", Name: "endpoints", Key: "interval"}},
+	}
+
+	cases := map[string]struct {
+		raw     string
+		want    string
+		wantErr bool
+	}{
+		"Static": {
+			raw:  `{"interval":5}`,
+			want: `{"interval":5}`,
+		},
+		"Device": {
+			raw:  `{"code":"{{ .Device.Code }}","zone":"{{ .Device.Labels.zone }}","interval":5}`,
+			want: `{"code":"GW01","interval":5,"zone":"north"}`,
+		},
+		"SiteAndValues": {
+			raw:  `{"broker":"{{ .Site.Config.broker }}","urls":["{{ index .Values.endpoints .Device.Code }}","{{ .Values.fallback }}"]}`,
+			want: `{"broker":"mqtt.messina.example.org","urls":["https://api.example.org/gw01","https://api.example.org"]}`,
+		},
+		"Typed": {
+			raw:  `{"interval":"{{ .Values.interval }}","id":"{{ .Values.interval }}0"}`,
+			want: `{"id":"50","interval":5}`,
+		},
+		"ToJson": {
+			raw:  `{"interval":"{{ .Values.interval | toJson }}","zone":"{{ .Device.Labels.zone | toJson }}"}`,
+			want: `{"interval":"5","zone":"north"}`,
+		},
+		"MissingLabel": {
+			raw:     `{"rack":"{{ .Device.Labels.rack }}"}`,
+			wantErr: true,
+		},
+		"BadTemplate": {
+			raw:     `{"code":"{{ .Device.Code "}`,
+			wantErr: true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			data, err := ForBoard(context.Background(), kube, "b1", values)
+			if err != nil {
+				t.Fatalf("ForBoard: %v", err)
+			}
+			got, err := Render([]byte(tc.raw), data)
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("Render: want error, got %s", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Render: %v", err)
+			}
+			if string(got) != tc.want {
+				t.Errorf("Render: want %s, got %s", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	cases := map[string]struct {
+		raw     string
+		wantErr bool
+	}{
+		"Empty":    {},
+		"Static":   {raw: `{"interval":5}`},
+		"Template": {raw: `{"code":"{{ .Device.Code }}"}`},
+		"BadJSON":  {raw: `{"code":`, wantErr: true},
+		"Unclosed": {raw: `{"code":"{{ .Device.Code"}`, wantErr: true},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if err := Validate([]byte(tc.raw)); (err != nil) != tc.wantErr {
+				t.Errorf("Validate: want error %t, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
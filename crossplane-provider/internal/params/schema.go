@@ -0,0 +1,202 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package params
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	openapierrors "k8s.io/kube-openapi/pkg/validation/errors"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/kube-openapi/pkg/validation/strfmt"
+	"k8s.io/kube-openapi/pkg/validation/validate"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/provider-s4t/apis/iot/v1alpha1"
+)
+
+const (
+	errDecodeSchema = "cannot decode parameters schema"
+	errSchemaRef    = "parameters schema references are not supported: %s"
+	errListPlugins  = "cannot list Plugins"
+	errGetPlugin    = "cannot get Plugin %q"
+
+	// secretMismatch replaces the message for a parameter set from a
+	// Secret, which may quote the value.
+	secretMismatch = "value from Secret does not match the parameters schema"
+)
+
+// A Schema validates plugin parameters against the JSON Schema a Plugin
+// declares.
+type Schema struct {
+	v *validate.SchemaValidator
+}
+
+// NewSchema returns the Schema raw declares.
+func NewSchema(raw []byte) (*Schema, error) {
+	var doc interface{}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, errors.Wrap(err, errDecodeSchema)
+	}
+	// The validator panics on references it cannot resolve.
+	if path := ref(doc, ""); path != "" {
+		return nil, errors.Errorf(errSchemaRef, path)
+	}
+	s := &spec.Schema{}
+	if err := json.Unmarshal(raw, s); err != nil {
+		return nil, errors.Wrap(err, errDecodeSchema)
+	}
+	return &Schema{v: validate.NewSchemaValidator(s, nil, "", strfmt.Default)}, nil
+}
+
+// ref returns the path of the first $ref in a decoded schema, or "".
+func ref(v interface{}, path string) string {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		if _, ok := t["$ref"]; ok {
+			return path + ".$ref"
+		}
+		for k, e := range t {
+			if p := ref(e, path+"."+k); p != "" {
+				return p
+			}
+		}
+	case []interface{}:
+		for _, e := range t {
+			if p := ref(e, path); p != "" {
+				return p
+			}
+		}
+	}
+	return ""
+}
+
+// Validate validates parameters that are about to be passed to the plugin,
+// rendered and with the values of the Secrets of from merged in. Errors on
+// parameters set from Secrets do not quote the value.
+func (s *Schema) Validate(fld *field.Path, raw []byte, from []v1alpha1.ParameterFrom) field.ErrorList {
+	return s.validate(fld, raw, nil, names(from))
+}
+
+// ValidateSpec validates parameters as they are declared, before they are
+// rendered for a board. Values that hold templates and parameters set from
+// Secrets are not known yet; they are left to Validate.
+func (s *Schema) ValidateSpec(fld *field.Path, raw []byte, from []v1alpha1.ParameterFrom) field.ErrorList {
+	var v interface{}
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return field.ErrorList{field.Invalid(fld, string(raw), err.Error())}
+		}
+	}
+	skip := names(from)
+	_, _ = walk(v, "", func(path, s string) (interface{}, error) {
+		skip = append(skip, path)
+		return s, nil
+	})
+	return s.validate(fld, raw, skip, nil)
+}
+
+func (s *Schema) validate(fld *field.Path, raw []byte, skip, redact []string) field.ErrorList {
+	v := interface{}(map[string]interface{}{})
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return field.ErrorList{field.Invalid(fld, field.OmitValueType{}, err.Error())}
+		}
+	}
+	var errs field.ErrorList
+	for _, e := range s.v.Validate(v).Errors {
+		ve, ok := e.(*openapierrors.Validation)
+		if !ok {
+			errs = append(errs, field.Invalid(fld, field.OmitValueType{}, e.Error()))
+			continue
+		}
+		path := "." + strings.TrimPrefix(ve.Name, ".")
+		if under(path, skip) {
+			continue
+		}
+		p := fld
+		if path != "." {
+			p = fld.Child(path[1:])
+		}
+		switch {
+		case under(path, redact):
+			errs = append(errs, field.Invalid(p, field.OmitValueType{}, secretMismatch))
+		case ve.Code() == openapierrors.RequiredFailCode:
+			errs = append(errs, field.Required(p, ""))
+		default:
+			errs = append(errs, field.Invalid(p, field.OmitValueType{}, ve.Error()))
+		}
+	}
+	return errs
+}
+
+// names returns the JSON paths of the parameters of from.
+func names(from []v1alpha1.ParameterFrom) []string {
+	out := make([]string, 0, len(from))
+	for _, f := range from {
+		out = append(out, "."+f.Name)
+	}
+	return out
+}
+
+// under reports whether the JSON path p is, or is within, one of paths.
+func under(p string, paths []string) bool {
+	for _, q := range paths {
+		if p == q || strings.HasPrefix(p, q+".") || strings.HasPrefix(p, q+"[") {
+			return true
+		}
+	}
+	return false
+}
+
+// SchemaFor returns the Schema of the Plugin named name or, when name is
+// empty, of the Plugin whose IoTronic UUID is uuid. It returns nil when that
+// Plugin declares none or does not exist (yet).
+func SchemaFor(ctx context.Context, kube client.Reader, name, uuid string) (*Schema, error) {
+	var p *v1alpha1.Plugin
+	switch {
+	case name != "":
+		p = &v1alpha1.Plugin{}
+		err := kube.Get(ctx, client.ObjectKey{Name: name}, p)
+		if kerrors.IsNotFound(err) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, errGetPlugin, name)
+		}
+	case uuid != "":
+		l := &v1alpha1.PluginList{}
+		if err := kube.List(ctx, l); err != nil {
+			return nil, errors.Wrap(err, errListPlugins)
+		}
+		for i := range l.Items {
+			if l.Items[i].Spec.ForProvider.Uuid == uuid && !meta.WasDeleted(&l.Items[i]) {
+				p = &l.Items[i]
+				break
+			}
+		}
+	}
+	if p == nil || p.Spec.ForProvider.ParametersSchema == nil {
+		return nil, nil
+	}
+	return NewSchema(p.Spec.ForProvider.ParametersSchema.Raw)
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package params
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/crossplane/provider-s4t/apis/iot/v1alpha1"
+)
+
+const testSchema = `{
+	"type": "object",
+	"required": ["interval", "apiKey"],
+	"properties": {
+		"interval": {"type": "integer", "minimum": 1},
+		"apiKey": {"type": "string", "minLength": 32},
+		"topic": {"type": "string", "pattern": "^sensors/"},
+		"targets": {"type": "array", "items": {"type": "string", "enum": ["a", "b"]}}
+	},
+	"additionalProperties": false
+}`
+
+// fields returns the sorted field paths of errs.
+func fields(errs field.ErrorList) []string {
+	out := []string{}
+	for _, e := range errs {
+		out = append(out, e.Field)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func TestNewSchema(t *testing.T) {
+	cases := map[string]struct {
+		raw     string
+		wantErr bool
+	}{
+		"Valid":     {raw: testSchema},
+		"NotJSON":   {raw: `{"type":`, wantErr: true},
+		"Reference": {raw: `{"properties": {"a": {"$ref": "#/definitions/a"}}}`, wantErr: true},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewSchema([]byte(tc.raw)); (err != nil) != tc.wantErr {
+				t.Errorf("NewSchema: want error %t, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestSchemaValidateSpec(t *testing.T) {
+	s, err := NewSchema([]byte(testSchema))
+	if err != nil {
+		t.Fatalf("NewSchema: %v", err)
+	}
+	fld := field.NewPath("spec", "forProvider", "parameters")
+	apiKey := []v1alpha1.ParameterFrom{from("apiKey", "cloud", "apiKey")}
+
+	cases := map[string]struct {
+		raw  string
+		from []v1alpha1.ParameterFrom
+		want []string
+	}{
+		"Valid": {
+			raw:  `{"interval":5,"topic":"sensors/gw","targets":["a"]}`,
+			from: apiKey,
+			want: []string{},
+		},
+		"Invalid": {
+			raw:  `{"interval":0,"topic":"gw","targets":["a","c"],"extra":true}`,
+			from: apiKey,
+			want: []string{
+				"spec.forProvider.parameters",
+				"spec.forProvider.parameters.interval",
+				"spec.forProvider.parameters.targets[1]",
+				"spec.forProvider.parameters.topic",
+			},
+		},
+		"Missing": {
+			raw:  `{}`,
+			want: []string{"spec.forProvider.parameters.apiKey", "spec.forProvider.parameters.interval"},
+		},
+		"Templated": {
+			raw:  `{"interval":"{{ .Values.interval }}","topic":"{{ .Device.Code }}"}`,
+			from: apiKey,
+			want: []string{},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, fields(s.ValidateSpec(fld, []byte(tc.raw), tc.from))); diff != "" {
+				t.Errorf("ValidateSpec: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSchemaValidate(t *testing.T) {
+	s, err := NewSchema([]byte(testSchema))
+	if err != nil {
+		t.Fatalf("NewSchema: %v", err)
+	}
+	apiKey := []v1alpha1.ParameterFrom{from("apiKey", "cloud", "apiKey")}
+
+	// Parameters are validated as rendered, so an integer rendered as a
+	// string does not match.
+	errs := s.Validate(field.NewPath("parameters"), []byte(`{"interval":"5","apiKey":"s3cr3t"}`), apiKey)
+	if diff := cmp.Diff([]string{"parameters.apiKey", "parameters.interval"}, fields(errs)); diff != "" {
+		t.Errorf("Validate: -want, +got:\n%s", diff)
+	}
+	if strings.Contains(errs.ToAggregate().Error(), "s3cr3t") {
+		t.Errorf("Validate: error reveals the secret value: %v", errs.ToAggregate())
+	}
+}
+
+func TestSchemaValidateRendered(t *testing.T) {
+	s, err := NewSchema([]byte(testSchema))
+	if err != nil {
+		t.Fatalf("NewSchema: %v", err)
+	}
+	d := &Data{Device: Device{Code: "GW01"}, Values: map[string]interface{}{"interval": "5", "targets": `["a","b"]`}}
+
+	// A value that is a single template takes the type of its output.
+	raw := `{"interval":"{{ .Values.interval }}","apiKey":"0123456789abcdef0123456789abcdef","topic":"sensors/{{ .Device.Code }}","targets":"{{ .Values.targets }}"}`
+	rendered, err := Render([]byte(raw), d)
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if errs := s.Validate(field.NewPath("parameters"), rendered, nil); len(errs) != 0 {
+		t.Errorf("Validate(%s): %v", rendered, errs.ToAggregate())
+	}
+}
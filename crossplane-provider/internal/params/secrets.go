@@ -0,0 +1,165 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package params
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/provider-s4t/apis/iot/v1alpha1"
+)
+
+// Errors name the Secret, key and parameter involved, never the value read.
+const (
+	errGetSecret   = "cannot get Secret %s/%s for parameter %q"
+	errNotSource   = "Secret %s/%s for parameter %q is not labelled %s: \"true\""
+	errNoSecretKey = "Secret %s/%s has no key %q for parameter %q"
+	errNotObject   = "cannot set parameter %q: %s is not an object"
+)
+
+// Merge returns raw with each parameter of from set to the value of its
+// Secret key. Only Secrets labelled as parameter sources are read.
+// Parameters are set as strings, replacing any value raw holds.
+// The result holds secret values and must only be sent to IoTronic.
+func Merge(ctx context.Context, kube client.Reader, raw []byte, from []v1alpha1.ParameterFrom) ([]byte, error) {
+	if len(from) == 0 {
+		return raw, nil
+	}
+	values := make(map[string]string, len(from))
+	for _, f := range from {
+		ref := f.SecretKeyRef
+		s := &corev1.Secret{}
+		if err := kube.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, s); err != nil {
+			return nil, errors.Wrapf(err, errGetSecret, ref.Namespace, ref.Name, f.Name)
+		}
+		if s.GetLabels()[v1alpha1.LabelParameterSource] != "true" {
+			return nil, errors.Errorf(errNotSource, ref.Namespace, ref.Name, f.Name, v1alpha1.LabelParameterSource)
+		}
+		v, ok := s.Data[ref.Key]
+		if !ok {
+			return nil, errors.Errorf(errNoSecretKey, ref.Namespace, ref.Name, ref.Key, f.Name)
+		}
+		values[f.Name] = string(v)
+	}
+	return set(raw, from, func(name string) (interface{}, bool) {
+		return values[name], true
+	})
+}
+
+// Versions returns the UID and resourceVersion of the Secret of each
+// parameter of from, so that a digest of raw and Versions changes whenever a
+// value Merge would set does. Digests of the merged parameters must never be
+// recorded: the values of short secrets could be recovered from them.
+func Versions(ctx context.Context, kube client.Reader, from []v1alpha1.ParameterFrom) ([]byte, error) {
+	var out []byte
+	for _, f := range from {
+		ref := f.SecretKeyRef
+		s := &corev1.Secret{}
+		if err := kube.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, s); err != nil {
+			return nil, errors.Wrapf(err, errGetSecret, ref.Namespace, ref.Name, f.Name)
+		}
+		out = append(out, fmt.Sprintf("%s=%s/%s/%s:%s@%s\n", f.Name, ref.Namespace, ref.Name, ref.Key, s.GetUID(), s.GetResourceVersion())...)
+	}
+	return out, nil
+}
+
+// Strip returns raw without the parameters of from, so that parameters read
+// back from IoTronic can be recorded without their secret values.
+func Strip(raw []byte, from []v1alpha1.ParameterFrom) []byte {
+	if len(from) == 0 || len(raw) == 0 {
+		return raw
+	}
+	out, err := set(raw, from, func(string) (interface{}, bool) { return nil, false })
+	if err != nil {
+		// Parameters that are not an object hold no parameter of from.
+		return raw
+	}
+	return out
+}
+
+// set returns raw with each parameter of from set to what fn returns for its
+// name, or removed when fn returns false.
+func set(raw []byte, from []v1alpha1.ParameterFrom, fn func(name string) (interface{}, bool)) ([]byte, error) {
+	root := map[string]interface{}{}
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &root); err != nil {
+			return nil, errors.Wrap(err, errDecode)
+		}
+	}
+params:
+	for _, f := range from {
+		v, keep := fn(f.Name)
+		keys := strings.Split(f.Name, ".")
+		obj := root
+		for i, k := range keys[:len(keys)-1] {
+			next, ok := obj[k]
+			if !ok && !keep {
+				continue params
+			}
+			if !ok {
+				next = map[string]interface{}{}
+				obj[k] = next
+			}
+			m, ok := next.(map[string]interface{})
+			if !ok {
+				return nil, errors.Errorf(errNotObject, f.Name, strings.Join(keys[:i+1], "."))
+			}
+			obj = m
+		}
+		if keep {
+			obj[keys[len(keys)-1]] = v
+		} else {
+			delete(obj, keys[len(keys)-1])
+		}
+	}
+	out, err := json.Marshal(root)
+	return out, errors.Wrap(err, errEncode)
+}
+
+// Referencing maps a Secret to the resources listed by newList that set
+// parameters from it, so that a change to the Secret is acted on as soon as
+// it is made rather than on their next poll.
+func Referencing(kube client.Client, newList func() resource.ManagedList, from func(resource.Managed) []v1alpha1.ParameterFrom) handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		l := newList()
+		if err := kube.List(ctx, l); err != nil {
+			log.Printf("Error listing resources for Secret %s/%s: %v", obj.GetNamespace(), obj.GetName(), err)
+			return nil
+		}
+		var reqs []reconcile.Request
+		for _, mg := range l.GetItems() {
+			for _, f := range from(mg) {
+				if f.SecretKeyRef.Namespace == obj.GetNamespace() && f.SecretKeyRef.Name == obj.GetName() {
+					reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{Name: mg.GetName()}})
+					break
+				}
+			}
+		}
+		return reqs
+	}
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package params
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/crossplane/provider-s4t/apis/iot/v1alpha1"
+)
+
+func from(name, secret, key string) v1alpha1.ParameterFrom {
+	return v1alpha1.ParameterFrom{
+		Name: name,
+		SecretKeyRef: xpv1.SecretKeySelector{
+			SecretReference: xpv1.SecretReference{Namespace: "iot", Name: secret},
+			Key:             key,
+		},
+	}
+}
+
+func TestMerge(t *testing.T) {
+	kube := fake.NewClientBuilder().WithObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "iot", Name: "cloud", Labels: map[string]string{v1alpha1.LabelParameterSource: "true"}},
+		Data:       map[string][]byte{"apiKey": []byte("s3cr3t")},
+	}, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "iot", Name: "admin"},
+		Data:       map[string][]byte{"token": []byte("s3cr3t")},
+	}).Build()
+
+	cases := map[string]struct {
+		raw     string
+		from    []v1alpha1.ParameterFrom
+		want    string
+		wantErr bool
+	}{
+		"NoSecrets": {
+			raw:  `{"interval":5}`,
+			want: `{"interval":5}`,
+		},
+		"TopLevel": {
+			raw:  `{"interval":5,"apiKey":"placeholder"}`,
+			from: []v1alpha1.ParameterFrom{from("apiKey", "cloud", "apiKey")},
+			want: `{"apiKey":"s3cr3t","interval":5}`,
+		},
+		"Nested": {
+			raw:  `{"cloud":{"region":"eu"}}`,
+			from: []v1alpha1.ParameterFrom{from("cloud.auth.key", "cloud", "apiKey")},
+			want: `{"cloud":{"auth":{"key":"s3cr3t"},"region":"eu"}}`,
+		},
+		"NoParameters": {
+			from: []v1alpha1.ParameterFrom{from("apiKey", "cloud", "apiKey")},
+			want: `{"apiKey":"s3cr3t"}`,
+		},
+		"NotAnObject": {
+			raw:     `{"cloud":"eu"}`,
+			from:    []v1alpha1.ParameterFrom{from("cloud.key", "cloud", "apiKey")},
+			wantErr: true,
+		},
+		"MissingSecret": {
+			from:    []v1alpha1.ParameterFrom{from("apiKey", "other", "apiKey")},
+			wantErr: true,
+		},
+		"NotASource": {
+			from:    []v1alpha1.ParameterFrom{from("token", "admin", "token")},
+			wantErr: true,
+		},
+		"MissingKey": {
+			from:    []v1alpha1.ParameterFrom{from("apiKey", "cloud", "token")},
+			wantErr: true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := Merge(context.Background(), kube, []byte(tc.raw), tc.from)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("Merge: want error, got %s", got)
+				}
+				if strings.Contains(err.Error(), "s3cr3t") {
+					t.Errorf("Merge: error reveals the secret value: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Merge: %v", err)
+			}
+			if string(got) != tc.want {
+				t.Errorf("Merge: want %s, got %s", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestVersions(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "iot", Name: "cloud", UID: "cloud-uid", Labels: map[string]string{v1alpha1.LabelParameterSource: "true"}},
+		Data:       map[string][]byte{"apiKey": []byte("s3cr3t")},
+	}
+	kube := fake.NewClientBuilder().WithObjects(secret).Build()
+	fr := []v1alpha1.ParameterFrom{from("apiKey", "cloud", "apiKey")}
+
+	before, err := Versions(context.Background(), kube, fr)
+	if err != nil {
+		t.Fatalf("Versions: %v", err)
+	}
+	if strings.Contains(string(before), "s3cr3t") || !strings.Contains(string(before), "cloud-uid") {
+		t.Errorf("Versions: want the Secret UID and not its value, got %s", before)
+	}
+
+	secret.Data["apiKey"] = []byte("n3w")
+	if err := kube.Update(context.Background(), secret); err != nil {
+		t.Fatal(err)
+	}
+	after, err := Versions(context.Background(), kube, fr)
+	if err != nil {
+		t.Fatalf("Versions: %v", err)
+	}
+	if string(before) == string(after) {
+		t.Error("Versions: want a change to the Secret to change the versions")
+	}
+
+	if _, err := Versions(context.Background(), kube, []v1alpha1.ParameterFrom{from("apiKey", "other", "apiKey")}); err == nil {
+		t.Error("Versions: want error for a missing Secret")
+	}
+}
+
+func TestStrip(t *testing.T) {
+	fr := []v1alpha1.ParameterFrom{from("apiKey", "cloud", "apiKey"), from("cloud.auth.key", "cloud", "apiKey")}
+
+	cases := map[string]struct {
+		raw  string
+		want string
+	}{
+		"Secrets": {
+			raw:  `{"apiKey":"s3cr3t","cloud":{"auth":{"key":"s3cr3t"},"region":"eu"},"interval":5}`,
+			want: `{"cloud":{"auth":{},"region":"eu"},"interval":5}`,
+		},
+		"Absent": {
+			raw:  `{"interval":5}`,
+			want: `{"interval":5}`,
+		},
+		"Empty": {},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := Strip([]byte(tc.raw), fr); string(got) != tc.want {
+				t.Errorf("Strip: want %s, got %s", tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,233 @@
+/*
+ Copyright 2022 The Crossplane Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package providerconfig selects the ProviderConfig, and with it the
+// Stack4Things deployment, a managed resource is reconciled against.
+package providerconfig
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+
+	s4t "github.com/MIKE9708/s4t-sdk-go/pkg/api"
+	read_config "github.com/MIKE9708/s4t-sdk-go/pkg/read_conf"
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/provider-s4t/apis/iot/v1alpha1"
+	apisv1alpha1 "github.com/crossplane/provider-s4t/apis/v1alpha1"
+)
+
+const (
+	// DefaultName is the ProviderConfig used by resources that neither set
+	// one nor belong to a site that does.
+	DefaultName = "s4t-provider-domain"
+
+	// DefaultKeystoneEndpoint and DefaultIotronicEndpoint are used when a
+	// ProviderConfig does not set its endpoints.
+	DefaultKeystoneEndpoint = "http://keystone.default.svc.cluster.local:5000/v3"
+	DefaultIotronicEndpoint = "http://iotronic-conductor.default.svc.cluster.local:8812"
+
+	// placeholder is the providerConfigRef name Crossplane defaults every
+	// managed resource to. It is treated as unset.
+	placeholder = "default"
+
+	defaultIotronicPort = "8812"
+	defaultKeystonePort = "5000"
+
+	errGetSite = "cannot get Site %q"
+	errGetPC   = "cannot get ProviderConfig %q"
+	errParse   = "cannot parse IoTronic endpoint %q"
+
+	errCredentials   = "cannot decode credentials"
+	errParseKeystone = "cannot parse Keystone endpoint %q"
+	errAuthenticate  = "cannot authenticate with Keystone"
+)
+
+// Name returns the name of the ProviderConfig mg should use: its own
+// providerConfigRef if set, otherwise that of the nearest site up the
+// hierarchy that sets one, starting from the site mg is labelled with (or,
+// for a Site, its parent), otherwise DefaultName.
+func Name(ctx context.Context, kube client.Client, mg resource.Managed) (string, error) {
+	if n := explicit(mg); n != "" {
+		return n, nil
+	}
+
+	site := mg.GetLabels()[v1alpha1.LabelSite]
+	if s, ok := mg.(*v1alpha1.Site); ok {
+		site = s.Spec.ForProvider.ParentSite
+	}
+
+	seen := map[string]bool{}
+	for site != "" && !seen[site] {
+		seen[site] = true
+		s := &v1alpha1.Site{}
+		if err := kube.Get(ctx, types.NamespacedName{Name: site}, s); err != nil {
+			return "", errors.Wrapf(err, errGetSite, site)
+		}
+		if n := explicit(s); n != "" {
+			return n, nil
+		}
+		site = s.Spec.ForProvider.ParentSite
+	}
+	return DefaultName, nil
+}
+
+// Resolve returns the ProviderConfig mg should use, as chosen by Name.
+func Resolve(ctx context.Context, kube client.Client, mg resource.Managed) (*apisv1alpha1.ProviderConfig, error) {
+	name, err := Name(ctx, kube, mg)
+	if err != nil {
+		return nil, err
+	}
+	pc := &apisv1alpha1.ProviderConfig{}
+	if err := kube.Get(ctx, types.NamespacedName{Name: name}, pc); err != nil {
+		return nil, errors.Wrapf(err, errGetPC, name)
+	}
+	return pc, nil
+}
+
+// KeystoneEndpoint returns the Keystone endpoint of pc.
+func KeystoneEndpoint(pc *apisv1alpha1.ProviderConfig) string {
+	if pc.Spec.KeystoneEndpoint != "" {
+		return pc.Spec.KeystoneEndpoint
+	}
+	return DefaultKeystoneEndpoint
+}
+
+// IotronicEndpoint returns the IoTronic endpoint of pc.
+func IotronicEndpoint(pc *apisv1alpha1.ProviderConfig) string {
+	if pc.Spec.IotronicEndpoint != "" {
+		return pc.Spec.IotronicEndpoint
+	}
+	return DefaultIotronicEndpoint
+}
+
+// Connect returns a client authenticated with creds, a JSON object holding a
+// username, password and domain, against the Keystone endpoint keystone, or
+// DefaultKeystoneEndpoint when empty. The endpoint is set on the client
+// itself, so that resources of different ProviderConfigs authenticate against
+// their own Keystone concurrently. The client is pointed at the default
+// IoTronic endpoint until Configure is called.
+func Connect(creds []byte, keystone string) (*s4t.Client, error) {
+	var result map[string]string
+	if err := json.Unmarshal(creds, &result); err != nil {
+		return nil, errors.Wrap(err, errCredentials)
+	}
+	auth := read_config.FormatAuthRequ(result["username"], result["password"], result["domain"])
+
+	if keystone == "" {
+		keystone = DefaultKeystoneEndpoint
+	}
+	u, err := url.Parse(keystone)
+	if err != nil || u.Host == "" {
+		return nil, errors.Errorf(errParseKeystone, keystone)
+	}
+	c := s4t.NewClient(u.Scheme + "://" + u.Hostname())
+	c.AuthPort = u.Port()
+	if c.AuthPort == "" {
+		c.AuthPort = defaultKeystonePort
+	}
+	c.Port = defaultIotronicPort
+
+	token, err := c.Authenticate(c, auth)
+	if err != nil {
+		return nil, errors.Wrap(err, errAuthenticate)
+	}
+	c.AuthToken = token
+
+	iotronic, _ := url.Parse(DefaultIotronicEndpoint)
+	c.Endpoint = iotronic.Scheme + "://" + iotronic.Hostname()
+	return c, nil
+}
+
+// Configure points an authenticated client at the IoTronic endpoint of pc.
+func Configure(c *s4t.Client, pc *apisv1alpha1.ProviderConfig) error {
+	u, err := url.Parse(IotronicEndpoint(pc))
+	if err != nil || u.Host == "" {
+		return errors.Errorf(errParse, IotronicEndpoint(pc))
+	}
+	c.Endpoint = u.Scheme + "://" + u.Hostname()
+	c.Port = u.Port()
+	if c.Port == "" {
+		c.Port = defaultIotronicPort
+	}
+	return nil
+}
+
+// A Tracker records that a managed resource uses the ProviderConfig it
+// resolves to with Name, rather than the one its providerConfigRef names,
+// so that a ProviderConfig serving a site is not deleted while in use.
+type Tracker struct {
+	kube    client.Client
+	tracker *resource.ProviderConfigUsageTracker
+}
+
+// NewTracker returns a Tracker that records ProviderConfigUsages with kube.
+func NewTracker(kube client.Client) *Tracker {
+	return &Tracker{
+		kube:    kube,
+		tracker: resource.NewProviderConfigUsageTracker(kube, &apisv1alpha1.ProviderConfigUsage{}),
+	}
+}
+
+// Track records that mg uses the ProviderConfig Name resolves it to.
+func (t *Tracker) Track(ctx context.Context, mg resource.Managed) error {
+	name, err := Name(ctx, t.kube, mg)
+	if err != nil {
+		return err
+	}
+	return t.tracker.Track(ctx, resolved{Managed: mg, name: name})
+}
+
+// resolved is a managed resource whose providerConfigRef is the
+// ProviderConfig it resolves to.
+type resolved struct {
+	resource.Managed
+	name string
+}
+
+func (r resolved) GetProviderConfigReference() *xpv1.Reference {
+	return &xpv1.Reference{Name: r.name}
+}
+
+// Inherit sets the ProviderConfig of child, a resource owner generates to
+// act on the board of Device d, to the one serving d: d's own
+// providerConfigRef if set, otherwise that of d's site, through the site
+// label, otherwise owner's.
+func Inherit(child, owner resource.Managed, d *v1alpha1.Device) {
+	switch site := d.GetLabels()[v1alpha1.LabelSite]; {
+	case explicit(d) != "":
+		child.SetProviderConfigReference(d.GetProviderConfigReference().DeepCopy())
+	case site != "":
+		meta.AddLabels(child, map[string]string{v1alpha1.LabelSite: site})
+		child.SetProviderConfigReference(&xpv1.Reference{Name: placeholder})
+	default:
+		child.SetProviderConfigReference(owner.GetProviderConfigReference().DeepCopy())
+	}
+}
+
+func explicit(mg resource.Managed) string {
+	ref := mg.GetProviderConfigReference()
+	if ref == nil || ref.Name == placeholder {
+		return ""
+	}
+	return ref.Name
+}
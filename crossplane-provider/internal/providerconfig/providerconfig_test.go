@@ -0,0 +1,180 @@
+/*
+ Copyright 2022 The Crossplane Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package providerconfig
+
+import (
+	"context"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/crossplane/provider-s4t/apis"
+	"github.com/crossplane/provider-s4t/apis/iot/v1alpha1"
+	apisv1alpha1 "github.com/crossplane/provider-s4t/apis/v1alpha1"
+)
+
+func site(name, parent, pc string) *v1alpha1.Site {
+	s := &v1alpha1.Site{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	s.Spec.ForProvider.ParentSite = parent
+	s.SetProviderConfigReference(&xpv1.Reference{Name: pc})
+	return s
+}
+
+func device(site, pc string) *v1alpha1.Device {
+	d := &v1alpha1.Device{ObjectMeta: metav1.ObjectMeta{Name: "board"}}
+	if site != "" {
+		d.SetLabels(map[string]string{v1alpha1.LabelSite: site})
+	}
+	if pc != "" {
+		d.SetProviderConfigReference(&xpv1.Reference{Name: pc})
+	}
+	return d
+}
+
+func TestName(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := apis.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	kube := fake.NewClientBuilder().WithScheme(s).WithObjects(
+		site("edge", "staging", "default"),
+		site("staging", "production", "s4t-staging"),
+		site("production", "", "default"),
+	).Build()
+
+	cases := map[string]struct {
+		reason string
+		mg     resource.Managed
+		want   string
+	}{
+		"Explicit": {
+			reason: "A resource's own providerConfigRef should win over its site's.",
+			mg:     device("edge", "s4t-edge"),
+			want:   "s4t-edge",
+		},
+		"Inherited": {
+			reason: "A resource should use the nearest ancestor site setting a ProviderConfig.",
+			mg:     device("edge", "default"),
+			want:   "s4t-staging",
+		},
+		"SiteWithoutProviderConfig": {
+			reason: "A resource whose sites set no ProviderConfig should use the default.",
+			mg:     device("production", ""),
+			want:   DefaultName,
+		},
+		"NoSite": {
+			reason: "A resource without a site label should use the default.",
+			mg:     device("", ""),
+			want:   DefaultName,
+		},
+		"Site": {
+			reason: "A Site should inherit from its parent.",
+			mg:     site("edge", "staging", "default"),
+			want:   "s4t-staging",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := Name(context.Background(), kube, tc.mg)
+			if err != nil {
+				t.Fatalf("\n%s\nName(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nName(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestInherit(t *testing.T) {
+	owner := &v1alpha1.FleetInjection{}
+	owner.SetProviderConfigReference(&xpv1.Reference{Name: "s4t-fleet"})
+
+	type want struct {
+		ref  *xpv1.Reference
+		site string
+	}
+
+	cases := map[string]struct {
+		reason string
+		d      *v1alpha1.Device
+		want   want
+	}{
+		"Explicit": {
+			reason: "A child should use its device's own ProviderConfig.",
+			d:      device("edge", "s4t-edge"),
+			want:   want{ref: &xpv1.Reference{Name: "s4t-edge"}},
+		},
+		"Site": {
+			reason: "A child of a device in a site should resolve through that site, not its owner.",
+			d:      device("edge", "default"),
+			want:   want{ref: &xpv1.Reference{Name: placeholder}, site: "edge"},
+		},
+		"NoSite": {
+			reason: "A child of a device without a site should use its owner's ProviderConfig.",
+			d:      device("", ""),
+			want:   want{ref: &xpv1.Reference{Name: "s4t-fleet"}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			child := &v1alpha1.BoardPluginInjection{}
+			Inherit(child, owner, tc.d)
+			got := want{ref: child.GetProviderConfigReference(), site: child.GetLabels()[v1alpha1.LabelSite]}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nInherit(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestTrack(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := apis.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	kube := fake.NewClientBuilder().WithScheme(s).WithObjects(
+		site("edge", "", "s4t-edge"),
+	).Build()
+
+	d := device("edge", "default")
+	d.SetUID("board")
+	d.SetGroupVersionKind(v1alpha1.DeviceGroupVersionKind)
+	if err := NewTracker(kube).Track(context.Background(), d); err != nil {
+		t.Fatalf("Track(...): unexpected error: %v", err)
+	}
+
+	pcu := &apisv1alpha1.ProviderConfigUsage{}
+	if err := kube.Get(context.Background(), types.NamespacedName{Name: "board"}, pcu); err != nil {
+		t.Fatalf("Get(...): unexpected error: %v", err)
+	}
+	want := xpv1.Reference{Name: "s4t-edge"}
+	if diff := cmp.Diff(want, pcu.GetProviderConfigReference()); diff != "" {
+		t.Errorf("Track(...): a resource should be recorded as using the ProviderConfig it resolves to: -want, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff("s4t-edge", pcu.GetLabels()[xpv1.LabelKeyProviderName]); diff != "" {
+		t.Errorf("Track(...): -want label, +got:\n%s", diff)
+	}
+}
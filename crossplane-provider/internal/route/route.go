@@ -0,0 +1,231 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package route exposes board endpoints through the cluster's ingress. It
+// generates an Istio VirtualService when the Istio CRDs are installed and a
+// Kubernetes Ingress otherwise, controlled by the managed resource so that
+// it is garbage collected with it.
+package route
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/crossplane/provider-s4t/apis/iot/v1alpha1"
+	"github.com/crossplane/provider-s4t/internal/names"
+)
+
+const (
+	errNoHosts       = "route has no hosts"
+	errNoBackend     = "route has no backend port"
+	errMapping       = "cannot discover the VirtualService API"
+	errApply         = "cannot apply %s %s/%s"
+	errRemove        = "cannot delete %s %s/%s"
+	errNotControlled = "%s %s/%s exists and is not controlled by %s %q"
+
+	// DefaultNamespace is where routes are generated unless they name a
+	// namespace. The Stack4Things services run in it.
+	DefaultNamespace = "default"
+
+	// LabelOwner is set on generated objects to the name of the managed
+	// resource they route to.
+	LabelOwner = "iot.s4t.crossplane.io/route-owner"
+
+	kindVirtualService = "VirtualService"
+	kindIngress        = "Ingress"
+)
+
+var (
+	virtualService = schema.GroupKind{Group: "networking.istio.io", Kind: kindVirtualService}
+	ingress        = schema.GroupVersionKind{Group: "networking.k8s.io", Version: "v1", Kind: kindIngress}
+)
+
+// A Target is the board endpoint a Route forwards to.
+type Target struct {
+	// Hosts are used when the Route names none.
+	Hosts []string
+
+	// Service and Port are used unless the Route names a backend.
+	Service string
+	Port    int32
+
+	// TLS passes TLS through to the backend, matched on SNI, instead of
+	// routing HTTP. Only VirtualServices support it.
+	TLS bool
+}
+
+// Apply creates or updates the object generated for r, controlled by owner
+// of kind gvk and named after both, so that owners of different kinds never
+// share one. An existing object owner does not control is left alone and
+// reported. The object previously generated, as recorded in prev, is deleted
+// if it is of another kind or lives elsewhere.
+func Apply(ctx context.Context, kube client.Client, owner resource.Managed, gvk schema.GroupVersionKind, r *v1alpha1.Route, t Target, prev *v1alpha1.RouteObservation) (*v1alpha1.RouteObservation, error) {
+	hosts := r.Hosts
+	if len(hosts) == 0 {
+		hosts = t.Hosts
+	}
+	if len(hosts) == 0 {
+		return nil, errors.New(errNoHosts)
+	}
+	if b := r.Backend; b != nil {
+		t.Service = b.Service
+		if b.Port != 0 {
+			t.Port = b.Port
+		}
+	}
+	if t.Port == 0 {
+		return nil, errors.New(errNoBackend)
+	}
+	ns := r.Namespace
+	if ns == "" {
+		ns = DefaultNamespace
+	}
+	path := r.Path
+	if path == "" {
+		path = "/"
+	}
+
+	u := &unstructured.Unstructured{}
+	mapping, err := kube.RESTMapper().RESTMapping(virtualService)
+	switch {
+	case err == nil:
+		u.SetGroupVersionKind(mapping.GroupVersionKind)
+	case kmeta.IsNoMatchError(err):
+		u.SetGroupVersionKind(ingress)
+	default:
+		return nil, errors.Wrap(err, errMapping)
+	}
+	u.SetName(names.Child(strings.ToLower(gvk.Kind), owner.GetName()))
+	u.SetNamespace(ns)
+
+	obs := &v1alpha1.RouteObservation{
+		Kind:       u.GetKind(),
+		APIVersion: u.GetAPIVersion(),
+		Name:       u.GetName(),
+		Namespace:  u.GetNamespace(),
+		Hosts:      hosts,
+		Backend:    fmt.Sprintf("%s:%d", t.Service, t.Port),
+	}
+	if prev != nil && (prev.Kind != obs.Kind || prev.Name != obs.Name || prev.Namespace != obs.Namespace) {
+		if err := Remove(ctx, kube, prev); err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = controllerutil.CreateOrUpdate(ctx, kube, u, func() error {
+		if u.GetResourceVersion() != "" && !metav1.IsControlledBy(u, owner) {
+			return errors.Errorf(errNotControlled, u.GetKind(), u.GetNamespace(), u.GetName(), gvk.Kind, owner.GetName())
+		}
+		meta.AddLabels(u, map[string]string{LabelOwner: owner.GetName()})
+		meta.AddAnnotations(u, r.Annotations)
+		meta.AddOwnerReference(u, meta.AsController(meta.TypedReferenceTo(owner, gvk)))
+		if u.GetKind() == kindVirtualService {
+			return unstructured.SetNestedField(u.Object, virtualServiceSpec(hosts, r.Gateways, path, t), "spec")
+		}
+		return unstructured.SetNestedField(u.Object, ingressSpec(hosts, r.IngressClassName, path, t), "spec")
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, errApply, u.GetKind(), u.GetNamespace(), u.GetName())
+	}
+	return obs, nil
+}
+
+// Remove deletes the object recorded in obs. It is not an error for the
+// object, or its API, to be gone already.
+func Remove(ctx context.Context, kube client.Client, obs *v1alpha1.RouteObservation) error {
+	if obs == nil {
+		return nil
+	}
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion(obs.APIVersion)
+	u.SetKind(obs.Kind)
+	u.SetName(obs.Name)
+	u.SetNamespace(obs.Namespace)
+	if err := kube.Delete(ctx, u); client.IgnoreNotFound(err) != nil && !kmeta.IsNoMatchError(err) {
+		return errors.Wrapf(err, errRemove, obs.Kind, obs.Namespace, obs.Name)
+	}
+	return nil
+}
+
+func virtualServiceSpec(hosts, gateways []string, path string, t Target) map[string]interface{} {
+	destination := map[string]interface{}{
+		"destination": map[string]interface{}{
+			"host": t.Service,
+			"port": map[string]interface{}{"number": int64(t.Port)},
+		},
+	}
+	spec := map[string]interface{}{"hosts": list(hosts)}
+	if len(gateways) > 0 {
+		spec["gateways"] = list(gateways)
+	}
+	if t.TLS {
+		spec["tls"] = []interface{}{map[string]interface{}{
+			"match": []interface{}{map[string]interface{}{"sniHosts": list(hosts)}},
+			"route": []interface{}{destination},
+		}}
+		return spec
+	}
+	spec["http"] = []interface{}{map[string]interface{}{
+		"match": []interface{}{map[string]interface{}{"uri": map[string]interface{}{"prefix": path}}},
+		"route": []interface{}{destination},
+	}}
+	return spec
+}
+
+func ingressSpec(hosts []string, class *string, path string, t Target) map[string]interface{} {
+	rules := make([]interface{}, 0, len(hosts))
+	for _, h := range hosts {
+		rules = append(rules, map[string]interface{}{
+			"host": h,
+			"http": map[string]interface{}{
+				"paths": []interface{}{map[string]interface{}{
+					"path":     path,
+					"pathType": "Prefix",
+					"backend": map[string]interface{}{
+						"service": map[string]interface{}{
+							"name": t.Service,
+							"port": map[string]interface{}{"number": int64(t.Port)},
+						},
+					},
+				}},
+			},
+		})
+	}
+	spec := map[string]interface{}{"rules": rules}
+	if class != nil {
+		spec["ingressClassName"] = *class
+	}
+	return spec
+}
+
+func list(s []string) []interface{} {
+	out := make([]interface{}, 0, len(s))
+	for _, v := range s {
+		out = append(out, v)
+	}
+	return out
+}
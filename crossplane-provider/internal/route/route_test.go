@@ -0,0 +1,164 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package route
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/crossplane/provider-s4t/apis/iot/v1alpha1"
+	"github.com/crossplane/provider-s4t/internal/names"
+)
+
+func mapper(istio bool) kmeta.RESTMapper {
+	vs := virtualService.WithVersion("v1beta1")
+	m := kmeta.NewDefaultRESTMapper([]schema.GroupVersion{ingress.GroupVersion(), vs.GroupVersion()})
+	m.Add(ingress, kmeta.RESTScopeNamespace)
+	if istio {
+		m.Add(vs, kmeta.RESTScopeNamespace)
+	}
+	return m
+}
+
+func get(t *testing.T, kube client.Client, obs *v1alpha1.RouteObservation) *unstructured.Unstructured {
+	t.Helper()
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion(obs.APIVersion)
+	u.SetKind(obs.Kind)
+	if err := kube.Get(context.Background(), types.NamespacedName{Namespace: obs.Namespace, Name: obs.Name}, u); err != nil {
+		t.Fatalf("Get %s: %v", obs.Kind, err)
+	}
+	return u
+}
+
+func TestApply(t *testing.T) {
+	owner := &v1alpha1.BoardServiceInjection{ObjectMeta: metav1.ObjectMeta{Name: "ssh", UID: "uid"}}
+	r := &v1alpha1.Route{Hosts: []string{"ssh.example.org"}}
+	target := Target{Service: "iotronic-wstun", Port: 50024}
+	ctx := context.Background()
+
+	kube := fake.NewClientBuilder().WithRESTMapper(mapper(false)).Build()
+	ing, err := Apply(ctx, kube, owner, v1alpha1.BoardServiceInjectionGroupVersionKind, r, target, nil)
+	if err != nil {
+		t.Fatalf("Apply without Istio: %v", err)
+	}
+	want := &v1alpha1.RouteObservation{
+		Kind:       "Ingress",
+		APIVersion: "networking.k8s.io/v1",
+		Name:       names.Child("boardserviceinjection", "ssh"),
+		Namespace:  DefaultNamespace,
+		Hosts:      []string{"ssh.example.org"},
+		Backend:    "iotronic-wstun:50024",
+	}
+	if diff := cmp.Diff(want, ing); diff != "" {
+		t.Errorf("Apply without Istio: -want, +got:\n%s", diff)
+	}
+	u := get(t, kube, ing)
+	if refs := u.GetOwnerReferences(); len(refs) != 1 || refs[0].UID != "uid" || refs[0].Controller == nil || !*refs[0].Controller {
+		t.Errorf("Ingress owner references: want the BoardServiceInjection as controller, got %+v", refs)
+	}
+	if rules, _, _ := unstructured.NestedSlice(u.Object, "spec", "rules"); len(rules) != 1 {
+		t.Errorf("Ingress rules: want 1, got %d", len(rules))
+	}
+
+	// Installing Istio replaces the Ingress with a VirtualService.
+	kube = fake.NewClientBuilder().WithRESTMapper(mapper(true)).WithObjects(u).Build()
+	vs, err := Apply(ctx, kube, owner, v1alpha1.BoardServiceInjectionGroupVersionKind, r, target, ing)
+	if err != nil {
+		t.Fatalf("Apply with Istio: %v", err)
+	}
+	if vs.Kind != "VirtualService" || vs.APIVersion != "networking.istio.io/v1beta1" {
+		t.Errorf("Apply with Istio: want a networking.istio.io/v1beta1 VirtualService, got %s %s", vs.APIVersion, vs.Kind)
+	}
+	hosts, _, _ := unstructured.NestedStringSlice(get(t, kube, vs).Object, "spec", "hosts")
+	if diff := cmp.Diff(r.Hosts, hosts); diff != "" {
+		t.Errorf("VirtualService hosts: -want, +got:\n%s", diff)
+	}
+	if err := kube.Get(ctx, types.NamespacedName{Namespace: ing.Namespace, Name: ing.Name}, u); !kerrors.IsNotFound(err) {
+		t.Errorf("Get Ingress after switching to Istio: want NotFound, got %v", err)
+	}
+
+	if err := Remove(ctx, kube, vs); err != nil {
+		t.Errorf("Remove: %v", err)
+	}
+	if err := Remove(ctx, kube, vs); err != nil {
+		t.Errorf("Remove again: %v", err)
+	}
+}
+
+func TestApplyErrors(t *testing.T) {
+	owner := &v1alpha1.Webservice{ObjectMeta: metav1.ObjectMeta{Name: "ws"}}
+	kube := fake.NewClientBuilder().WithRESTMapper(mapper(false)).Build()
+	gvk := v1alpha1.WebserviceGroupVersionKind
+
+	cases := map[string]struct {
+		r *v1alpha1.Route
+		t Target
+	}{
+		"NoHosts":   {r: &v1alpha1.Route{}, t: Target{Service: "svc", Port: 80}},
+		"NoBackend": {r: &v1alpha1.Route{Hosts: []string{"a"}}, t: Target{Service: "svc"}},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Apply(context.Background(), kube, owner, gvk, tc.r, tc.t, nil); err == nil {
+				t.Error("Apply: want error")
+			}
+		})
+	}
+}
+
+func TestApplyNotControlled(t *testing.T) {
+	ws := &v1alpha1.Webservice{ObjectMeta: metav1.ObjectMeta{Name: "ssh", UID: "ws"}}
+	bsi := &v1alpha1.BoardServiceInjection{ObjectMeta: metav1.ObjectMeta{Name: "ssh", UID: "bsi"}}
+	r := &v1alpha1.Route{Hosts: []string{"ssh.example.org"}}
+	target := Target{Service: "svc", Port: 80}
+	ctx := context.Background()
+	kube := fake.NewClientBuilder().WithRESTMapper(mapper(false)).Build()
+
+	// Owners of different kinds sharing a name get objects of their own.
+	a, err := Apply(ctx, kube, ws, v1alpha1.WebserviceGroupVersionKind, r, target, nil)
+	if err != nil {
+		t.Fatalf("Apply(Webservice): %v", err)
+	}
+	b, err := Apply(ctx, kube, bsi, v1alpha1.BoardServiceInjectionGroupVersionKind, r, target, nil)
+	if err != nil {
+		t.Fatalf("Apply(BoardServiceInjection): %v", err)
+	}
+	if a.Name == b.Name {
+		t.Errorf("Apply: a Webservice and a BoardServiceInjection named %q should not share %s %s", ws.GetName(), a.Kind, a.Name)
+	}
+
+	// An object of the same name controlled by something else is refused.
+	other := &v1alpha1.Webservice{ObjectMeta: metav1.ObjectMeta{Name: "ssh", UID: "other"}}
+	if _, err := Apply(ctx, kube, other, v1alpha1.WebserviceGroupVersionKind, r, target, nil); err == nil {
+		t.Error("Apply: want error for an object controlled by another owner")
+	}
+	u := get(t, kube, a)
+	if refs := u.GetOwnerReferences(); len(refs) != 1 || refs[0].UID != "ws" {
+		t.Errorf("Apply: the owner references of an object controlled by another owner must not change, got %+v", refs)
+	}
+}
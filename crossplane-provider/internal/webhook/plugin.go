@@ -0,0 +1,380 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/crossplane/provider-s4t/apis/iot/v1alpha1"
+	"github.com/crossplane/provider-s4t/internal/params"
+)
+
+const (
+	errNotPlugin               = "object is not a Plugin"
+	errNotBoardPluginInjection = "object is not a BoardPluginInjection"
+	errNotPluginSchedule       = "object is not a PluginSchedule"
+	errNotBoardProfile         = "object is not a BoardProfile"
+
+	// maxWarnings is how many resources a Plugin update warns of at most.
+	maxWarnings = 10
+)
+
+// +kubebuilder:webhook:path=/validate-iot-s4t-crossplane-io-v1alpha1-plugin,mutating=false,failurePolicy=fail,sideEffects=None,groups=iot.s4t.crossplane.io,resources=plugins,verbs=create;update,versions=v1alpha1,name=plugins.iot.s4t.crossplane.io,admissionReviewVersions=v1
+
+// pluginValidator rejects Plugins whose parameters schema cannot be used, or
+// whose default parameters or health check call parameters do not match it.
+// It warns of the injections, schedules and profiles a new schema no longer
+// admits, which the provider then reports as invalid.
+type pluginValidator struct {
+	kube client.Client
+}
+
+func (v *pluginValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	p, ok := obj.(*v1alpha1.Plugin)
+	if !ok {
+		return nil, errors.New(errNotPlugin)
+	}
+	if err := validatePlugin(p); err != nil {
+		return nil, err
+	}
+	return v.mismatched(ctx, p), nil
+}
+
+func (v *pluginValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	o, ok := oldObj.(*v1alpha1.Plugin)
+	if !ok {
+		return nil, errors.New(errNotPlugin)
+	}
+	p, ok := newObj.(*v1alpha1.Plugin)
+	if !ok {
+		return nil, errors.New(errNotPlugin)
+	}
+	if reflect.DeepEqual(pluginParameters(o), pluginParameters(p)) {
+		return nil, nil
+	}
+	if err := validatePlugin(p); err != nil {
+		return nil, err
+	}
+	if reflect.DeepEqual(o.Spec.ForProvider.ParametersSchema, p.Spec.ForProvider.ParametersSchema) {
+		return nil, nil
+	}
+	return v.mismatched(ctx, p), nil
+}
+
+func (v *pluginValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// pluginParameters returns the fields of a Plugin that validatePlugin checks.
+func pluginParameters(p *v1alpha1.Plugin) []interface{} {
+	fp := p.Spec.ForProvider
+	out := []interface{}{fp.ParametersSchema, fp.Parameters.Raw, fp.ParametersFrom}
+	if fp.Rollout != nil && fp.Rollout.HealthCheck.Call != nil {
+		out = append(out, fp.Rollout.HealthCheck.Call.Parameters.Raw)
+	}
+	return out
+}
+
+func validatePlugin(p *v1alpha1.Plugin) error {
+	fp := p.Spec.ForProvider
+	if fp.ParametersSchema == nil {
+		return nil
+	}
+	fld := field.NewPath("spec", "forProvider")
+	s, err := params.NewSchema(fp.ParametersSchema.Raw)
+	if err != nil {
+		return invalid(v1alpha1.PluginGroupVersionKind.GroupKind(), p.GetName(), field.ErrorList{field.Invalid(fld.Child("parametersSchema"), field.OmitValueType{}, err.Error())})
+	}
+	errs := s.ValidateSpec(fld.Child("parameters"), fp.Parameters.Raw, fp.ParametersFrom)
+	if fp.Rollout != nil && fp.Rollout.HealthCheck.Call != nil && len(fp.Rollout.HealthCheck.Call.Parameters.Raw) > 0 {
+		errs = append(errs, s.ValidateSpec(fld.Child("rollout", "healthCheck", "call", "parameters"), fp.Rollout.HealthCheck.Call.Parameters.Raw, nil)...)
+	}
+	return invalid(v1alpha1.PluginGroupVersionKind.GroupKind(), p.GetName(), errs)
+}
+
+// mismatched warns of the BoardPluginInjections, PluginSchedules and
+// BoardProfiles of p whose parameters do not match its schema, which
+// validatePlugin found usable. They are not rejected: they were admitted
+// under the old schema and are reported invalid when next reconciled.
+func (v *pluginValidator) mismatched(ctx context.Context, p *v1alpha1.Plugin) admission.Warnings {
+	fp := p.Spec.ForProvider
+	if fp.ParametersSchema == nil {
+		return nil
+	}
+	s, err := params.NewSchema(fp.ParametersSchema.Raw)
+	if err != nil {
+		return nil
+	}
+	var warnings admission.Warnings
+	warn := func(kind, name string, errs field.ErrorList) {
+		if len(errs) == 0 {
+			return
+		}
+		if len(warnings) == maxWarnings {
+			warnings = append(warnings, "more resources do not match the parameters schema")
+		}
+		if len(warnings) < maxWarnings {
+			warnings = append(warnings, fmt.Sprintf("%s %s does not match the parameters schema: %s", kind, name, errs.ToAggregate()))
+		}
+	}
+
+	il := &v1alpha1.BoardPluginInjectionList{}
+	if err := v.kube.List(ctx, il); err != nil {
+		return append(warnings, fmt.Sprintf("cannot check BoardPluginInjections against the parameters schema: %v", err))
+	}
+	for i := range il.Items {
+		in := &il.Items[i]
+		ref := in.Spec.ForProvider.PluginRef
+		if (ref != nil && ref.Name == p.GetName()) || (ref == nil && fp.Uuid != "" && in.Spec.ForProvider.PluginUuid == fp.Uuid) {
+			warn(v1alpha1.BoardPluginInjectionKind, in.GetName(), injectionErrors(s, in))
+		}
+	}
+
+	sl := &v1alpha1.PluginScheduleList{}
+	if err := v.kube.List(ctx, sl); err != nil {
+		return append(warnings, fmt.Sprintf("cannot check PluginSchedules against the parameters schema: %v", err))
+	}
+	for i := range sl.Items {
+		if sc := &sl.Items[i]; sc.Spec.ForProvider.PluginRef.Name == p.GetName() {
+			warn(v1alpha1.PluginScheduleKind, sc.GetName(), scheduleErrors(s, sc))
+		}
+	}
+
+	pl := &v1alpha1.BoardProfileList{}
+	if err := v.kube.List(ctx, pl); err != nil {
+		return append(warnings, fmt.Sprintf("cannot check BoardProfiles against the parameters schema: %v", err))
+	}
+	for i := range pl.Items {
+		pr := &pl.Items[i]
+		var errs field.ErrorList
+		for j, item := range pr.Spec.ForProvider.Plugins {
+			if item.PluginRef == p.GetName() {
+				errs = append(errs, profilePluginErrors(s, j, item)...)
+			}
+		}
+		warn(v1alpha1.BoardProfileKind, pr.GetName(), errs)
+	}
+	return warnings
+}
+
+// +kubebuilder:webhook:path=/validate-iot-s4t-crossplane-io-v1alpha1-boardplugininjection,mutating=false,failurePolicy=fail,sideEffects=None,groups=iot.s4t.crossplane.io,resources=boardplugininjections,verbs=create;update,versions=v1alpha1,name=boardplugininjections.iot.s4t.crossplane.io,admissionReviewVersions=v1
+
+// injectionValidator rejects BoardPluginInjections whose parameters do not
+// match the schema of their Plugin.
+type injectionValidator struct {
+	kube client.Client
+}
+
+func (v *injectionValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	i, ok := obj.(*v1alpha1.BoardPluginInjection)
+	if !ok {
+		return nil, errors.New(errNotBoardPluginInjection)
+	}
+	return nil, v.validate(ctx, i)
+}
+
+func (v *injectionValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	o, ok := oldObj.(*v1alpha1.BoardPluginInjection)
+	if !ok {
+		return nil, errors.New(errNotBoardPluginInjection)
+	}
+	i, ok := newObj.(*v1alpha1.BoardPluginInjection)
+	if !ok {
+		return nil, errors.New(errNotBoardPluginInjection)
+	}
+	// Resolving the plugin reference sets pluginUuid; that alone must not
+	// block the update, nor the finalizer removal completing a deletion.
+	if bytes.Equal(o.Spec.ForProvider.Parameters.Raw, i.Spec.ForProvider.Parameters.Raw) &&
+		reflect.DeepEqual(o.Spec.ForProvider.ParametersFrom, i.Spec.ForProvider.ParametersFrom) &&
+		reflect.DeepEqual(o.Spec.ForProvider.PluginRef, i.Spec.ForProvider.PluginRef) &&
+		(i.Spec.ForProvider.PluginRef != nil || o.Spec.ForProvider.PluginUuid == i.Spec.ForProvider.PluginUuid) {
+		return nil, nil
+	}
+	return nil, v.validate(ctx, i)
+}
+
+func (v *injectionValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *injectionValidator) validate(ctx context.Context, i *v1alpha1.BoardPluginInjection) error {
+	fp := i.Spec.ForProvider
+	if len(fp.Parameters.Raw) == 0 && len(fp.ParametersFrom) == 0 {
+		return nil
+	}
+	name := ""
+	if fp.PluginRef != nil {
+		name = fp.PluginRef.Name
+	}
+	s, err := params.SchemaFor(ctx, v.kube, name, fp.PluginUuid)
+	if err != nil || s == nil {
+		return err
+	}
+	return invalid(v1alpha1.BoardPluginInjectionGroupVersionKind.GroupKind(), i.GetName(), injectionErrors(s, i))
+}
+
+// injectionErrors validates the parameters of i, if any, against s.
+func injectionErrors(s *params.Schema, i *v1alpha1.BoardPluginInjection) field.ErrorList {
+	fp := i.Spec.ForProvider
+	if len(fp.Parameters.Raw) == 0 && len(fp.ParametersFrom) == 0 {
+		return nil
+	}
+	return s.ValidateSpec(field.NewPath("spec", "forProvider", "parameters"), fp.Parameters.Raw, fp.ParametersFrom)
+}
+
+// +kubebuilder:webhook:path=/validate-iot-s4t-crossplane-io-v1alpha1-pluginschedule,mutating=false,failurePolicy=fail,sideEffects=None,groups=iot.s4t.crossplane.io,resources=pluginschedules,verbs=create;update,versions=v1alpha1,name=pluginschedules.iot.s4t.crossplane.io,admissionReviewVersions=v1
+
+// scheduleValidator rejects PluginSchedules whose Call parameters do not
+// match the schema of their Plugin.
+type scheduleValidator struct {
+	kube client.Client
+}
+
+func (v *scheduleValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	s, ok := obj.(*v1alpha1.PluginSchedule)
+	if !ok {
+		return nil, errors.New(errNotPluginSchedule)
+	}
+	return nil, v.validate(ctx, s)
+}
+
+func (v *scheduleValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	o, ok := oldObj.(*v1alpha1.PluginSchedule)
+	if !ok {
+		return nil, errors.New(errNotPluginSchedule)
+	}
+	s, ok := newObj.(*v1alpha1.PluginSchedule)
+	if !ok {
+		return nil, errors.New(errNotPluginSchedule)
+	}
+	if reflect.DeepEqual(o.Spec.ForProvider.Action, s.Spec.ForProvider.Action) && reflect.DeepEqual(o.Spec.ForProvider.PluginRef, s.Spec.ForProvider.PluginRef) {
+		return nil, nil
+	}
+	return nil, v.validate(ctx, s)
+}
+
+func (v *scheduleValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *scheduleValidator) validate(ctx context.Context, s *v1alpha1.PluginSchedule) error {
+	a := s.Spec.ForProvider.Action
+	if a.Type != v1alpha1.PluginScheduleCall || (a.Parameters == nil && len(a.ParametersFrom) == 0) {
+		return nil
+	}
+	ps, err := params.SchemaFor(ctx, v.kube, s.Spec.ForProvider.PluginRef.Name, "")
+	if err != nil || ps == nil {
+		return err
+	}
+	return invalid(v1alpha1.PluginScheduleGroupVersionKind.GroupKind(), s.GetName(), scheduleErrors(ps, s))
+}
+
+// scheduleErrors validates the parameters of the Call of s, if any, against
+// ps.
+func scheduleErrors(ps *params.Schema, s *v1alpha1.PluginSchedule) field.ErrorList {
+	a := s.Spec.ForProvider.Action
+	if a.Type != v1alpha1.PluginScheduleCall || (a.Parameters == nil && len(a.ParametersFrom) == 0) {
+		return nil
+	}
+	var raw []byte
+	if a.Parameters != nil {
+		raw = a.Parameters.Raw
+	}
+	return ps.ValidateSpec(field.NewPath("spec", "forProvider", "action", "parameters"), raw, a.ParametersFrom)
+}
+
+// +kubebuilder:webhook:path=/validate-iot-s4t-crossplane-io-v1alpha1-boardprofile,mutating=false,failurePolicy=fail,sideEffects=None,groups=iot.s4t.crossplane.io,resources=boardprofiles,verbs=create;update,versions=v1alpha1,name=boardprofiles.iot.s4t.crossplane.io,admissionReviewVersions=v1
+
+// profileValidator rejects BoardProfiles whose plugin parameters do not match
+// the schemas of their Plugins, rather than leaving every board's injection
+// to fail.
+type profileValidator struct {
+	kube client.Client
+}
+
+func (v *profileValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	p, ok := obj.(*v1alpha1.BoardProfile)
+	if !ok {
+		return nil, errors.New(errNotBoardProfile)
+	}
+	return nil, v.validate(ctx, p)
+}
+
+func (v *profileValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	o, ok := oldObj.(*v1alpha1.BoardProfile)
+	if !ok {
+		return nil, errors.New(errNotBoardProfile)
+	}
+	p, ok := newObj.(*v1alpha1.BoardProfile)
+	if !ok {
+		return nil, errors.New(errNotBoardProfile)
+	}
+	if reflect.DeepEqual(o.Spec.ForProvider.Plugins, p.Spec.ForProvider.Plugins) {
+		return nil, nil
+	}
+	return nil, v.validate(ctx, p)
+}
+
+func (v *profileValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *profileValidator) validate(ctx context.Context, p *v1alpha1.BoardProfile) error {
+	var errs field.ErrorList
+	for i, pl := range p.Spec.ForProvider.Plugins {
+		if len(pl.Parameters.Raw) == 0 && len(pl.ParametersFrom) == 0 {
+			continue
+		}
+		s, err := params.SchemaFor(ctx, v.kube, pl.PluginRef, "")
+		if err != nil {
+			return err
+		}
+		if s != nil {
+			errs = append(errs, profilePluginErrors(s, i, pl)...)
+		}
+	}
+	return invalid(v1alpha1.BoardProfileGroupVersionKind.GroupKind(), p.GetName(), errs)
+}
+
+// profilePluginErrors validates the parameters of pl, the plugin at index i
+// of a BoardProfile, if any, against s.
+func profilePluginErrors(s *params.Schema, i int, pl v1alpha1.BoardProfilePlugin) field.ErrorList {
+	if len(pl.Parameters.Raw) == 0 && len(pl.ParametersFrom) == 0 {
+		return nil
+	}
+	return s.ValidateSpec(field.NewPath("spec", "forProvider", "plugins").Index(i).Child("parameters"), pl.Parameters.Raw, pl.ParametersFrom)
+}
+
+// invalid returns an Invalid error for the object of kind gk named name,
+// listing errs, or nil if there are none.
+func invalid(gk schema.GroupKind, name string, errs field.ErrorList) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return kerrors.NewInvalid(gk, name, errs)
+}
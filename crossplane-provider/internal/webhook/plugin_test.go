@@ -0,0 +1,347 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/google/go-cmp/cmp"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/provider-s4t/apis/iot/v1alpha1"
+)
+
+const intervalSchema = `{"type":"object","properties":{"interval":{"type":"integer","minimum":1}},"additionalProperties":false}`
+
+func raw(s string) runtime.RawExtension {
+	return runtime.RawExtension{Raw: []byte(s)}
+}
+
+func plugin(parameters, schema string) *v1alpha1.Plugin {
+	p := &v1alpha1.Plugin{ObjectMeta: metav1.ObjectMeta{Name: "collector"}}
+	p.Spec.ForProvider.Uuid = "plugin-1"
+	p.Spec.ForProvider.Parameters = raw(parameters)
+	if schema != "" {
+		s := raw(schema)
+		p.Spec.ForProvider.ParametersSchema = &s
+	}
+	return p
+}
+
+func injection(name, pluginRef, parameters string) *v1alpha1.BoardPluginInjection {
+	i := &v1alpha1.BoardPluginInjection{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	i.Spec.ForProvider.PluginRef = &xpv1.Reference{Name: pluginRef}
+	i.Spec.ForProvider.State = ptr.To(v1alpha1.PluginRunning)
+	i.Spec.ForProvider.Parameters = raw(parameters)
+	return i
+}
+
+func schedule(name string, action v1alpha1.PluginScheduleActionType, parameters string) *v1alpha1.PluginSchedule {
+	s := &v1alpha1.PluginSchedule{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	s.Spec.ForProvider.PluginRef = xpv1.Reference{Name: "collector"}
+	s.Spec.ForProvider.Action = v1alpha1.PluginScheduleAction{Type: action, Parameters: ptr.To(raw(parameters))}
+	return s
+}
+
+func profile(name, parameters string) *v1alpha1.BoardProfile {
+	p := &v1alpha1.BoardProfile{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	p.Spec.ForProvider.Plugins = []v1alpha1.BoardProfilePlugin{
+		{PluginRef: "other", State: ptr.To(v1alpha1.PluginRunning), Parameters: raw(`{"interval":0}`)},
+		{PluginRef: "collector", State: ptr.To(v1alpha1.PluginRunning), Parameters: raw(parameters)},
+	}
+	return p
+}
+
+// finalized returns a copy of o with the managed resource finalizer, as the
+// provider's first reconcile leaves it.
+func finalized[T client.Object](o T) T {
+	c := o.DeepCopyObject().(T)
+	c.SetFinalizers([]string{"finalizer.managedresource.crossplane.io"})
+	return c
+}
+
+// fields returns the sorted field paths an Invalid error lists.
+func fields(err error) []string {
+	se := &kerrors.StatusError{}
+	if !errors.As(err, &se) || se.ErrStatus.Details == nil {
+		return nil
+	}
+	out := []string{}
+	for _, c := range se.ErrStatus.Details.Causes {
+		out = append(out, c.Field)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func TestPluginValidator(t *testing.T) {
+	mismatch := plugin(`{"interval":0}`, intervalSchema)
+
+	cases := map[string]struct {
+		reason string
+		old    *v1alpha1.Plugin
+		new    *v1alpha1.Plugin
+		want   []string
+	}{
+		"Valid": {
+			reason: "Default parameters matching the schema should be accepted.",
+			new:    plugin(`{"interval":5}`, intervalSchema),
+		},
+		"BadSchema": {
+			reason: "A schema that cannot be used should be rejected at its field.",
+			new:    plugin(`{}`, `{"properties": {"a": {"$ref": "#/definitions/a"}}}`),
+			want:   []string{"spec.forProvider.parametersSchema"},
+		},
+		"Mismatch": {
+			reason: "Default parameters not matching the schema should be rejected at their field.",
+			new:    mismatch,
+			want:   []string{"spec.forProvider.parameters.interval"},
+		},
+		"FinalizerOnlyUpdate": {
+			reason: "Updates leaving the parameters and schema alone should be accepted.",
+			old:    mismatch,
+			new:    finalized(mismatch),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			v := &pluginValidator{kube: newKube(t)}
+			var err error
+			if tc.old == nil {
+				_, err = v.ValidateCreate(context.Background(), tc.new)
+			} else {
+				_, err = v.ValidateUpdate(context.Background(), tc.old, tc.new)
+			}
+			if diff := cmp.Diff(tc.want, fields(err)); diff != "" {
+				t.Errorf("\n%s\nvalidate(...): -want fields, +got fields:\n%s\nerror: %v", tc.reason, diff, err)
+			}
+		})
+	}
+}
+
+func TestPluginValidatorWarnings(t *testing.T) {
+	kube := newKube(t,
+		injection("stale", "collector", `{"interval":0}`),
+		injection("fine", "collector", `{"interval":5}`),
+		injection("unrelated", "other", `{"interval":0}`),
+		schedule("stale", v1alpha1.PluginScheduleCall, `{"interval":"often"}`),
+		schedule("start", v1alpha1.PluginScheduleStart, `{"interval":0}`),
+		profile("stale", `{"interval":-1}`),
+	)
+	v := &pluginValidator{kube: kube}
+	ctx := context.Background()
+
+	// A new schema is checked against the resources of the plugin.
+	warnings, err := v.ValidateUpdate(ctx, plugin(`{}`, ""), plugin(`{}`, intervalSchema))
+	if err != nil {
+		t.Fatalf("ValidateUpdate: %v", err)
+	}
+	want := []string{
+		"BoardPluginInjection stale does not match the parameters schema: spec.forProvider.parameters.interval",
+		"PluginSchedule stale does not match the parameters schema: spec.forProvider.action.parameters.interval",
+		"BoardProfile stale does not match the parameters schema: spec.forProvider.plugins[1].parameters.interval",
+	}
+	if len(warnings) != len(want) {
+		t.Fatalf("ValidateUpdate: want %d warnings, got %q", len(want), warnings)
+	}
+	for i, w := range want {
+		if !strings.HasPrefix(warnings[i], w) {
+			t.Errorf("ValidateUpdate: want warning starting %q, got %q", w, warnings[i])
+		}
+	}
+
+	// Other changes to the plugin do not warn.
+	warnings, err = v.ValidateUpdate(ctx, plugin(`{}`, intervalSchema), plugin(`{"interval":5}`, intervalSchema))
+	if err != nil || len(warnings) != 0 {
+		t.Errorf("ValidateUpdate without schema change: want no warnings, got %q, %v", warnings, err)
+	}
+}
+
+func TestInjectionValidator(t *testing.T) {
+	kube := newKube(t, plugin(`{}`, intervalSchema))
+	mismatch := injection("i", "collector", `{"interval":0}`)
+	byUUID := mismatch.DeepCopy()
+	byUUID.Spec.ForProvider.PluginRef = nil
+	byUUID.Spec.ForProvider.PluginUuid = "plugin-1"
+	resolved := mismatch.DeepCopy()
+	resolved.Spec.ForProvider.PluginUuid = "plugin-1"
+
+	cases := map[string]struct {
+		reason string
+		old    *v1alpha1.BoardPluginInjection
+		new    *v1alpha1.BoardPluginInjection
+		want   []string
+	}{
+		"Valid": {
+			reason: "Parameters matching the schema of the Plugin should be accepted.",
+			new:    injection("i", "collector", `{"interval":5}`),
+		},
+		"Mismatch": {
+			reason: "Parameters not matching the schema should be rejected at their field.",
+			new:    mismatch,
+			want:   []string{"spec.forProvider.parameters.interval"},
+		},
+		"ByUUID": {
+			reason: "An injection naming its Plugin by UUID should be checked against its schema.",
+			new:    byUUID,
+			want:   []string{"spec.forProvider.parameters.interval"},
+		},
+		"NoSchema": {
+			reason: "Injections of a Plugin without a schema are not checked.",
+			new:    injection("i", "other", `{"interval":0}`),
+		},
+		"ParametersUpdate": {
+			reason: "Changed parameters should be checked.",
+			old:    injection("i", "collector", `{"interval":5}`),
+			new:    mismatch,
+			want:   []string{"spec.forProvider.parameters.interval"},
+		},
+		"ResolvedUpdate": {
+			reason: "Resolving the plugin reference should be accepted even if the parameters no longer match.",
+			old:    mismatch,
+			new:    resolved,
+		},
+		"FinalizerOnlyUpdate": {
+			reason: "Finalizer changes should be accepted even if the parameters no longer match.",
+			old:    mismatch,
+			new:    finalized(mismatch),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			v := &injectionValidator{kube: kube}
+			var err error
+			if tc.old == nil {
+				_, err = v.ValidateCreate(context.Background(), tc.new)
+			} else {
+				_, err = v.ValidateUpdate(context.Background(), tc.old, tc.new)
+			}
+			if diff := cmp.Diff(tc.want, fields(err)); diff != "" {
+				t.Errorf("\n%s\nvalidate(...): -want fields, +got fields:\n%s\nerror: %v", tc.reason, diff, err)
+			}
+		})
+	}
+}
+
+func TestScheduleValidator(t *testing.T) {
+	kube := newKube(t, plugin(`{}`, intervalSchema))
+	mismatch := schedule("s", v1alpha1.PluginScheduleCall, `{"interval":0}`)
+	rescheduled := mismatch.DeepCopy()
+	rescheduled.Spec.ForProvider.Schedule = "@daily"
+
+	cases := map[string]struct {
+		reason string
+		old    *v1alpha1.PluginSchedule
+		new    *v1alpha1.PluginSchedule
+		want   []string
+	}{
+		"Valid": {
+			reason: "Call parameters matching the schema should be accepted.",
+			new:    schedule("s", v1alpha1.PluginScheduleCall, `{"interval":5}`),
+		},
+		"Mismatch": {
+			reason: "Call parameters not matching the schema should be rejected at their field.",
+			new:    mismatch,
+			want:   []string{"spec.forProvider.action.parameters.interval"},
+		},
+		"NotACall": {
+			reason: "Only Call passes parameters, so other actions are not checked.",
+			new:    schedule("s", v1alpha1.PluginScheduleStart, `{"interval":0}`),
+		},
+		"UnchangedUpdate": {
+			reason: "Updates leaving the action and plugin alone should be accepted.",
+			old:    mismatch,
+			new:    rescheduled,
+		},
+		"FinalizerOnlyUpdate": {
+			reason: "Finalizer changes should be accepted even if the parameters no longer match.",
+			old:    mismatch,
+			new:    finalized(mismatch),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			v := &scheduleValidator{kube: kube}
+			var err error
+			if tc.old == nil {
+				_, err = v.ValidateCreate(context.Background(), tc.new)
+			} else {
+				_, err = v.ValidateUpdate(context.Background(), tc.old, tc.new)
+			}
+			if diff := cmp.Diff(tc.want, fields(err)); diff != "" {
+				t.Errorf("\n%s\nvalidate(...): -want fields, +got fields:\n%s\nerror: %v", tc.reason, diff, err)
+			}
+		})
+	}
+}
+
+func TestProfileValidator(t *testing.T) {
+	kube := newKube(t, plugin(`{}`, intervalSchema))
+	mismatch := profile("p", `{"interval":0}`)
+	withService := mismatch.DeepCopy()
+	withService.Spec.ForProvider.Services = []v1alpha1.BoardProfileService{{ServiceRef: "ssh"}}
+
+	cases := map[string]struct {
+		reason string
+		old    *v1alpha1.BoardProfile
+		new    *v1alpha1.BoardProfile
+		want   []string
+	}{
+		"Valid": {
+			reason: "Plugin parameters matching their schemas should be accepted.",
+			new:    profile("p", `{"interval":5}`),
+		},
+		"Mismatch": {
+			reason: "Plugin parameters not matching their schema should be rejected at the field of their item.",
+			new:    mismatch,
+			want:   []string{"spec.forProvider.plugins[1].parameters.interval"},
+		},
+		"UnchangedUpdate": {
+			reason: "Updates leaving the plugins alone should be accepted.",
+			old:    mismatch,
+			new:    withService,
+		},
+		"FinalizerOnlyUpdate": {
+			reason: "Finalizer changes should be accepted even if the parameters no longer match.",
+			old:    mismatch,
+			new:    finalized(mismatch),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			v := &profileValidator{kube: kube}
+			var err error
+			if tc.old == nil {
+				_, err = v.ValidateCreate(context.Background(), tc.new)
+			} else {
+				_, err = v.ValidateUpdate(context.Background(), tc.old, tc.new)
+			}
+			if diff := cmp.Diff(tc.want, fields(err)); diff != "" {
+				t.Errorf("\n%s\nvalidate(...): -want fields, +got fields:\n%s\nerror: %v", tc.reason, diff, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/crossplane/provider-s4t/apis/iot/v1alpha1"
+	"github.com/crossplane/provider-s4t/internal/ipam"
+)
+
+const errNotPort = "object is not a Port"
+
+// +kubebuilder:webhook:path=/validate-iot-s4t-crossplane-io-v1alpha1-port,mutating=false,failurePolicy=fail,sideEffects=None,groups=iot.s4t.crossplane.io,resources=ports,verbs=create;update,versions=v1alpha1,name=ports.iot.s4t.crossplane.io,admissionReviewVersions=v1
+
+// portValidator rejects Ports whose address lies outside the IPPool serving
+// their network, is reserved, or is used by another Port.
+type portValidator struct {
+	kube client.Client
+}
+
+func (v *portValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	p, ok := obj.(*v1alpha1.Port)
+	if !ok {
+		return nil, errors.New(errNotPort)
+	}
+	return nil, ipam.Validate(ctx, v.kube, p)
+}
+
+func (v *portValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	o, ok := oldObj.(*v1alpha1.Port)
+	if !ok {
+		return nil, errors.New(errNotPort)
+	}
+	p, ok := newObj.(*v1alpha1.Port)
+	if !ok {
+		return nil, errors.New(errNotPort)
+	}
+	// Status and metadata updates, including the finalizer removal that
+	// completes a deletion, must not be blocked by a pool that changed since.
+	if sameAddress(o.Spec.ForProvider, p.Spec.ForProvider) {
+		return nil, nil
+	}
+	return nil, ipam.Validate(ctx, v.kube, p)
+}
+
+func (v *portValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// sameAddress reports whether two versions of a Port request the same
+// address on the same network, whether named or referenced.
+func sameAddress(a, b v1alpha1.PortParameters) bool {
+	return a.Ip == b.Ip && a.Network == b.Network && reflect.DeepEqual(a.NetworkRef, b.NetworkRef)
+}
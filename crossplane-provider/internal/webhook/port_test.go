@@ -0,0 +1,116 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/crossplane/provider-s4t/apis/iot/v1alpha1"
+)
+
+func newKube(t *testing.T, objs ...client.Object) client.Client {
+	t.Helper()
+	s := runtime.NewScheme()
+	if err := v1alpha1.SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	return fake.NewClientBuilder().WithScheme(s).WithObjects(objs...).Build()
+}
+
+func port(name, network, networkRef, ip string) *v1alpha1.Port {
+	p := &v1alpha1.Port{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	p.Spec.ForProvider.Network = network
+	if networkRef != "" {
+		p.Spec.ForProvider.NetworkRef = &xpv1.Reference{Name: networkRef}
+	}
+	p.Spec.ForProvider.Ip = ip
+	return p
+}
+
+func TestPortValidator(t *testing.T) {
+	byName := &v1alpha1.IPPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "net0"},
+		Spec:       v1alpha1.IPPoolSpec{Network: "net0", CIDR: "10.0.0.0/29", Gateway: "10.0.0.1"},
+	}
+	byRef := &v1alpha1.IPPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "lan"},
+		Spec:       v1alpha1.IPPoolSpec{NetworkRef: &xpv1.Reference{Name: "lan"}, CIDR: "10.1.0.0/29"},
+	}
+	kube := newKube(t, byName, byRef, port("a", "net0", "", "10.0.0.3"))
+
+	finalized := port("b", "net0", "", "10.0.0.3")
+	finalized.SetFinalizers([]string{"finalizer.managedresource.crossplane.io"})
+
+	cases := map[string]struct {
+		reason  string
+		old     *v1alpha1.Port
+		new     *v1alpha1.Port
+		wantErr bool
+	}{
+		"Free": {
+			reason: "A free address inside the pool should be accepted.",
+			new:    port("b", "net0", "", "10.0.0.4"),
+		},
+		"Taken": {
+			reason:  "An address used by another Port should be rejected.",
+			new:     port("b", "net0", "", "10.0.0.3"),
+			wantErr: true,
+		},
+		"Outside": {
+			reason:  "An address outside the pool should be rejected.",
+			new:     port("b", "net0", "", "10.9.0.3"),
+			wantErr: true,
+		},
+		"NoPool": {
+			reason: "Ports on a network without a pool are not checked.",
+			new:    port("b", "other", "", "10.9.0.3"),
+		},
+		"MetadataOnlyUpdate": {
+			reason: "Updates leaving the address and network alone, such as finalizer changes, should be accepted even if the address now conflicts.",
+			old:    port("b", "net0", "", "10.0.0.3"),
+			new:    finalized,
+		},
+		"NetworkRefUpdate": {
+			reason:  "Changing only networkRef should validate the address against the pool of the new network.",
+			old:     port("b", "", "wan", "10.0.0.4"),
+			new:     port("b", "", "lan", "10.0.0.4"),
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			v := &portValidator{kube: kube}
+			var err error
+			if tc.old == nil {
+				_, err = v.ValidateCreate(context.Background(), tc.new)
+			} else {
+				_, err = v.ValidateUpdate(context.Background(), tc.old, tc.new)
+			}
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("\n%s\nvalidate(...): want error %t, got %v", tc.reason, tc.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package webhook validates S4T resources on admission, catching conflicts
+// that a single object's schema cannot express, and plugin parameters that do
+// not match the schema their Plugin declares.
+package webhook
+
+import (
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/crossplane/provider-s4t/apis/iot/v1alpha1"
+)
+
+// Setup registers the validating webhooks with the manager's webhook server.
+func Setup(mgr ctrl.Manager) error {
+	if err := ctrl.NewWebhookManagedBy(mgr).
+		For(&v1alpha1.Port{}).
+		WithValidator(&portValidator{kube: mgr.GetClient()}).
+		Complete(); err != nil {
+		return err
+	}
+	if err := ctrl.NewWebhookManagedBy(mgr).
+		For(&v1alpha1.Plugin{}).
+		WithValidator(&pluginValidator{kube: mgr.GetClient()}).
+		Complete(); err != nil {
+		return err
+	}
+	if err := ctrl.NewWebhookManagedBy(mgr).
+		For(&v1alpha1.BoardPluginInjection{}).
+		WithValidator(&injectionValidator{kube: mgr.GetClient()}).
+		Complete(); err != nil {
+		return err
+	}
+	if err := ctrl.NewWebhookManagedBy(mgr).
+		For(&v1alpha1.PluginSchedule{}).
+		WithValidator(&scheduleValidator{kube: mgr.GetClient()}).
+		Complete(); err != nil {
+		return err
+	}
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&v1alpha1.BoardProfile{}).
+		WithValidator(&profileValidator{kube: mgr.GetClient()}).
+		Complete()
+}